@@ -35,8 +35,10 @@ import (
 	"github.com/go-enjin/be/pkg/factories/tokens"
 	"github.com/go-enjin/be/pkg/feature"
 	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/lang"
 	"github.com/go-enjin/be/pkg/lang/catalog"
 	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/log/filelogwriter"
 	"github.com/go-enjin/be/pkg/net/headers/policy/csp"
 	"github.com/go-enjin/be/pkg/net/headers/policy/permissions"
 	"github.com/go-enjin/be/pkg/profiling"
@@ -62,6 +64,12 @@ type Enjin struct {
 
 	debug bool
 
+	shutdownTimeout time.Duration
+	gracefulRestart bool
+	restartTimeout  time.Duration
+	shutdownOnce    sync.Once
+	shuttingDown    bool
+
 	catalog catalog.Catalog
 	locales []language.Tag
 
@@ -129,6 +137,10 @@ func newEnjin(eb *EnjinBuilder) *Enjin {
 	e.Emit(signals.PreNewEnjin, feature.EnjinTag.String(), interface{}(e).(feature.Internals))
 
 	e.initConsoles()
+	e.initExport()
+	e.initCheckLinks()
+	e.initLint()
+	e.initTranslationCoverage()
 	e.setupFeatures()
 	e.ReloadLocales()
 
@@ -164,11 +176,6 @@ func (e *Enjin) action(ctx *cli.Context) (err error) {
 		sigint := make(chan os.Signal, 1)
 		signal.Notify(sigint, syscall.SIGINT, syscall.SIGTERM)
 		<-sigint
-		for _, enjin := range e.eb.enjins {
-			for _, f := range enjin.Features().List() {
-				f.Shutdown()
-			}
-		}
 		e.Shutdown()
 	}()
 
@@ -189,6 +196,12 @@ func (e *Enjin) action(ctx *cli.Context) (err error) {
 			err = nil
 		}
 	}
+
+	if e.ShuttingDown() {
+		// the listener stops before the features are shutdown, wait for
+		// Shutdown to complete (which exits the process)
+		select {}
+	}
 	return
 }
 
@@ -240,6 +253,9 @@ func (e *Enjin) setupInternals(ctx *cli.Context) (err error) {
 func (e *Enjin) SetupRootEnjin(ctx *cli.Context) (err error) {
 
 	e.debug = ctx.Bool("debug")
+	e.shutdownTimeout = ctx.Duration("shutdown-timeout")
+	e.gracefulRestart = ctx.Bool("graceful-restart")
+	e.restartTimeout = ctx.Duration("restart-timeout")
 	e.prefix = ctx.String("prefix")
 	e.prefix = strings.ToLower(e.prefix)
 	e.production = e.prefix == "" || e.prefix == "prd"
@@ -248,6 +264,15 @@ func (e *Enjin) SetupRootEnjin(ctx *cli.Context) (err error) {
 		e.debug = false
 	}
 
+	if format := strings.ToLower(ctx.String("log-format")); format != "" {
+		if v, ok := log.Formats[format]; ok {
+			log.Config.LoggingFormat = v
+			log.Config.Apply()
+		} else {
+			log.FatalF("invalid log-format: %v", format)
+		}
+	}
+
 	lvl := strings.ToLower(ctx.String("log-level"))
 	if v, ok := log.Levels[lvl]; ok {
 		log.Config.LogLevel = v
@@ -265,6 +290,40 @@ func (e *Enjin) SetupRootEnjin(ctx *cli.Context) (err error) {
 		}
 	}
 
+	if file := ctx.String("log-file"); file != "" {
+		var flw *filelogwriter.FileLogWriter
+		if flw, err = filelogwriter.NewFileLogWriter(file); err != nil {
+			err = fmt.Errorf("error setting up log-file: %v", err)
+			return
+		}
+		flw.SetMaxSize(int64(ctx.Int("log-file-max-size")) * 1024 * 1024).
+			SetRotateInterval(ctx.Duration("log-file-rotate")).
+			SetMaxBackups(ctx.Int("log-file-max-backups")).
+			SetMaxAge(ctx.Duration("log-file-max-age")).
+			SetCompress(ctx.Bool("log-file-compress")).
+			ReopenOnSignal()
+		log.Config.Output = flw
+		log.Config.Apply()
+	}
+
+	for _, spec := range ctx.StringSlice("lang-fallback") {
+		var tag language.Tag
+		var fallbacks []language.Tag
+		if tag, fallbacks, err = lang.ParseFallbackChain(spec); err != nil {
+			err = fmt.Errorf("error parsing lang-fallback: %v", err)
+			return
+		}
+		e.eb.fallbacks.Set(tag, fallbacks...)
+		for _, eb := range e.eb.enjins {
+			if _, present := eb.fallbacks[tag]; !present {
+				eb.fallbacks.Set(tag, fallbacks...)
+			}
+		}
+	}
+	if ctx.IsSet("lang-fallback") {
+		e.ReloadLocales()
+	}
+
 	if err = e.setupInternals(ctx); err != nil {
 		return
 	}
@@ -350,6 +409,8 @@ func (e *Enjin) startupRootService(ctx *cli.Context) (err error) {
 		}
 	}()
 
+	e.startGracefulRestartHandler()
+
 	if len(e.eb.enjins) == 0 {
 		return e.eb.fServiceListener.StartListening(e.router, e)
 	}
@@ -392,8 +453,64 @@ func (e *Enjin) startupRootService(ctx *cli.Context) (err error) {
 	return e.eb.fServiceListener.StartListening(root, e)
 }
 
+// startupWithoutListener prepares the root enjin and its router for commands
+// which handle requests in-process, without starting the service listener
+func (e *Enjin) startupWithoutListener(ctx *cli.Context) (err error) {
+	if err = e.SetupRootEnjin(ctx); err != nil {
+		return
+	}
+	if err = e.startupIntegrityChecks(ctx); err != nil {
+		return
+	}
+	if err = e.startupFeatures(ctx); err != nil {
+		return
+	}
+	e.router = chi.NewRouter()
+	err = e.setupRouter(e.router)
+	return
+}
+
+// shutdownWithoutListener shuts down the features started with
+// startupWithoutListener
+func (e *Enjin) shutdownWithoutListener() {
+	for _, f := range e.eb.features.List() {
+		f.Shutdown()
+	}
+}
+
 func (e *Enjin) Shutdown() {
+	e.shutdownOnce.Do(e.shutdown)
+}
+
+func (e *Enjin) ShuttingDown() (shuttingDown bool) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	shuttingDown = e.shuttingDown
+	return
+}
+
+// shutdown stops accepting new requests and drains the active ones before
+// shutting down any features those requests may depend upon
+func (e *Enjin) shutdown() {
+	e.mutex.Lock()
+	e.shuttingDown = true
+	e.mutex.Unlock()
+
+	e.Notify("enjin shutting down")
+	if gsl, ok := e.eb.fServiceListener.This().(feature.GracefulServiceListener); ok {
+		if err := gsl.DrainListening(e.shutdownTimeout); err != nil {
+			log.ErrorF("error draining service listener: %v - %v", e.eb.fServiceListener.Tag(), err)
+		}
+	} else if err := e.eb.fServiceListener.StopListening(); err != nil {
+		log.ErrorF("error stopping service listener: %v - %v", e.eb.fServiceListener.Tag(), err)
+	}
+
 	e.Emit(signals.PreShutdownFeaturesPhase, feature.EnjinTag.String(), interface{}(e).(feature.Internals))
+	for _, enjin := range e.eb.enjins {
+		for _, f := range enjin.Features().List() {
+			f.Shutdown()
+		}
+	}
 	for _, f := range e.eb.features.List() {
 		f.Shutdown()
 	}
@@ -401,10 +518,5 @@ func (e *Enjin) Shutdown() {
 	profiling.Stop()
 	e.Emit(signals.RootEnjinShutdown, feature.EnjinTag.String(), interface{}(e).(feature.Internals))
 	e.Notify("enjin shutdown complete")
-	time.Sleep(250 * time.Millisecond)
-	// TODO: why/how is the http service listener actually ending the process abruptly?
-	if err := e.eb.fServiceListener.StopListening(); err != nil {
-		log.ErrorDF(1, "error stopping http listener: %v - %v", e.eb.fServiceListener.Tag(), err)
-	}
 	os.Exit(0)
 }
@@ -0,0 +1,139 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package be
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/linkcheck"
+	"github.com/go-enjin/be/pkg/log"
+)
+
+func (e *Enjin) initCheckLinks() {
+	var kinds []string
+	for _, kind := range linkcheck.IssueKinds {
+		kinds = append(kinds, string(kind))
+	}
+	e.cli.Commands = append(e.cli.Commands, &cli.Command{
+		Name:      "check-links",
+		Usage:     "render all pages and report broken links and missing assets",
+		UsageText: globals.BinName + " [global options] check-links [options]",
+		Description: "Check-links starts the enjin without a listener, renders every page (for each " +
+			"site language) and resolves all internal href, src and srcset references against the " +
+			"site pages, public filesystems and theme static files. The exit status is non-zero " +
+			"when any of the --fail-on issue kinds are found.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "site-url",
+				Usage: "the site URL used when rendering requests",
+				Value: "http://localhost",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "report format: text or json",
+				Value: "text",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "write the report to the given file instead of stdout",
+			},
+			&cli.StringSliceFlag{
+				Name:  "fail-on",
+				Usage: "issue kinds resulting in a non-zero exit status: " + strings.Join(kinds, ", "),
+				Value: cli.NewStringSlice(string(linkcheck.Broken)),
+			},
+		},
+		Action: e.checkLinksAction,
+	})
+}
+
+func (e *Enjin) checkLinksAction(ctx *cli.Context) (err error) {
+	if ctx.Args().Len() > 0 {
+		cli.ShowCommandHelpAndExit(ctx, "check-links", 1)
+	}
+
+	format := strings.ToLower(ctx.String("format"))
+	if format != "text" && format != "json" {
+		err = fmt.Errorf("invalid --format: %q", format)
+		return
+	}
+
+	var failOn []linkcheck.IssueKind
+	for _, name := range ctx.StringSlice("fail-on") {
+		if kind, ok := linkcheck.ParseIssueKind(name); ok {
+			failOn = append(failOn, kind)
+		} else {
+			err = fmt.Errorf("invalid --fail-on: %q", name)
+			return
+		}
+	}
+
+	var siteUrl *url.URL
+	if siteUrl, err = url.Parse(ctx.String("site-url")); err != nil {
+		err = fmt.Errorf("invalid --site-url: %v", err)
+		return
+	} else if siteUrl.Host == "" {
+		err = fmt.Errorf("invalid --site-url: host is required")
+		return
+	}
+
+	if err = e.startupWithoutListener(ctx); err != nil {
+		return
+	}
+	defer e.shutdownWithoutListener()
+
+	if len(e.eb.enjins) > 0 {
+		log.WarnF("check-links only includes the root enjin, %d included enjins are skipped", len(e.eb.enjins))
+	}
+
+	report := linkcheck.New(e, e.router, siteUrl).Run()
+
+	var w io.Writer = os.Stdout
+	if output := ctx.String("output"); output != "" {
+		var fh *os.File
+		if fh, err = os.Create(output); err != nil {
+			return
+		}
+		defer func() {
+			_ = fh.Close()
+		}()
+		w = fh
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		err = encoder.Encode(report)
+	default:
+		err = report.WriteText(w)
+	}
+	if err != nil {
+		return
+	}
+
+	if report.Failed(failOn...) {
+		err = cli.Exit("", 1)
+	}
+	return
+}
@@ -0,0 +1,380 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package be
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gofrs/uuid"
+	"github.com/urfave/cli/v2"
+
+	clPath "github.com/go-corelibs/path"
+	"github.com/go-corelibs/x-text/language"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/linkcheck"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/maps"
+)
+
+const (
+	// ExportRedirectsFile is the Netlify/Cloudflare Pages style redirects map
+	// written to the root of the export directory
+	ExportRedirectsFile = "_redirects"
+	// ExportRedirectsJsonFile is the JSON redirects map written to the root
+	// of the export directory, for use with other static hosting services
+	ExportRedirectsJsonFile = "redirects.json"
+)
+
+var (
+	// ExportStandardPaths are the well-known paths included with every export
+	ExportStandardPaths = []string{"/robots.txt", "/sitemap.xml"}
+
+	rxExportStatusPath = regexp.MustCompile(`^/([45]\d\d)$`)
+)
+
+type exportTarget struct {
+	host   string
+	path   string
+	status int
+}
+
+func (e *Enjin) initExport() {
+	e.cli.Commands = append(e.cli.Commands, &cli.Command{
+		Name:      "export",
+		Usage:     "render all routable pages and static files to a directory",
+		UsageText: globals.BinName + " [global options] export [options] <directory>",
+		Description: "Export starts the enjin without a listener, renders every page (for each site " +
+			"language), public and theme static file, the standard robots and sitemap files, any " +
+			"feature export paths (feeds) and the status pages through the normal request handling " +
+			"and writes a deployable directory tree. " +
+			"Permalink and language redirects are written to " + ExportRedirectsFile + " and " +
+			ExportRedirectsJsonFile + ".",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "site-url",
+				Usage: "the site URL used when rendering requests",
+				Value: "http://localhost",
+			},
+			&cli.StringSliceFlag{
+				Name:  "include",
+				Usage: "additional URL paths to export (feeds, generated files, etc)",
+			},
+			&cli.BoolFlag{
+				Name:  "clean",
+				Usage: "remove the export directory before writing",
+			},
+		},
+		Action: e.exportAction,
+	})
+}
+
+func (e *Enjin) exportAction(ctx *cli.Context) (err error) {
+	argv := ctx.Args().Slice()
+	if len(argv) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, "export", 1)
+	}
+
+	var output string
+	if output, err = filepath.Abs(argv[0]); err != nil {
+		return
+	}
+
+	var siteUrl *url.URL
+	if siteUrl, err = url.Parse(ctx.String("site-url")); err != nil {
+		err = fmt.Errorf("invalid --site-url: %v", err)
+		return
+	} else if siteUrl.Host == "" {
+		err = fmt.Errorf("invalid --site-url: host is required")
+		return
+	}
+
+	if err = e.startupWithoutListener(ctx); err != nil {
+		return
+	}
+	defer e.shutdownWithoutListener()
+
+	if len(e.eb.enjins) > 0 {
+		log.WarnF("export only includes the root enjin, %d included enjins are skipped", len(e.eb.enjins))
+	}
+
+	if ctx.Bool("clean") {
+		if err = os.RemoveAll(output); err != nil {
+			return
+		}
+	}
+
+	targets, redirects := e.exportTargets(siteUrl, ctx.StringSlice("include"))
+
+	var written, skipped int
+	for _, target := range targets {
+		if ok, ee := e.exportTarget(siteUrl, output, target, redirects); ee != nil {
+			err = ee
+			return
+		} else if ok {
+			written += 1
+		} else {
+			skipped += 1
+		}
+	}
+
+	if err = exportRedirects(output, redirects); err != nil {
+		return
+	}
+
+	fmt.Printf("exported %d files (%d skipped, %d redirects) to: %v\n", written, skipped, len(redirects), output)
+	return
+}
+
+// exportTargets enumerates every path to export, along with the permalink and
+// language redirects for all pages
+func (e *Enjin) exportTargets(siteUrl *url.URL, includes []string) (targets []exportTarget, redirects map[string]string) {
+	redirects = make(map[string]string)
+	unique := make(map[exportTarget]struct{})
+	add := func(translated string) {
+		t := exportTarget{path: translated}
+		if u, ee := url.Parse(translated); ee == nil && u.Host != "" {
+			// domain language mode urls include the host
+			t = exportTarget{host: u.Host, path: u.Path}
+		}
+		if t.path == "" {
+			t.path = "/"
+		}
+		if _, present := unique[t]; !present {
+			unique[t] = struct{}{}
+			targets = append(targets, t)
+		}
+	}
+
+	langMode := e.SiteLanguageMode()
+	defaultTag := e.SiteDefaultLanguage()
+	locales := e.SiteLocales()
+	if len(locales) == 0 {
+		locales = append(locales, defaultTag)
+	}
+
+	r, _ := http.NewRequest(http.MethodGet, siteUrl.String(), nil)
+
+	for _, pageUrl := range linkcheck.PageUrls(e) {
+		for _, tag := range locales {
+			if !e.SiteSupportsLanguage(tag) {
+				continue
+			}
+			translated := langMode.ToUrl(defaultTag, tag, pageUrl)
+			add(translated)
+
+			if !language.Compare(tag, defaultTag) {
+				continue
+			}
+
+			if defaultTag != language.Und && langMode.Name() == "path" {
+				// the default language is also reachable with the language prefix
+				if prefixed := "/" + defaultTag.String() + clPath.CleanWithSlash(pageUrl); prefixed != translated {
+					redirects[prefixed] = translated
+				}
+			}
+
+			if p := e.FindPage(r, tag, pageUrl); p != nil && p.Permalink() != uuid.Nil {
+				redirects["/"+p.Permalink().String()] = translated
+				if pageUrl == "/" {
+					redirects["/"+p.PermalinkSha()] = translated
+				} else {
+					redirects[clPath.CleanWithSlash(pageUrl)+"-"+p.PermalinkSha()] = translated
+				}
+			}
+		}
+	}
+
+	for _, file := range e.exportStaticFiles() {
+		add(file)
+	}
+
+	for _, standard := range append(ExportStandardPaths, includes...) {
+		add(clPath.CleanWithSlash(standard))
+	}
+
+	for _, epp := range feature.FilterTyped[feature.ExportPathsProvider](e.Features().List()) {
+		for _, exportPath := range epp.ExportPaths() {
+			add(clPath.CleanWithSlash(exportPath))
+		}
+	}
+
+	for _, status := range maps.OrderedKeys(e.eb.statusPages) {
+		// status pages are written as <status>.html
+		t := exportTarget{path: clPath.CleanWithSlash(e.eb.statusPages[status]), status: status}
+		if _, present := unique[t]; !present {
+			unique[t] = struct{}{}
+			targets = append(targets, t)
+		}
+	}
+
+	sort.SliceStable(targets, func(i, j int) bool {
+		if targets[i].host == targets[j].host {
+			return targets[i].path < targets[j].path
+		}
+		return targets[i].host < targets[j].host
+	})
+	return
+}
+
+// exportStaticFiles returns the URL paths of all public and theme static files
+func (e *Enjin) exportStaticFiles() (files []string) {
+	for _, f := range feature.FilterTyped[feature.ServePathFeature](e.Features().List()) {
+		if fsf, ok := f.This().(feature.FileSystemFeature); ok {
+			for point, mps := range fsf.GetMountedPoints() {
+				for _, mp := range mps {
+					if found, ee := mp.ROFS.ListAllFiles("."); ee == nil {
+						for _, file := range found {
+							files = append(files, clPath.CleanWithSlash(path.Join(point, file)))
+						}
+					}
+				}
+			}
+		}
+	}
+
+	if t, ee := e.GetTheme(); ee == nil {
+		for t != nil {
+			if sfs := t.StaticFS(); sfs != nil {
+				if found, ee := sfs.ListAllFiles("."); ee == nil {
+					for _, file := range found {
+						files = append(files, clPath.CleanWithSlash(file))
+					}
+				}
+			}
+			t = t.GetParent()
+		}
+	}
+	return
+}
+
+// exportTarget renders the target through the enjin router and writes the
+// response to the output directory; redirect responses are added to the
+// redirects map and only successful (or status page) responses are written
+func (e *Enjin) exportTarget(siteUrl *url.URL, output string, target exportTarget, redirects map[string]string) (written bool, err error) {
+	u := *siteUrl
+	u.Path = target.path
+	if target.host != "" {
+		u.Host = target.host
+	}
+
+	var r *http.Request
+	if r, err = http.NewRequest(http.MethodGet, u.String(), nil); err != nil {
+		return
+	}
+	r.RemoteAddr = "127.0.0.1:0"
+	r.RequestURI = target.path
+	if u.Scheme == "https" {
+		// request.ParseDomainUrl depends on r.TLS
+		r.TLS = &tls.ConnectionState{}
+	}
+
+	w := httptest.NewRecorder()
+	e.router.ServeHTTP(w, r)
+
+	status := w.Code
+	mimeType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type"))
+
+	var file string
+	switch {
+	case target.status > 0:
+		if status != http.StatusOK && status != target.status {
+			log.DebugF("export skipping %d status page %v: %d %v", target.status, target.path, status, http.StatusText(status))
+			return
+		}
+		file = strconv.Itoa(target.status) + ".html"
+	case status == http.StatusOK:
+		file = exportFilePath(target.path, mimeType)
+	case status >= 300 && status < 400:
+		if location := w.Header().Get("Location"); location != "" {
+			redirects[target.path] = location
+		}
+		return
+	default:
+		if m := rxExportStatusPath.FindStringSubmatch(target.path); len(m) == 2 && m[1] == strconv.Itoa(status) {
+			// status pages are served with their status code
+			file = m[1] + ".html"
+			break
+		}
+		log.DebugF("export skipping %v: %d %v", target.path, status, http.StatusText(status))
+		return
+	}
+
+	if target.host != "" {
+		file = filepath.Join(output, target.host, filepath.FromSlash(file))
+	} else {
+		file = filepath.Join(output, filepath.FromSlash(file))
+	}
+
+	if err = os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		return
+	}
+	if err = os.WriteFile(file, w.Body.Bytes(), 0644); err != nil {
+		return
+	}
+	log.DebugF("exported %v: %v", target.path, file)
+	written = true
+	return
+}
+
+// exportFilePath returns the relative file path to write the given URL path
+// to, HTML pages without a file extension are written as directory indexes
+func exportFilePath(urlPath, mimeType string) (file string) {
+	file = strings.TrimPrefix(clPath.CleanWithSlash(urlPath), "/")
+	if file == "" {
+		file = "index.html"
+	} else if path.Ext(file) == "" && mimeType == "text/html" {
+		file += "/index.html"
+	}
+	return
+}
+
+func exportRedirects(output string, redirects map[string]string) (err error) {
+	if len(redirects) == 0 {
+		return
+	}
+
+	if err = os.MkdirAll(output, 0755); err != nil {
+		return
+	}
+
+	var lines string
+	for _, from := range maps.SortedKeys(redirects) {
+		lines += from + " " + redirects[from] + " 301\n"
+	}
+	if err = os.WriteFile(filepath.Join(output, ExportRedirectsFile), []byte(lines), 0644); err != nil {
+		return
+	}
+
+	var data []byte
+	if data, err = json.MarshalIndent(redirects, "", "\t"); err != nil {
+		return
+	}
+	err = os.WriteFile(filepath.Join(output, ExportRedirectsJsonFile), append(data, '\n'), 0644)
+	return
+}
@@ -90,6 +90,9 @@ func init() {
 	if earlyDebug {
 		log.Config.LogLevel = log.LevelDebug
 	}
+	if format, ok := log.Formats[strings.ToLower(os.Getenv(globals.EnvPrefix+"_LOG_FORMAT"))]; ok {
+		log.Config.LoggingFormat = format
+	}
 
 	log.Config.Apply()
 }
@@ -0,0 +1,113 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package be
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/lint"
+	"github.com/go-enjin/be/pkg/log"
+)
+
+func (e *Enjin) initLint() {
+	e.cli.Commands = append(e.cli.Commands, &cli.Command{
+		Name:      "lint",
+		Usage:     "check all content files for front-matter and page problems",
+		UsageText: globals.BinName + " [global options] lint [options]",
+		Description: "Lint starts the enjin without a listener and walks every mounted content " +
+			"filesystem, checking each file for front-matter syntax errors, unknown theme layouts, " +
+			"missing or invalid page context fields (including theme archetype fields), file " +
+			"extensions not supported by any page format and duplicate page URLs. The exit " +
+			"status is non-zero when any errors are found, or any warnings with --strict.",
+		Flags: []cli.Flag{
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "report format: text or json",
+				Value: "text",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "write the report to the given file instead of stdout",
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "exit with a non-zero status when there are any warnings",
+			},
+		},
+		Action: e.lintAction,
+	})
+}
+
+func (e *Enjin) lintAction(ctx *cli.Context) (err error) {
+	if ctx.Args().Len() > 0 {
+		cli.ShowCommandHelpAndExit(ctx, "lint", 1)
+	}
+
+	format := strings.ToLower(ctx.String("format"))
+	if format != "text" && format != "json" {
+		err = fmt.Errorf("invalid --format: %q", format)
+		return
+	}
+
+	if err = e.startupWithoutListener(ctx); err != nil {
+		return
+	}
+	defer e.shutdownWithoutListener()
+
+	if len(e.eb.enjins) > 0 {
+		log.WarnF("lint only includes the root enjin, %d included enjins are skipped", len(e.eb.enjins))
+	}
+
+	var report *lint.Report
+	if report, err = lint.New(e).Run(); err != nil {
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if output := ctx.String("output"); output != "" {
+		var fh *os.File
+		if fh, err = os.Create(output); err != nil {
+			return
+		}
+		defer func() {
+			_ = fh.Close()
+		}()
+		w = fh
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		err = encoder.Encode(report)
+	default:
+		err = report.WriteText(w)
+	}
+	if err != nil {
+		return
+	}
+
+	if report.Failed(ctx.Bool("strict")) {
+		err = cli.Exit("", 1)
+	}
+	return
+}
@@ -35,6 +35,9 @@ func (e *Enjin) ReloadLocales() {
 		//log.DebugF("adding %v locales", lp.Tag())
 		lp.AddLocales(ctlg)
 	}
+	for tag, fallbacks := range e.eb.fallbacks {
+		ctlg.SetFallbacks(tag, fallbacks...)
+	}
 	e.mutex.Lock()
 	e.catalog = ctlg
 	e.locales = ctlg.LocaleTagsWithDefault(e.eb.defaultLang)
@@ -72,6 +75,18 @@ func (e *Enjin) SiteLanguageCatalog() (c catalog.Catalog) {
 	return
 }
 
+func (e *Enjin) SiteLanguageMessageKeys(tag language.Tag) (keys []string) {
+	e.mutex.RLock()
+	defer e.mutex.RUnlock()
+	keys = e.catalog.LocaleMessageKeys(tag)
+	return
+}
+
+func (e *Enjin) SiteLanguageFallbacks(tag language.Tag) (fallbacks []language.Tag) {
+	fallbacks = e.eb.fallbacks.Get(tag)
+	return
+}
+
 func (e *Enjin) SiteDefaultLanguage() (tag language.Tag) {
 	//e.mutex.RLock()
 	//defer e.mutex.RUnlock()
@@ -23,13 +23,18 @@ import (
 	"github.com/go-enjin/be/pkg/log"
 	beNet "github.com/go-enjin/be/pkg/net"
 	"github.com/go-enjin/be/pkg/net/headers"
+	"github.com/go-enjin/be/pkg/trace"
 )
 
 func (e *Enjin) requestFiltersMiddleware(next http.Handler) http.Handler {
 	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 		remoteAddr, _ := beNet.GetIpFromRequest(r)
 		for _, rf := range e.eb.fRequestFilters {
-			if err := rf.FilterRequest(r); err != nil {
+			_, span := trace.StartRequestChild(r, "request-filter "+rf.Tag().String())
+			err := rf.FilterRequest(r)
+			span.SetError(err)
+			span.End()
+			if err != nil {
 				log.WarnRF(r, "filtering request from: %v - %v", remoteAddr, err)
 				e.ServeNotFound(w, r)
 				return
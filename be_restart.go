@@ -0,0 +1,86 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package be
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net/handoff"
+)
+
+// startGracefulRestartHandler listens for SIGUSR2 when --graceful-restart is
+// enabled, starting a new process which inherits the listening sockets and
+// draining this process once the new one is ready
+func (e *Enjin) startGracefulRestartHandler() {
+	if !e.gracefulRestart {
+		return
+	}
+
+	gsl, ok := e.eb.fServiceListener.This().(feature.GracefulServiceListener)
+	if !ok {
+		log.WarnF("--graceful-restart requires a feature.GracefulServiceListener, %v is not", e.eb.fServiceListener.Tag())
+		return
+	}
+
+	go func() {
+		sigusr2 := make(chan os.Signal, 1)
+		signal.Notify(sigusr2, syscall.SIGUSR2)
+		for range sigusr2 {
+			if err := e.restartProcess(gsl); err != nil {
+				log.ErrorF("graceful restart failed, continuing with this process: %v", err)
+				continue
+			}
+			signal.Stop(sigusr2)
+			e.Shutdown()
+			return
+		}
+	}()
+}
+
+func (e *Enjin) restartProcess(gsl feature.GracefulServiceListener) (err error) {
+	e.Notify("graceful restart starting")
+
+	var files []*os.File
+	if files, err = gsl.ListenerFiles(); err != nil {
+		err = fmt.Errorf("error getting listener files: %w", err)
+		return
+	}
+	defer func() {
+		for _, file := range files {
+			_ = file.Close()
+		}
+	}()
+
+	var pid int
+	if pid, err = handoff.Restart(files, e.restartTimeout); err != nil {
+		return
+	}
+
+	log.InfoF("graceful restart process %d is ready, draining this process", pid)
+	return
+}
+
+// NotifyReady informs the parent process, when gracefully restarted, that
+// this process is listening and ready to take over
+func (e *Enjin) NotifyReady() {
+	if err := handoff.NotifyReady(); err != nil {
+		log.ErrorF("error notifying parent process of readiness: %v", err)
+	}
+}
@@ -29,11 +29,15 @@ import (
 	"github.com/go-enjin/be/pkg/request"
 	"github.com/go-enjin/be/pkg/request/argv"
 	"github.com/go-enjin/be/pkg/signals"
+	"github.com/go-enjin/be/pkg/trace"
 )
 
 func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 	e.Emit(signals.PreEnjinSetupRouter, feature.EnjinTag.String(), interface{}(e).(feature.Internals))
 
+	// tracing spans are a no-op unless a tracing feature is present
+	router.Use(e.traceRequestMiddleware)
+
 	router.Use(func(next http.Handler) http.Handler {
 		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 
@@ -41,6 +45,10 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 				request.KeyEnjinID, e.String(),
 				request.KeyHomePath, "/",
 			)
+			if log.Structured() {
+				// the access logger runs before the user and language are known
+				r = log.PrepareRequestFields(r)
+			}
 
 			w.Header().Set("Server", e.ServerName())
 			next.ServeHTTP(w, r)
@@ -48,13 +56,13 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 		})
 	})
 
-	router.Use(middleware.RequestID)
-	router.Use(e.eb.fPanicHandler.PanicHandler)
-	router.Use(argv.Middleware)
+	e.use(router, "request-id", middleware.RequestID)
+	e.use(router, e.eb.fPanicHandler.Tag().String(), e.eb.fPanicHandler.PanicHandler)
+	e.use(router, "argv", argv.Middleware)
 
 	if e.eb.hotReload {
 		log.DebugF("including hot-reload middleware")
-		router.Use(e.hotReloadMiddleware)
+		e.use(router, "hot-reload", e.hotReloadMiddleware)
 	}
 
 	// request modifier features are expected to modify the request object
@@ -68,7 +76,9 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 		router.Use(func(next http.Handler) http.Handler {
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				for _, rm := range e.eb.fRequestModifiers {
+					_, span := trace.StartRequestChild(r, "request-modifier "+rm.Tag().String())
 					rm.ModifyRequest(w, r)
+					span.End()
 				}
 				next.ServeHTTP(w, r)
 			})
@@ -87,9 +97,11 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 		router.Use(func(next http.Handler) http.Handler {
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				for _, rr := range e.eb.fRequestRewriters {
+					_, span := trace.StartRequestChild(r, "request-rewriter "+rr.Tag().String())
 					if modified := rr.RewriteRequest(w, r); modified != nil {
 						r = modified
 					}
+					span.End()
 				}
 				next.ServeHTTP(w, r)
 			})
@@ -97,36 +109,36 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 	}
 
 	// logging after requests modified so proxy has a chance to populate ip
-	router.Use(middleware.Logger)
+	e.use(router, "logger", middleware.Logger)
 
 	// gzip compression for default compressible content types
-	router.Use(middleware.Compress(5))
+	e.use(router, "compress", middleware.Compress(5))
 
 	// these should be request modifiers instead of enjin middleware
-	router.Use(e.eb.fLocaleHandler.LocaleHandler)
-	router.Use(e.redirectionMiddleware)
-	router.Use(e.headersMiddleware)
+	e.use(router, e.eb.fLocaleHandler.Tag().String(), e.eb.fLocaleHandler.LocaleHandler)
+	e.use(router, "redirection", e.redirectionMiddleware)
+	e.use(router, "headers", e.headersMiddleware)
 
 	// operational security measures
-	router.Use(e.domainsMiddleware)
-	router.Use(e.permissionsPolicy.PrepareRequestMiddleware)
-	router.Use(e.contentSecurityPolicy.PrepareRequestMiddleware)
-	router.Use(e.requestFiltersMiddleware)
+	e.use(router, "domains", e.domainsMiddleware)
+	e.use(router, "permissions-policy", e.permissionsPolicy.PrepareRequestMiddleware)
+	e.use(router, "content-security-policy", e.contentSecurityPolicy.PrepareRequestMiddleware)
+	e.use(router, "request-filters", e.requestFiltersMiddleware)
 
 	// header policy modifier features do not block next.ServeHTTP calls and
 	// must happen before blocking middleware features (ones that may not call
 	// next.ServeHTTP having already served the response)
 	for _, ppm := range e.eb.fPermissionsPolicyModifiers {
 		log.DebugF("including %v modify permissions policy middleware", ppm.Tag())
-		router.Use(e.permissionsPolicy.ModifyPolicyMiddleware(ppm.ModifyPermissionsPolicy))
+		e.use(router, ppm.Tag().String(), e.permissionsPolicy.ModifyPolicyMiddleware(ppm.ModifyPermissionsPolicy))
 	}
 	for _, cspm := range e.eb.fContentSecurityPolicyModifiers {
 		log.DebugF("including %v modify content security policy middleware", cspm.Tag())
-		router.Use(e.contentSecurityPolicy.ModifyPolicyMiddleware(cspm.ModifyContentSecurityPolicy))
+		e.use(router, cspm.Tag().String(), e.contentSecurityPolicy.ModifyPolicyMiddleware(cspm.ModifyContentSecurityPolicy))
 	}
 	for _, cspFnTag := range e.eb.cspModifierFnOrder {
 		log.DebugF("including %v modify content security policy func", cspFnTag)
-		router.Use(e.contentSecurityPolicy.ModifyPolicyMiddleware(e.eb.cspModifierFns[cspFnTag]))
+		e.use(router, cspFnTag, e.contentSecurityPolicy.ModifyPolicyMiddleware(e.eb.cspModifierFns[cspFnTag]))
 	}
 
 	// theme static files [blocking middleware]
@@ -134,23 +146,23 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 		log.WarnF("not including any theme middleware: %v", ee)
 	} else {
 		if t.StaticFS() != nil {
-			router.Use(t.Middleware)
+			e.use(router, "theme-"+t.Name(), t.Middleware)
 		}
 		if tp := t.GetParent(); tp != nil {
 			if tp.StaticFS() != nil {
-				router.Use(tp.Middleware)
+				e.use(router, "theme-"+tp.Name(), tp.Middleware)
 			}
 		}
 	}
 
-	router.Use(e.userAuthMiddleware)
+	e.use(router, "user-auth", e.userAuthMiddleware)
 
 	// potentially blocking middleware features that do not require standard
 	// page rendering or data response facilities
 	for _, um := range e.eb.fUseMiddlewares {
 		log.DebugF("including %v use middleware", um.Tag())
 		if mw := um.Use(e); mw != nil {
-			router.Use(mw)
+			e.use(router, um.Tag().String(), mw)
 		}
 	}
 
@@ -158,13 +170,13 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 	// that did not actually serve a response
 	for _, hm := range e.eb.fHeadersModifiers {
 		log.DebugF("including %v use-after modify headers middleware", hm.Tag())
-		router.Use(headers.ModifyAfterUseMiddleware(hm.ModifyHeaders))
+		e.use(router, hm.Tag().String(), headers.ModifyAfterUseMiddleware(hm.ModifyHeaders))
 	}
 
 	// processor middleware features are potentially blocking
 	for _, proc := range e.eb.fProcessors {
 		log.DebugF("including %v processor middleware", proc.Tag())
-		router.Use(func(next http.Handler) http.Handler {
+		e.use(router, proc.Tag().String(), func(next http.Handler) http.Handler {
 			innerProc := proc // prevent outer scoped proc overwriting
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				innerProc.Process(e, next, w, r)
@@ -177,7 +189,7 @@ func (e *Enjin) setupRouter(router *chi.Mux) (err error) {
 	sort.Sort(clStrings.SortByLength(sortedRoutes))
 	for _, route := range sortedRoutes {
 		log.DebugF("including enjin %v route processor middleware", route)
-		router.Use(func(next http.Handler) http.Handler {
+		e.use(router, "route-processor "+route, func(next http.Handler) http.Handler {
 			innerRoute := route // prevent outer scoped route overwriting
 			return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
 				if r.URL.Path == innerRoute {
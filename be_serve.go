@@ -32,15 +32,18 @@ import (
 	"github.com/go-enjin/be/pkg/request"
 	"github.com/go-enjin/be/pkg/request/argv"
 	"github.com/go-enjin/be/pkg/signals"
+	"github.com/go-enjin/be/pkg/trace"
 	"github.com/go-enjin/be/pkg/userbase"
 	"github.com/go-enjin/be/types/page"
 )
 
 func (e *Enjin) FinalizeServeRequest(w http.ResponseWriter, r *http.Request) (modified *http.Request) {
 	for _, fsp := range e.GetFinalizeServePagesFeatures() {
+		_, span := trace.StartRequestChild(r, "finalize-serve-request "+fsp.Tag().String())
 		if m := fsp.FinalizeServeRequest(w, r); m != nil {
 			r = m
 		}
+		span.End()
 	}
 	modified = r
 	return
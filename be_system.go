@@ -179,6 +179,10 @@ func (e *Enjin) FindRedirection(url string) (p feature.Page) {
 	return
 }
 
+// FindTranslations returns all translations of the given url and, for each
+// site language without a translation, a copy of the translation in the first
+// language of its fallback chain, with the language and LanguageFallback
+// context flag set
 func (e *Enjin) FindTranslations(url string) (pages feature.Pages) {
 	for _, provider := range e.eb.fPageProviders {
 		if found := provider.FindTranslations(url); len(found) > 0 {
@@ -190,9 +194,35 @@ func (e *Enjin) FindTranslations(url string) (pages feature.Pages) {
 			pages = append(pages, pg)
 		}
 	}
+
+	translated := make(map[language.Tag]feature.Page)
+	for _, pg := range pages {
+		if _, present := translated[pg.LanguageTag()]; !present {
+			translated[pg.LanguageTag()] = pg
+		}
+	}
+	for _, tag := range e.SiteLocales() {
+		if _, present := translated[tag]; present {
+			continue
+		}
+		for _, fallback := range e.eb.fallbacks.Get(tag) {
+			if pg, ok := translated[fallback]; ok {
+				// copies of copies are the same page and cannot be relabeled
+				if cp := pg.Copy(); cp != pg {
+					cp.SetLanguage(tag)
+					cp.Context().SetSpecific("LanguageFallback", true)
+					pages = append(pages, cp)
+				}
+				break
+			}
+		}
+	}
 	return
 }
 
+// FindTranslationUrls returns the urls of all translations of the given url
+// and, for each site language without a translation, the url of the first
+// language of its fallback chain which has one
 func (e *Enjin) FindTranslationUrls(url string) (pages map[language.Tag]string) {
 	pages = make(map[language.Tag]string)
 	for _, provider := range e.eb.fPageProviders {
@@ -205,6 +235,21 @@ func (e *Enjin) FindTranslationUrls(url string) (pages map[language.Tag]string)
 			pages[pg.LanguageTag()] = pg.Url()
 		}
 	}
+	fallbacks := make(map[language.Tag]string)
+	for _, tag := range e.SiteLocales() {
+		if _, present := pages[tag]; present {
+			continue
+		}
+		for _, fallback := range e.eb.fallbacks.Get(tag) {
+			if path, ok := pages[fallback]; ok {
+				fallbacks[tag] = path
+				break
+			}
+		}
+	}
+	for tag, path := range fallbacks {
+		pages[tag] = path
+	}
 	return
 }
 
@@ -221,6 +266,15 @@ func (e *Enjin) FindFile(path string) (data []byte, mime string, err error) {
 }
 
 func (e *Enjin) FindPage(r *http.Request, tag language.Tag, url string) (p feature.Page) {
+	for _, chained := range e.eb.fallbacks.Chain(tag) {
+		if p = e.findPage(r, chained, url); p != nil {
+			return
+		}
+	}
+	return
+}
+
+func (e *Enjin) findPage(r *http.Request, tag language.Tag, url string) (p feature.Page) {
 	for _, provider := range e.eb.fPageProviders {
 		if p = provider.FindPage(r, tag, url); p != nil {
 			return
@@ -272,7 +326,7 @@ func (e *Enjin) MatchFormat(filename string) (format feature.PageFormat, match s
 func (e *Enjin) CheckMatchQL(query string) (pages []feature.Page, err error) {
 	t, _ := e.GetTheme()
 	for _, queryEnjin := range e.eb.fQueryIndexFeatures {
-		if matches, ee := queryEnjin.PerformQuery(query); ee != nil {
+		if matches, ee := e.performQuery(queryEnjin, query); ee != nil {
 			err = ee
 		} else {
 			for _, stub := range matches {
@@ -292,7 +346,7 @@ func (e *Enjin) CheckMatchQL(query string) (pages []feature.Page, err error) {
 func (e *Enjin) MatchQL(query string) (pages []feature.Page) {
 	t, _ := e.GetTheme()
 	for _, queryEnjin := range e.eb.fQueryIndexFeatures {
-		if matches, err := queryEnjin.PerformQuery(query); err != nil {
+		if matches, err := e.performQuery(queryEnjin, query); err != nil {
 			log.ErrorF("error performing enjin query: %v", err)
 		} else {
 			for _, stub := range matches {
@@ -312,7 +366,7 @@ func (e *Enjin) MatchQL(query string) (pages []feature.Page) {
 func (e *Enjin) MatchStubsQL(query string) (stubs []*feature.PageStub) {
 	for _, queryEnjin := range e.eb.fQueryIndexFeatures {
 		var err error
-		if stubs, err = queryEnjin.PerformQuery(query); err != nil {
+		if stubs, err = e.performQuery(queryEnjin, query); err != nil {
 			log.ErrorF("error performing enjin query: %v", err)
 		}
 		// first query index feature wins?
@@ -323,7 +377,7 @@ func (e *Enjin) MatchStubsQL(query string) (stubs []*feature.PageStub) {
 
 func (e *Enjin) CheckMatchStubsQL(query string) (stubs []*feature.PageStub, err error) {
 	for _, queryEnjin := range e.eb.fQueryIndexFeatures {
-		stubs, err = queryEnjin.PerformQuery(query)
+		stubs, err = e.performQuery(queryEnjin, query)
 		// first query index feature wins?
 		break
 	}
@@ -333,7 +387,7 @@ func (e *Enjin) CheckMatchStubsQL(query string) (stubs []*feature.PageStub, err
 func (e *Enjin) SelectQL(query string) (selected map[string]interface{}) {
 	for _, queryEnjin := range e.eb.fQueryIndexFeatures {
 		var err error
-		if selected, err = queryEnjin.PerformSelect(query); err != nil {
+		if selected, err = e.performSelect(queryEnjin, query); err != nil {
 			log.ErrorF("error performing enjin select: %v", err)
 		}
 		// first query index feature wins?
@@ -344,7 +398,7 @@ func (e *Enjin) SelectQL(query string) (selected map[string]interface{}) {
 
 func (e *Enjin) CheckSelectQL(query string) (selected map[string]interface{}, err error) {
 	for _, queryEnjin := range e.eb.fQueryIndexFeatures {
-		selected, err = queryEnjin.PerformSelect(query)
+		selected, err = e.performSelect(queryEnjin, query)
 		// first query index feature wins?
 		break
 	}
@@ -0,0 +1,82 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package be
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/go-chi/chi/v5/middleware"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/trace"
+)
+
+// use includes the given middleware, wrapped with a tracing span named for
+// the middleware
+func (e *Enjin) use(router *chi.Mux, name string, mw func(next http.Handler) http.Handler) {
+	router.Use(trace.Middleware("middleware "+name, mw))
+}
+
+// traceRequestMiddleware starts the root server span of each request,
+// continuing any W3C traceparent given, and records the response status
+func (e *Enjin) traceRequestMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !trace.Enabled() {
+			next.ServeHTTP(w, r)
+			return
+		}
+		r, span := trace.StartRequest(r, r.Method+" "+r.URL.Path,
+			"http.request.method", r.Method,
+			"url.path", r.URL.Path,
+			"server.address", r.Host,
+			"user_agent.original", r.UserAgent(),
+		)
+		defer span.End()
+		ww := middleware.NewWrapResponseWriter(w, r.ProtoMajor)
+		next.ServeHTTP(ww, r)
+		status := ww.Status()
+		if status == 0 {
+			status = http.StatusOK
+		}
+		span.SetAttributes("http.response.status_code", status)
+		if status >= 500 {
+			span.SetError(fmt.Errorf("%d %s", status, http.StatusText(status)))
+		}
+	})
+}
+
+// performQuery runs the PQL query using the given feature, within a new span
+// when detached spans are enabled because the query has no request context
+func (e *Enjin) performQuery(qe feature.QueryIndexFeature, query string) (stubs []*feature.PageStub, err error) {
+	_, span := trace.Start(context.Background(), "pql query "+qe.Tag().String(), "pql.query", query)
+	defer span.End()
+	stubs, err = qe.PerformQuery(query)
+	span.SetAttributes("pql.matches", len(stubs))
+	span.SetError(err)
+	return
+}
+
+// performSelect runs the PQL select using the given feature, within a new
+// span when detached spans are enabled
+func (e *Enjin) performSelect(qe feature.QueryIndexFeature, query string) (selected map[string]interface{}, err error) {
+	_, span := trace.Start(context.Background(), "pql select "+qe.Tag().String(), "pql.query", query)
+	defer span.End()
+	selected, err = qe.PerformSelect(query)
+	span.SetError(err)
+	return
+}
@@ -0,0 +1,141 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package be
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-corelibs/x-text/language"
+
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/lang/coverage"
+	"github.com/go-enjin/be/pkg/log"
+)
+
+func (e *Enjin) initTranslationCoverage() {
+	e.cli.Commands = append(e.cli.Commands, &cli.Command{
+		Name:      "translation-coverage",
+		Usage:     "report untranslated messages and pages for each site locale",
+		UsageText: globals.BinName + " [global options] translation-coverage [options]",
+		Description: "Translation-coverage starts the enjin without a listener and extracts all " +
+			"message keys used with the template \"_\" function (within the theme and content " +
+			"filesystems) and with message.Printer methods (within the Go sources of the --source " +
+			"directories). Each key is compared against the gotext.json translations loaded for " +
+			"each site locale and all default language pages are checked for translated " +
+			"counterparts. Skeleton out.gotext.json messages are written for all missing keys " +
+			"when --write-skeleton is given.",
+		Flags: []cli.Flag{
+			&cli.StringSliceFlag{
+				Name:  "source",
+				Usage: "local directories to scan for Go sources and templates",
+			},
+			&cli.StringFlag{
+				Name:  "format",
+				Usage: "report format: text or json",
+				Value: "text",
+			},
+			&cli.StringFlag{
+				Name:  "output",
+				Usage: "write the report to the given file instead of stdout",
+			},
+			&cli.StringFlag{
+				Name:  "write-skeleton",
+				Usage: "add skeleton messages to <dir>/<locale>/out.gotext.json files for all missing keys",
+			},
+			&cli.BoolFlag{
+				Name:  "strict",
+				Usage: "exit with a non-zero status when there are any missing translations",
+			},
+		},
+		Action: e.translationCoverageAction,
+	})
+}
+
+func (e *Enjin) translationCoverageAction(ctx *cli.Context) (err error) {
+	if ctx.Args().Len() > 0 {
+		cli.ShowCommandHelpAndExit(ctx, "translation-coverage", 1)
+	}
+
+	format := strings.ToLower(ctx.String("format"))
+	if format != "text" && format != "json" {
+		err = fmt.Errorf("invalid --format: %q", format)
+		return
+	}
+
+	if err = e.startupWithoutListener(ctx); err != nil {
+		return
+	}
+	defer e.shutdownWithoutListener()
+
+	keys := coverage.Keys{}
+	coverage.ExtractEnjin(e, keys)
+	for _, dir := range ctx.StringSlice("source") {
+		if err = coverage.ExtractDir(dir, keys); err != nil {
+			err = fmt.Errorf("error extracting messages from %v: %v", dir, err)
+			return
+		}
+	}
+
+	report := coverage.Build(e, keys)
+
+	if dir := ctx.String("write-skeleton"); dir != "" {
+		for _, lc := range report.Locales {
+			tag, _ := language.Parse(lc.Locale)
+			var path string
+			var added int
+			if path, added, err = coverage.WriteSkeleton(dir, tag, keys, lc.Missing); err != nil {
+				err = fmt.Errorf("error writing %v skeleton: %v", lc.Locale, err)
+				return
+			} else if added > 0 {
+				log.InfoF("added %d skeleton messages to: %v", added, path)
+			}
+		}
+	}
+
+	var w io.Writer = os.Stdout
+	if output := ctx.String("output"); output != "" {
+		var fh *os.File
+		if fh, err = os.Create(output); err != nil {
+			return
+		}
+		defer func() {
+			_ = fh.Close()
+		}()
+		w = fh
+	}
+
+	switch format {
+	case "json":
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		err = encoder.Encode(report)
+	default:
+		err = report.WriteText(w)
+	}
+	if err != nil {
+		return
+	}
+
+	if ctx.Bool("strict") && report.Failed() {
+		err = cli.Exit("", 1)
+	}
+	return
+}
@@ -17,6 +17,9 @@ package be
 import (
 	"net/http"
 
+	"github.com/go-corelibs/x-text/message"
+
+	"github.com/go-enjin/be/pkg/log"
 	"github.com/go-enjin/be/pkg/userbase"
 )
 
@@ -30,6 +33,13 @@ func (e *Enjin) userAuthMiddleware(next http.Handler) http.Handler {
 				r = modified
 			}
 		}
+		if log.Structured() {
+			if u := userbase.GetCurrentUser(r); u != nil {
+				r = log.AppendRequestFields(r, log.FieldUser, u.GetEID())
+				// user request modifiers may have changed the language
+				log.RecordRequestFields(r, log.FieldLanguage, message.GetTag(r).String())
+			}
+		}
 		next.ServeHTTP(w, r)
 	})
 }
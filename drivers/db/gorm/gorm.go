@@ -17,6 +17,8 @@
 package gorm
 
 import (
+	"context"
+	"database/sql"
 	"fmt"
 	"time"
 
@@ -40,6 +42,7 @@ var (
 
 type Feature interface {
 	feature.Database
+	feature.HealthChecker
 }
 
 type CFeature struct {
@@ -223,3 +226,18 @@ func (f *CFeature) MustDB(tag string) (db interface{}) {
 	log.PanicDF(1, "gorm db connection %v not found", tag)
 	return
 }
+
+// HealthCheck pings all configured connections
+func (f *CFeature) HealthCheck(ctx context.Context) (err error) {
+	for _, tag := range maps.SortedKeys(f.conns) {
+		var db *sql.DB
+		if db, err = f.conns[tag].DB(); err != nil {
+			err = fmt.Errorf("gorm db %v error: %v", tag, err)
+			return
+		} else if err = db.PingContext(ctx); err != nil {
+			err = fmt.Errorf("gorm db %v ping error: %v", tag, err)
+			return
+		}
+	}
+	return
+}
@@ -17,8 +17,13 @@
 package gomail
 
 import (
+	"context"
+	"crypto/tls"
 	"fmt"
+	"net"
 	"net/http"
+	"net/smtp"
+	"strconv"
 	"sync"
 
 	"github.com/Shopify/gomail"
@@ -42,18 +47,24 @@ var (
 type Feature interface {
 	feature.Feature
 	feature.EmailSender
+	feature.HealthChecker
 }
 
 type MakeFeature interface {
 	Make() Feature
 
 	AddAccount(name string, cfg SmtpConfig) MakeFeature
+	// SetHealthCheckAccount specifies the account, typically a local relay,
+	// whose SMTP server is checked by HealthCheck; no check is performed when
+	// not set
+	SetHealthCheckAccount(name string) MakeFeature
 }
 
 type CFeature struct {
 	feature.CFeature
 
-	accounts map[string]SmtpConfig
+	accounts      map[string]SmtpConfig
+	healthAccount string
 
 	sync.RWMutex
 }
@@ -81,6 +92,11 @@ func (f *CFeature) AddAccount(key string, cfg SmtpConfig) MakeFeature {
 	return f
 }
 
+func (f *CFeature) SetHealthCheckAccount(name string) MakeFeature {
+	f.healthAccount = name
+	return f
+}
+
 func (f *CFeature) Make() Feature {
 	return f
 }
@@ -132,6 +148,13 @@ func (f *CFeature) Build(b feature.Buildable) (err error) {
 		)
 	}
 	b.AddFlags(accountFlags...)
+	b.AddFlags(&cli.StringFlag{
+		Name:     globals.MakeFlagName(tag, "health-account"),
+		Usage:    "specify the account whose SMTP server is health checked",
+		Category: tag,
+		Value:    f.healthAccount,
+		EnvVars:  globals.MakeFlagEnvKeys(tag, "health-account"),
+	})
 
 	b.AddCommands(&cli.Command{
 		Name:      "test-gomail-send",
@@ -223,6 +246,16 @@ func (f *CFeature) Startup(ctx *cli.Context) (err error) {
 
 		f.accounts[key] = account
 	}
+
+	if flagName := globals.MakeFlagName(tag, "health-account"); ctx.IsSet(flagName) {
+		f.healthAccount = ctx.String(flagName)
+	}
+	if f.healthAccount != "" {
+		if _, present := f.accounts[f.healthAccount]; !present {
+			err = fmt.Errorf("health check account not found: %v", f.healthAccount)
+			return
+		}
+	}
 	return
 }
 
@@ -257,3 +290,47 @@ func (f *CFeature) SendEmail(r *http.Request, account string, message *gomail.Me
 	}()
 	return
 }
+
+// HealthCheck connects to the SMTP server of the health check account, waits
+// for the service greeting and then politely quits without sending anything
+func (f *CFeature) HealthCheck(ctx context.Context) (err error) {
+	if f.healthAccount == "" {
+		return
+	}
+	f.RLock()
+	cfg, present := f.accounts[f.healthAccount]
+	f.RUnlock()
+	if !present {
+		err = fmt.Errorf("smtp account %v not found", f.healthAccount)
+	} else if err = checkSmtpServer(ctx, cfg); err != nil {
+		err = fmt.Errorf("smtp account %v error: %v", f.healthAccount, err)
+	}
+	return
+}
+
+func checkSmtpServer(ctx context.Context, cfg SmtpConfig) (err error) {
+	address := net.JoinHostPort(cfg.Host, strconv.Itoa(cfg.Port))
+
+	var dialer net.Dialer
+	var conn net.Conn
+	if conn, err = dialer.DialContext(ctx, "tcp", address); err != nil {
+		return
+	}
+	defer conn.Close()
+
+	if deadline, ok := ctx.Deadline(); ok {
+		_ = conn.SetDeadline(deadline)
+	}
+
+	if cfg.Port == 465 {
+		// gomail uses implicit TLS for port 465
+		conn = tls.Client(conn, &tls.Config{ServerName: cfg.Host})
+	}
+
+	var client *smtp.Client
+	if client, err = smtp.NewClient(conn, cfg.Host); err != nil {
+		return
+	}
+	err = client.Quit()
+	return
+}
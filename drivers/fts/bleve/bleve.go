@@ -17,6 +17,7 @@
 package bleve
 
 import (
+	"context"
 	"fmt"
 	"net/url"
 	"sync"
@@ -47,6 +48,7 @@ const Tag feature.Tag = "drivers-fts-bleve"
 type Feature interface {
 	feature.Feature
 	feature.SearchEnjinFeature
+	feature.HealthChecker
 }
 
 type CFeature struct {
@@ -100,6 +102,7 @@ func (f *CFeature) Setup(enjin feature.Internals) {
 }
 
 func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	err = f.CFeature.Startup(ctx)
 	return
 }
 
@@ -243,3 +246,23 @@ func (f *CFeature) RemoveFromSearchIndex(stub *feature.PageStub, p feature.Page)
 	}
 	return
 }
+
+// HealthCheck reports the search indexes as not ready until at least one
+// locale has been indexed and all indexes respond to document count queries
+func (f *CFeature) HealthCheck(ctx context.Context) (err error) {
+	f.RLock()
+	defer f.RUnlock()
+	if len(f.indexes) == 0 {
+		err = fmt.Errorf("search index not ready")
+		return
+	}
+	for tag, index := range f.indexes {
+		if err = ctx.Err(); err != nil {
+			return
+		} else if _, err = index.DocCount(); err != nil {
+			err = fmt.Errorf("search index %v error: %v", tag, err)
+			return
+		}
+	}
+	return
+}
@@ -17,18 +17,36 @@
 package gocache
 
 import (
+	"bytes"
+	"context"
 	"fmt"
+	"net/http"
+	"strconv"
+	"sync/atomic"
 	"time"
 
 	"github.com/urfave/cli/v2"
 
 	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/trace"
 )
 
 const (
 	NoExpiration time.Duration = -1
+
+	// HealthCheckKey is the prefix of the unique keys written, read and
+	// deleted by HealthCheck
+	HealthCheckKey = "__enjin-health-check__"
 )
 
+// gHealthCheckCount makes the HealthCheck keys unique for concurrent checks
+var gHealthCheckCount atomic.Uint64
+
+// asyncStore is implemented by stores which apply writes asynchronously
+type asyncStore interface {
+	Wait()
+}
+
 const Tag feature.Tag = "drivers-kvs-gocache"
 
 var (
@@ -44,6 +62,7 @@ var (
 type Feature interface {
 	feature.Feature
 	feature.KeyValueCaches
+	feature.HealthChecker
 }
 
 type MakeFeature interface {
@@ -135,3 +154,60 @@ func (f *CFeature) Get(name string) (kvs feature.KeyValueCache, err error) {
 	err = BucketNotFound
 	return
 }
+
+// HealthCheck performs a set, get and delete round-trip of a unique
+// HealthCheckKey within the first bucket of each configured cache
+func (f *CFeature) HealthCheck(ctx context.Context) (err error) {
+	count := strconv.FormatUint(gHealthCheckCount.Add(1), 10)
+	checkKey := HealthCheckKey + "-" + count
+	value := []byte(time.Now().String() + " " + count)
+	for _, key := range f.order {
+		if err = ctx.Err(); err != nil {
+			return
+		}
+		kvc, _ := f.caches[key]
+		names := kvc.ListBuckets()
+		if len(names) == 0 {
+			continue
+		}
+		var kvs feature.KeyValueStore
+		if kvs, err = kvc.Bucket(names[0]); err != nil {
+			err = fmt.Errorf("gocache %v bucket %v error: %v", key, names[0], err)
+			return
+		} else if err = kvs.Set(checkKey, value); err != nil {
+			err = fmt.Errorf("gocache %v bucket %v set error: %v", key, names[0], err)
+			return
+		}
+		if as, ok := kvs.(asyncStore); ok {
+			as.Wait()
+		}
+		var stored []byte
+		if stored, err = kvs.Get(checkKey); err != nil {
+			err = fmt.Errorf("gocache %v bucket %v get error: %v", key, names[0], err)
+			return
+		} else if !bytes.Equal(stored, value) {
+			err = fmt.Errorf("gocache %v bucket %v round-trip value mismatch", key, names[0])
+			return
+		} else if err = kvs.Delete(checkKey); err != nil {
+			err = fmt.Errorf("gocache %v bucket %v delete error: %v", key, names[0], err)
+			return
+		}
+	}
+	return
+}
+
+// storeTracing scopes the tracing spans of key-value store operations to a
+// request, store methods are otherwise not given any request context and
+// the spans are detached
+type storeTracing struct {
+	r *http.Request
+}
+
+func (t storeTracing) startSpan(operation, key string) (span *trace.Span) {
+	if t.r != nil {
+		_, span = trace.StartRequestChild(t.r, "kvs "+operation, "kvs.key", key)
+		return
+	}
+	_, span = trace.Start(context.Background(), "kvs "+operation, "kvs.key", key)
+	return
+}
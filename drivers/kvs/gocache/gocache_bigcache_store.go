@@ -19,6 +19,7 @@ package gocache
 import (
 	"context"
 	"fmt"
+	"net/http"
 
 	"github.com/allegro/bigcache/v3"
 	gocache "github.com/eko/gocache/lib/v4/cache"
@@ -26,15 +27,28 @@ import (
 	"github.com/go-enjin/be/pkg/feature"
 )
 
-var _ feature.ExtendedKeyValueStore = (*cBigCacheStore)(nil)
+var (
+	_ feature.ExtendedKeyValueStore = (*cBigCacheStore)(nil)
+	_ feature.RequestKeyValueStore  = (*cBigCacheStore)(nil)
+)
 
 type cBigCacheStore struct {
+	storeTracing
+
 	name   string
 	cache  *gocache.Cache[[]byte]
 	client *bigcache.BigCache
 }
 
+func (c *cBigCacheStore) ForRequest(r *http.Request) (kvs feature.KeyValueStore) {
+	scoped := *c
+	scoped.r = r
+	kvs = &scoped
+	return
+}
+
 func (c *cBigCacheStore) Get(key string) (value []byte, err error) {
+	defer c.startSpan("get", key).End()
 	var ok bool
 	var v interface{}
 	if v, err = c.cache.Get(context.Background(), key); err != nil {
@@ -47,11 +61,13 @@ func (c *cBigCacheStore) Get(key string) (value []byte, err error) {
 }
 
 func (c *cBigCacheStore) Set(key string, value []byte) (err error) {
+	defer c.startSpan("set", key).End()
 	err = c.cache.Set(context.Background(), key, value)
 	return
 }
 
 func (c *cBigCacheStore) Delete(key string) (err error) {
+	defer c.startSpan("delete", key).End()
 	err = c.cache.Delete(context.Background(), key)
 	return
 }
@@ -19,6 +19,7 @@ package gocache
 import (
 	"context"
 	"fmt"
+	"net/http"
 	"os"
 	"time"
 
@@ -27,15 +28,27 @@ import (
 	"github.com/go-enjin/be/pkg/feature"
 )
 
-var _ feature.ExtendedKeyValueStore = (*cIMCacheStore)(nil)
+var (
+	_ feature.ExtendedKeyValueStore = (*cIMCacheStore)(nil)
+	_ feature.RequestKeyValueStore  = (*cIMCacheStore)(nil)
+)
 
 type cIMCacheStore struct {
+	storeTracing
+
 	cache *imcache.Sharded[string, []byte]
 
 	expiration time.Duration
 	interval   time.Duration
 }
 
+func (c *cIMCacheStore) ForRequest(r *http.Request) (kvs feature.KeyValueStore) {
+	scoped := *c
+	scoped.r = r
+	kvs = &scoped
+	return
+}
+
 func newIMCacheBucket(expiration, interval time.Duration) (store *cIMCacheStore) {
 	var options []imcache.Option[string, []byte]
 	if expiration > 1 {
@@ -57,6 +70,7 @@ func newIMCacheBucket(expiration, interval time.Duration) (store *cIMCacheStore)
 }
 
 func (c *cIMCacheStore) Get(key string) (value []byte, err error) {
+	defer c.startSpan("get", key).End()
 	var ok bool
 	var v interface{}
 
@@ -72,11 +86,13 @@ func (c *cIMCacheStore) Get(key string) (value []byte, err error) {
 }
 
 func (c *cIMCacheStore) Set(key string, value []byte) (err error) {
+	defer c.startSpan("set", key).End()
 	c.cache.Set(key, value, imcache.WithNoExpiration())
 	return
 }
 
 func (c *cIMCacheStore) Delete(key string) (err error) {
+	defer c.startSpan("delete", key).End()
 	c.cache.Remove(key)
 	return
 }
@@ -18,6 +18,7 @@ package gocache
 
 import (
 	"context"
+	"net/http"
 
 	gocache "github.com/eko/gocache/lib/v4/cache"
 	"github.com/patrickmn/go-cache"
@@ -25,24 +26,39 @@ import (
 	"github.com/go-enjin/be/pkg/feature"
 )
 
-var _ feature.ExtendedKeyValueStore = (*cMemoryStore)(nil)
+var (
+	_ feature.ExtendedKeyValueStore = (*cMemoryStore)(nil)
+	_ feature.RequestKeyValueStore  = (*cMemoryStore)(nil)
+)
 
 type cMemoryStore struct {
+	storeTracing
+
 	client *cache.Cache
 	cache  *gocache.Cache[[]byte]
 }
 
+func (c *cMemoryStore) ForRequest(r *http.Request) (kvs feature.KeyValueStore) {
+	scoped := *c
+	scoped.r = r
+	kvs = &scoped
+	return
+}
+
 func (c *cMemoryStore) Get(key string) (value []byte, err error) {
+	defer c.startSpan("get", key).End()
 	value, err = c.cache.Get(context.Background(), key)
 	return
 }
 
 func (c *cMemoryStore) Set(key string, value []byte) (err error) {
+	defer c.startSpan("set", key).End()
 	err = c.cache.Set(context.Background(), key, value)
 	return
 }
 
 func (c *cMemoryStore) Delete(key string) (err error) {
+	defer c.startSpan("delete", key).End()
 	err = c.cache.Delete(context.Background(), key)
 	return
 }
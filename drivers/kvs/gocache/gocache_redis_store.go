@@ -18,6 +18,7 @@ package gocache
 
 import (
 	"context"
+	"net/http"
 	"strings"
 
 	"github.com/eko/gocache/lib/v4/cache"
@@ -27,9 +28,14 @@ import (
 	"github.com/go-enjin/be/pkg/log"
 )
 
-var _ feature.ExtendedKeyValueStore = (*cRedisStore)(nil)
+var (
+	_ feature.ExtendedKeyValueStore = (*cRedisStore)(nil)
+	_ feature.RequestKeyValueStore  = (*cRedisStore)(nil)
+)
 
 type cRedisStore struct {
+	storeTracing
+
 	tag     string
 	name    string
 	cache   *cache.Cache[string]
@@ -37,6 +43,13 @@ type cRedisStore struct {
 	cluster *redis.ClusterClient
 }
 
+func (c *cRedisStore) ForRequest(r *http.Request) (kvs feature.KeyValueStore) {
+	scoped := *c
+	scoped.r = r
+	kvs = &scoped
+	return
+}
+
 func (c *cRedisStore) MakeKey(prefix string) (key string) {
 	key = prefix + ":" + c.name + ":" + c.tag
 	return
@@ -48,6 +61,7 @@ func (c *cRedisStore) makePattern(prefix string) (pattern string) {
 }
 
 func (c *cRedisStore) Get(key string) (value []byte, err error) {
+	defer c.startSpan("get", key).End()
 	var data string
 	if data, err = c.cache.Get(context.Background(), c.MakeKey(key)); err != nil {
 		return
@@ -57,12 +71,14 @@ func (c *cRedisStore) Get(key string) (value []byte, err error) {
 }
 
 func (c *cRedisStore) Set(key string, value []byte) (err error) {
+	defer c.startSpan("set", key).End()
 	key = c.MakeKey(key)
 	err = c.cache.Set(context.Background(), key, string(value))
 	return
 }
 
 func (c *cRedisStore) Delete(key string) (err error) {
+	defer c.startSpan("delete", key).End()
 	key = c.MakeKey(key)
 	err = c.cache.Delete(context.Background(), key)
 	return
@@ -18,6 +18,7 @@ package gocache
 
 import (
 	"fmt"
+	"net/http"
 	"os"
 
 	"github.com/dgraph-io/ristretto"
@@ -26,12 +27,24 @@ import (
 	"github.com/go-enjin/be/pkg/log"
 )
 
-var _ feature.KeyValueStore = (*cRistrettoStore)(nil)
+var (
+	_ feature.KeyValueStore        = (*cRistrettoStore)(nil)
+	_ feature.RequestKeyValueStore = (*cRistrettoStore)(nil)
+)
 
 type cRistrettoStore struct {
+	storeTracing
+
 	cache *ristretto.Cache
 }
 
+func (c *cRistrettoStore) ForRequest(r *http.Request) (kvs feature.KeyValueStore) {
+	scoped := *c
+	scoped.r = r
+	kvs = &scoped
+	return
+}
+
 func newRistrettoBucket() (store *cRistrettoStore) {
 	var err error
 	store = &cRistrettoStore{}
@@ -46,6 +59,7 @@ func newRistrettoBucket() (store *cRistrettoStore) {
 }
 
 func (c *cRistrettoStore) Get(key string) (value []byte, err error) {
+	defer c.startSpan("get", key).End()
 	var ok bool
 	var v interface{}
 
@@ -61,13 +75,21 @@ func (c *cRistrettoStore) Get(key string) (value []byte, err error) {
 }
 
 func (c *cRistrettoStore) Set(key string, value []byte) (err error) {
+	defer c.startSpan("set", key).End()
 	if !c.cache.Set(key, value, 0) {
 		log.FatalF("ristretto set dropped for key: %v", key)
 	}
 	return
 }
 
+// Wait blocks until all buffered writes are applied, ristretto admits values
+// asynchronously and values may not be visible immediately after a Set
+func (c *cRistrettoStore) Wait() {
+	c.cache.Wait()
+}
+
 func (c *cRistrettoStore) Delete(key string) (err error) {
+	defer c.startSpan("delete", key).End()
 	c.cache.Del(key)
 	return
 }
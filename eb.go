@@ -80,6 +80,7 @@ type EnjinBuilder struct {
 	localeTags  []language.Tag
 	localeNames map[language.Tag]string
 	defaultLang language.Tag
+	fallbacks   lang.FallbackChains
 
 	publicUser  feature.Actions
 	userActions feature.Actions
@@ -170,6 +171,7 @@ func New() (be *EnjinBuilder) {
 	be.hotReload = false
 	be.langMode = lang.NewQueryMode().Make()
 	be.defaultLang = language.Und
+	be.fallbacks = make(lang.FallbackChains)
 	be.publicUser = make(feature.Actions, 0)
 	be.buildPages = make(map[string]string)
 	be.cspModifierFns = make(map[string]feature.CspModifierFn)
@@ -369,12 +371,80 @@ func (eb *EnjinBuilder) Build() feature.Runner {
 			EnvVars:  eb.MakeEnvKeys("LOG_LEVEL"),
 			Category: "general",
 		},
+		&cli.StringFlag{
+			Name:     "log-format",
+			Usage:    "set logging format: pretty, text or json (structured)",
+			EnvVars:  eb.MakeEnvKeys("LOG_FORMAT"),
+			Category: "general",
+		},
+		&cli.StringFlag{
+			Name:     "log-file",
+			Usage:    "write log output to the given file instead of stderr",
+			EnvVars:  eb.MakeEnvKeys("LOG_FILE"),
+			Category: "general",
+		},
+		&cli.IntFlag{
+			Name:     "log-file-max-size",
+			Usage:    "rotate the --log-file when larger than this many megabytes",
+			EnvVars:  eb.MakeEnvKeys("LOG_FILE_MAX_SIZE"),
+			Category: "general",
+		},
+		&cli.DurationFlag{
+			Name:     "log-file-rotate",
+			Usage:    "rotate the --log-file at this interval (ie: 24h)",
+			EnvVars:  eb.MakeEnvKeys("LOG_FILE_ROTATE"),
+			Category: "general",
+		},
+		&cli.IntFlag{
+			Name:     "log-file-max-backups",
+			Usage:    "maximum number of rotated --log-file files to keep",
+			EnvVars:  eb.MakeEnvKeys("LOG_FILE_MAX_BACKUPS"),
+			Category: "general",
+		},
+		&cli.DurationFlag{
+			Name:     "log-file-max-age",
+			Usage:    "maximum age of rotated --log-file files to keep",
+			EnvVars:  eb.MakeEnvKeys("LOG_FILE_MAX_AGE"),
+			Category: "general",
+		},
+		&cli.BoolFlag{
+			Name:     "log-file-compress",
+			Usage:    "gzip compress rotated --log-file files",
+			EnvVars:  eb.MakeEnvKeys("LOG_FILE_COMPRESS"),
+			Category: "general",
+		},
+		&cli.StringSliceFlag{
+			Name:     "lang-fallback",
+			Usage:    "specify one or more language fallback chains, for example: \"pt-BR>pt>es>en\"",
+			EnvVars:  eb.MakeEnvKeys("LANG_FALLBACK"),
+			Category: "general",
+		},
 		&cli.StringSliceFlag{
 			Name:     "domain",
 			Usage:    "restrict inbound requests to only the domain names given",
 			EnvVars:  eb.MakeEnvKeys("DOMAIN"),
 			Category: "service",
 		},
+		&cli.DurationFlag{
+			Name:     "shutdown-timeout",
+			Usage:    "how long to wait for active requests to complete when shutting down",
+			Value:    globals.DefaultShutdownTimeout,
+			EnvVars:  eb.MakeEnvKeys("SHUTDOWN_TIMEOUT"),
+			Category: "service",
+		},
+		&cli.BoolFlag{
+			Name:     "graceful-restart",
+			Usage:    "on SIGUSR2, start a new process inheriting the listening sockets and drain this one",
+			EnvVars:  eb.MakeEnvKeys("GRACEFUL_RESTART"),
+			Category: "service",
+		},
+		&cli.DurationFlag{
+			Name:     "restart-timeout",
+			Usage:    "how long to wait for a gracefully restarted process to become ready",
+			Value:    globals.DefaultRestartTimeout,
+			EnvVars:  eb.MakeEnvKeys("RESTART_TIMEOUT"),
+			Category: "service",
+		},
 		&cli.BoolFlag{
 			Name:     "strict",
 			Usage:    "use strict Slugsums validation (extraneous files are errors)",
@@ -16,6 +16,7 @@ package be
 
 import (
 	"fmt"
+	"reflect"
 
 	"github.com/urfave/cli/v2"
 
@@ -175,6 +176,12 @@ func (eb *EnjinBuilder) AddFeature(features ...feature.Feature) feature.Builder
 		if err := eb.features.Add(f); err != nil {
 			log.FatalDF(1, "error adding feature: %T - %v", f, err)
 		}
+		if rt := reflect.TypeOf(f.This()); rt != nil {
+			if rt.Kind() == reflect.Pointer {
+				rt = rt.Elem()
+			}
+			log.RegisterFeaturePackage(rt.PkgPath(), f.Tag().String(), f.BaseTag().String())
+		}
 		eb.includeFeature(f)
 	}
 	return eb
@@ -35,6 +35,11 @@ func (eb *EnjinBuilder) SiteSupportedLanguages(tags ...language.Tag) feature.Bui
 	return eb
 }
 
+func (eb *EnjinBuilder) SiteLanguageFallbacks(tag language.Tag, fallbacks ...language.Tag) feature.Builder {
+	eb.fallbacks.Set(tag, fallbacks...)
+	return eb
+}
+
 func (eb *EnjinBuilder) SiteLanguageDisplayNames(names map[language.Tag]string) feature.Builder {
 	if eb.localeNames == nil {
 		eb.localeNames = make(map[language.Tag]string)
@@ -0,0 +1,138 @@
+//go:build db_site_users || dbs || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site_users
+
+import (
+	"errors"
+	"net/http"
+	"sort"
+
+	"github.com/maruel/natural"
+	"gorm.io/gorm"
+
+	beErrors "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/signals"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) GroupPresent(group feature.Group) (present bool) {
+	present = f.groupPresent(f.db, group)
+	return
+}
+
+func (f *CFeature) ListGroups(r *http.Request) (groups feature.Groups, err error) {
+	if !userbase.CurrentUserCan(r, f.PermissionAdminGroups) {
+		err = beErrors.ErrPermissionDenied
+		return
+	}
+	var names []string
+	if err = f.groups(f.db).Pluck("name", &names).Error; err != nil {
+		return
+	}
+	sort.Slice(names, func(i, j int) bool {
+		return natural.Less(names[i], names[j])
+	})
+	groups = groups.AppendString(names...)
+	return
+}
+
+func (f *CFeature) CreateGroup(r *http.Request, group feature.Group, permissions ...feature.Action) (err error) {
+
+	if stop := f.Emit(signals.PreCreateGroup, f.Tag().String(), r, group, &permissions); stop {
+		err = beErrors.ErrSignalStopped
+		return
+	} else if !userbase.CurrentUserCan(r, f.PermissionAdminGroups) {
+		err = beErrors.ErrPermissionDenied
+		return
+	}
+
+	actions := feature.Actions(permissions)
+	err = f.db.Transaction(func(tx *gorm.DB) (err error) {
+		if _, err = f.findGroup(tx, group); err == nil {
+			err = beErrors.ErrExistsAlready
+			return
+		} else if !errors.Is(err, beErrors.ErrGroupNotFound) {
+			return
+		}
+		err = f.setGroup(tx, group, actions...)
+		return
+	})
+
+	f.Emit(signals.PostCreateGroup, f.Tag().String(), r, group, actions, err)
+	return
+}
+
+func (f *CFeature) RetrieveGroup(r *http.Request, group feature.Group) (permissions feature.Actions, err error) {
+
+	if stop := f.Emit(signals.PreRetrieveGroup, f.Tag().String(), r, group, &permissions); stop {
+		err = beErrors.ErrSignalStopped
+		return
+	} else if !userbase.CurrentUserCan(r, f.PermissionAdminGroups) {
+		err = beErrors.ErrPermissionDenied
+		return
+	}
+
+	permissions, err = f.getGroup(f.db, group)
+
+	f.Emit(signals.PostRetrieveGroup, f.Tag().String(), r, group, permissions, err)
+	return
+}
+
+func (f *CFeature) UpdateGroup(r *http.Request, group feature.Group, permissions ...feature.Action) (err error) {
+	actions := feature.Actions(permissions)
+
+	if stop := f.Emit(signals.PreUpdateGroup, f.Tag().String(), r, group, &actions); stop {
+		err = beErrors.ErrSignalStopped
+		return
+	} else if !userbase.CurrentUserCan(r, f.PermissionAdminGroups) {
+		err = beErrors.ErrPermissionDenied
+		return
+	}
+
+	err = f.db.Transaction(func(tx *gorm.DB) (err error) {
+		if _, err = f.findGroup(tx, group); err != nil {
+			return
+		}
+		err = f.setGroup(tx, group, actions...)
+		return
+	})
+
+	f.Emit(signals.PostUpdateGroup, f.Tag().String(), r, group, actions, err)
+	return
+}
+
+func (f *CFeature) DeleteGroup(r *http.Request, group feature.Group) (err error) {
+
+	if stop := f.Emit(signals.PreDeleteGroup, f.Tag().String(), r, group); stop {
+		err = beErrors.ErrSignalStopped
+		return
+	} else if !userbase.CurrentUserCan(r, f.PermissionAdminGroups) {
+		err = beErrors.ErrPermissionDenied
+		return
+	}
+
+	var result *gorm.DB
+	if result = f.groups(f.db).Where("name = ?", group.String()).Delete(&Group{}); result.Error != nil {
+		err = result.Error
+	} else if result.RowsAffected == 0 {
+		err = beErrors.ErrGroupNotFound
+	}
+
+	f.Emit(signals.PostDeleteGroup, f.Tag().String(), r, group, err)
+	return
+}
@@ -0,0 +1,59 @@
+//go:build db_site_users || dbs || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site_users
+
+import (
+	"net/http"
+
+	"github.com/go-enjin/be/pkg/request"
+)
+
+var (
+	ReadLocksBucket  = "users-read-lock"
+	WriteLocksBucket = "users-write-lock"
+)
+
+func (f *CFeature) IsUserLocked(r *http.Request, eid string) (locked bool) {
+	locked = f.userLocker.IsLocked()
+	return
+}
+
+func (f *CFeature) LockUser(r *http.Request, eid string) {
+	rid := request.GetRequestID(r)
+	f.userLocker.Lock(rid)
+
+	return
+}
+
+func (f *CFeature) UnlockUser(r *http.Request, eid string) {
+	rid := request.GetRequestID(r)
+	f.userLocker.Unlock(rid)
+	return
+}
+
+func (f *CFeature) RLockUser(r *http.Request, eid string) {
+	rid := request.GetRequestID(r)
+	f.userLocker.RLock(rid)
+
+	return
+}
+
+func (f *CFeature) RUnlockUser(r *http.Request, eid string) {
+	rid := request.GetRequestID(r)
+	f.userLocker.RUnlock(rid)
+	return
+}
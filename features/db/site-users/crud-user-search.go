@@ -0,0 +1,98 @@
+//go:build db_site_users || dbs || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site_users
+
+import (
+	"net/http"
+	"strings"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+)
+
+var (
+	// SortableUserColumns maps the SearchUsers sortBy values to their
+	// database column names
+	SortableUserColumns = map[string]string{
+		"email":   "email",
+		"name":    "name",
+		"origin":  "origin",
+		"active":  "active",
+		"created": "created",
+		"updated": "updated",
+	}
+)
+
+func (f *CFeature) searchUsersScope(query string) func(tx *gorm.DB) *gorm.DB {
+	return func(tx *gorm.DB) *gorm.DB {
+		tx = f.users(tx)
+		if query != "" {
+			like := "%" + escapeLIKE(strings.ToLower(query)) + "%"
+			tx = tx.Where(`LOWER(email) LIKE ? ESCAPE '!' OR LOWER(name) LIKE ? ESCAPE '!' OR eid = ?`, like, like, query)
+		}
+		return tx
+	}
+}
+
+// SearchUsers returns the page of users with email addresses or names
+// containing the query (case-insensitive), or with an exact EID match,
+// ordered by the sortBy column (see SortableUserColumns). An empty query
+// matches all users and numPerPage <= 0 returns all matching users
+func (f *CFeature) SearchUsers(r *http.Request, query, sortBy string, pg, numPerPage int, sortDesc bool) (list []feature.User, total int) {
+	query = strings.TrimSpace(query)
+	column, ok := SortableUserColumns[sortBy]
+	if !ok {
+		column = "email"
+	}
+
+	var count int64
+	if err := f.db.Scopes(f.searchUsersScope(query)).Count(&count).Error; err != nil {
+		log.ErrorRF(r, "error counting %v users: %v", f.Tag(), err)
+		return
+	}
+	total = int(count)
+
+	stmt := f.db.Scopes(f.searchUsersScope(query)).
+		Order(clause.OrderByColumn{Column: clause.Column{Name: column}, Desc: sortDesc})
+	if column != "email" {
+		stmt = stmt.Order(clause.OrderByColumn{Column: clause.Column{Name: "email"}})
+	}
+	if numPerPage > 0 {
+		if pg < 0 {
+			pg = 0
+		}
+		stmt = stmt.Offset(pg * numPerPage).Limit(numPerPage)
+	}
+
+	var users []*User
+	if err := stmt.Find(&users).Error; err != nil {
+		log.ErrorRF(r, "error finding %v users: %v", f.Tag(), err)
+		return
+	}
+
+	for _, u := range users {
+		if au, err := u.toUser(); err == nil {
+			list = append(list, au)
+		} else {
+			log.ErrorRF(r, "error decoding %v user: %v - %v", f.Tag(), u.EID, err)
+		}
+	}
+	return
+}
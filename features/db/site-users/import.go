@@ -0,0 +1,182 @@
+//go:build db_site_users || dbs || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site_users
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	beContext "github.com/go-enjin/be/pkg/context"
+	beErrors "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+	beUser "github.com/go-enjin/be/types/users"
+)
+
+// importAction copies all users and groups from another SiteUsersProvider
+// feature, typically the fs-site-users feature, into the database; user
+// identifiers are re-made with this feature's MakeRealID and MakeEnjinID and
+// the import fails if any enjin ID would change, unless --allow-eid-change
+// is given
+func (f *CFeature) importAction(ctx *cli.Context) (err error) {
+	argv := ctx.Args().Slice()
+	if len(argv) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, f.KebabTag+"-import", 1)
+	}
+
+	if root, ok := f.Enjin.(feature.RootInternals); !ok {
+		err = fmt.Errorf("%T does not implement feature.RootInternals", f.Enjin)
+		return
+	} else if err = root.SetupRootEnjin(ctx); err != nil {
+		return
+	}
+	for _, ef := range f.Enjin.Features().List() {
+		if err = ef.Startup(ctx); err != nil {
+			err = fmt.Errorf("error starting up %q feature: %v", ef.Tag(), err)
+			return
+		}
+	}
+
+	var src feature.SiteUsersProvider
+	if ef, ok := f.Enjin.Features().Get(feature.Tag(argv[0])); !ok {
+		err = fmt.Errorf("%v feature not found", argv[0])
+		return
+	} else if ef.Tag() == f.Tag() {
+		err = fmt.Errorf("cannot import %v from itself", f.Tag())
+		return
+	} else if src, ok = ef.This().(feature.SiteUsersProvider); !ok {
+		err = fmt.Errorf("%v feature is not a feature.SiteUsersProvider", ef.Tag())
+		return
+	}
+
+	// the source provider requires permission to read group details
+	var r *http.Request
+	if r, err = http.NewRequest(http.MethodGet, "/", nil); err != nil {
+		return
+	}
+	permissions := feature.Actions{f.PermissionAdminGroups}
+	if uap, ok := src.This().(feature.UserActionsProvider); ok {
+		permissions = permissions.Append(uap.UserActions()...)
+	}
+	r = userbase.SetCurrentPermissions(r, permissions...)
+
+	overwrite := ctx.Bool("overwrite")
+	users, _ := src.ListUsers(r, 0, -1, false)
+
+	var groups feature.Groups
+	if gp, ok := src.This().(feature.SiteUsersGroupsProvider); ok {
+		if groups, err = gp.ListGroups(r); err != nil {
+			err = fmt.Errorf("error listing %v groups: %v", src.Tag(), err)
+			return
+		}
+	} else {
+		for _, u := range users {
+			groups = groups.Append(u.GetGroups()...)
+		}
+	}
+
+	// everything keyed by enjin ID is orphaned when the enjin ID changes,
+	// typically because the real ID prefixes of the two features differ
+	var changed int
+	for _, u := range users {
+		if eid := f.MakeEnjinID(f.MakeRealID(u.GetEmail())); eid != u.GetEID() {
+			changed += 1
+			log.WarnF("user %q changes enjin id: %v => %v", u.GetEmail(), u.GetEID(), eid)
+		}
+	}
+	if changed > 0 && !ctx.Bool("allow-eid-change") {
+		err = fmt.Errorf("%d of %d users would change enjin id, use the same real id prefix as %v (see SetRealIDPrefix) or --allow-eid-change", changed, len(users), src.Tag())
+		return
+	}
+
+	var importedGroups, importedUsers, skipped int
+	err = f.db.Transaction(func(tx *gorm.DB) (err error) {
+
+		for _, group := range groups {
+			if group == userbase.PublicGroup {
+				continue
+			}
+			var actions feature.Actions
+			if actions, err = src.RetrieveGroup(r, group); errors.Is(err, beErrors.ErrGroupNotFound) {
+				err = nil
+				continue
+			} else if err != nil {
+				err = fmt.Errorf("error retrieving %v group %q: %v", src.Tag(), group, err)
+				return
+			} else if !overwrite && f.groupPresent(tx, group) {
+				skipped += 1
+				continue
+			} else if err = f.setGroup(tx, group, actions...); err != nil {
+				err = fmt.Errorf("error importing group %q: %v", group, err)
+				return
+			}
+			importedGroups += 1
+		}
+
+		for _, u := range users {
+			rid := f.MakeRealID(u.GetEmail())
+			eid := f.MakeEnjinID(rid)
+			au := &beUser.User{
+				RID:         rid,
+				EID:         eid,
+				Name:        u.GetName(),
+				Email:       u.GetEmail(),
+				Image:       u.GetImage(),
+				Origin:      u.GetOrigin(),
+				Groups:      u.GetGroups(),
+				Actions:     u.GetActions(),
+				Context:     u.UnsafeContext(),
+				Active:      u.GetActive(),
+				AdminLocked: u.GetAdminLocked(),
+			}
+			if au.Context == nil {
+				au.Context = beContext.Context{}
+			}
+
+			var existing *User
+			if existing, err = f.findUser(tx, au.EID, true); err == nil {
+				if !overwrite {
+					skipped += 1
+					continue
+				} else if err = existing.fromUser(au); err != nil {
+					return
+				} else if err = f.users(tx).Save(existing).Error; err != nil {
+					err = fmt.Errorf("error updating user %q: %v", au.EID, err)
+					return
+				}
+			} else if !errors.Is(err, beErrors.ErrUserNotFound) {
+				return
+			} else if err = f.insertUser(tx, au); err != nil {
+				err = fmt.Errorf("error importing user %q: %v", au.EID, err)
+				return
+			}
+			importedUsers += 1
+		}
+
+		return
+	})
+
+	if err == nil {
+		log.InfoF("%v imported %d groups and %d users from %v, skipped %d existing", f.Tag(), importedGroups, importedUsers, src.Tag(), skipped)
+	}
+	return
+}
@@ -0,0 +1,198 @@
+//go:build db_site_users || dbs || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site_users
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strings"
+
+	"github.com/mrz1836/go-sanitize"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	beErrors "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/userbase"
+	beUser "github.com/go-enjin/be/types/users"
+)
+
+func (f *CFeature) loadEnvironment() (err error) {
+
+	if named, ok := f.env.GetSiteEnviron("init-group"); ok {
+		for name, value := range named {
+			actions := feature.ParseActions(value)
+			group := feature.Group(name)
+			f.InitGroup(group, actions...)
+		}
+	}
+
+	if initUserEmails, ok := f.env.GetSiteEnviron("init-user-email"); ok {
+		if initUserGroups, ok := f.env.GetSiteEnviron("init-user-group"); ok {
+			for key, spacedEmails := range initUserEmails {
+				if spacedGroups, ok := initUserGroups[key]; ok {
+					var groups feature.Groups
+					for _, name := range strings.Split(spacedGroups, " ") {
+						if name != "" {
+							groups = groups.Append(feature.Group(strings.ToLower(name)))
+						}
+					}
+					for _, value := range strings.Split(spacedEmails, " ") {
+						if email := sanitize.Email(value, false); email != "" {
+							f.InitUser(email, groups...)
+						}
+					}
+				}
+			}
+		}
+	}
+
+	return
+}
+
+func (f *CFeature) users(tx *gorm.DB) *gorm.DB {
+	return tx.Table(f.userTable)
+}
+
+func (f *CFeature) groups(tx *gorm.DB) *gorm.DB {
+	return tx.Table(f.groupTable)
+}
+
+func (f *CFeature) findUser(tx *gorm.DB, eid string, forUpdate bool) (u *User, err error) {
+	stmt := f.users(tx)
+	if forUpdate {
+		// sqlite does not support row-level locking and the dialect omits this clause
+		stmt = stmt.Clauses(clause.Locking{Strength: "UPDATE"})
+	}
+	u = &User{}
+	if err = stmt.Where("eid = ?", eid).First(u).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		u = nil
+		err = beErrors.ErrUserNotFound
+	}
+	return
+}
+
+func (f *CFeature) getUser(tx *gorm.DB, eid string) (au *beUser.User, err error) {
+	var u *User
+	if u, err = f.findUser(tx, eid, false); err != nil {
+		return
+	}
+	au, err = u.toUser()
+	return
+}
+
+func (f *CFeature) insertUser(tx *gorm.DB, au *beUser.User) (err error) {
+	u := &User{}
+	if err = u.fromUser(au); err != nil {
+		return
+	}
+	err = f.users(tx).Create(u).Error
+	return
+}
+
+// updateUser loads the user record within a transaction, calls the given
+// function to modify it and saves the changes, rolling back if either the
+// function or the save return an error
+func (f *CFeature) updateUser(eid string, fn func(au *beUser.User) (err error)) (err error) {
+	err = f.db.Transaction(func(tx *gorm.DB) (err error) {
+		var u *User
+		var au *beUser.User
+		if u, err = f.findUser(tx, eid, true); err != nil {
+			return
+		} else if au, err = u.toUser(); err != nil {
+			return
+		} else if err = fn(au); err != nil {
+			return
+		} else if err = u.fromUser(au); err != nil {
+			return
+		}
+		err = f.users(tx).Save(u).Error
+		return
+	})
+	return
+}
+
+func (f *CFeature) groupPresent(tx *gorm.DB, group feature.Group) (present bool) {
+	var count int64
+	if err := f.groups(tx).Where("name = ?", group.String()).Count(&count).Error; err == nil {
+		present = count > 0
+	}
+	return
+}
+
+func (f *CFeature) findGroup(tx *gorm.DB, group feature.Group) (g *Group, err error) {
+	g = &Group{}
+	if err = f.groups(tx).Where("name = ?", group.String()).First(g).Error; errors.Is(err, gorm.ErrRecordNotFound) {
+		g = nil
+		err = beErrors.ErrGroupNotFound
+	}
+	return
+}
+
+func (f *CFeature) getGroup(tx *gorm.DB, group feature.Group) (permissions feature.Actions, err error) {
+	var g *Group
+	if g, err = f.findGroup(tx, group); err != nil {
+		return
+	}
+	permissions, err = g.toActions()
+	return
+}
+
+// setGroup creates or updates the group with the given permissions
+func (f *CFeature) setGroup(tx *gorm.DB, group feature.Group, permissions ...feature.Action) (err error) {
+	var data []byte
+	if data, err = json.Marshal(feature.Actions(permissions)); err != nil {
+		return
+	}
+	var g *Group
+	if g, err = f.findGroup(tx, group); errors.Is(err, beErrors.ErrGroupNotFound) {
+		err = f.groups(tx).Create(&Group{Name: group.String(), Actions: data}).Error
+		return
+	} else if err != nil {
+		return
+	}
+	g.Actions = data
+	err = f.groups(tx).Save(g).Error
+	return
+}
+
+func (f *CFeature) getCheckUserPerm(uid, eid string, self, other feature.Action) (need feature.Action) {
+	if uid == eid {
+		need = self
+	} else {
+		need = other
+	}
+	return
+}
+
+func (f *CFeature) checkUserCan(r *http.Request, uid, eid string, self, other feature.Action, more ...feature.Action) (allowed bool) {
+	actions := feature.Actions{
+		f.getCheckUserPerm(uid, eid, self, other),
+	}
+	allowed = userbase.CurrentUserCanAll(r, actions.Append(more...)...)
+	return
+}
+
+// escapeLIKE escapes the LIKE wildcards within the input using "!", which
+// is portable across dialects when used with: ESCAPE '!'
+func escapeLIKE(input string) (escaped string) {
+	escaped = strings.ReplaceAll(input, `!`, `!!`)
+	escaped = strings.ReplaceAll(escaped, `%`, `!%`)
+	escaped = strings.ReplaceAll(escaped, `_`, `!_`)
+	return
+}
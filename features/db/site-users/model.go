@@ -0,0 +1,122 @@
+//go:build db_site_users || dbs || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site_users
+
+import (
+	"encoding/json"
+	"time"
+
+	"gorm.io/datatypes"
+
+	beContext "github.com/go-enjin/be/pkg/context"
+	"github.com/go-enjin/be/pkg/feature"
+	beUser "github.com/go-enjin/be/types/users"
+)
+
+// User is the database model for site users
+type User struct {
+	ID uint `gorm:"primaryKey"`
+
+	RID    string `gorm:"column:rid;not null;unique;index"`
+	EID    string `gorm:"column:eid;not null;unique;index"`
+	Name   string `gorm:"column:name;not null;index"`
+	Email  string `gorm:"column:email;not null;index"`
+	Image  string `gorm:"column:image"`
+	Origin string `gorm:"column:origin;not null;index"`
+
+	// Groups is named user_groups because "groups" is a reserved word in
+	// some SQL dialects
+	Groups  datatypes.JSON `gorm:"column:user_groups"`
+	Actions datatypes.JSON `gorm:"column:actions"`
+	Context datatypes.JSON `gorm:"column:context"`
+
+	Active      bool `gorm:"column:active;not null;index"`
+	AdminLocked bool `gorm:"column:admin_locked;not null;index"`
+
+	CreatedAt time.Time `gorm:"column:created;index"`
+	UpdatedAt time.Time `gorm:"column:updated;index"`
+}
+
+// Group is the database model for site user groups
+type Group struct {
+	ID uint `gorm:"primaryKey"`
+
+	Name    string         `gorm:"column:name;not null;unique;index"`
+	Actions datatypes.JSON `gorm:"column:actions"`
+
+	CreatedAt time.Time `gorm:"column:created;index"`
+	UpdatedAt time.Time `gorm:"column:updated;index"`
+}
+
+func (u *User) toUser() (au *beUser.User, err error) {
+	au = &beUser.User{
+		RID:         u.RID,
+		EID:         u.EID,
+		Name:        u.Name,
+		Email:       u.Email,
+		Image:       u.Image,
+		Origin:      u.Origin,
+		Groups:      feature.Groups{},
+		Actions:     feature.Actions{},
+		Context:     beContext.Context{},
+		Active:      u.Active,
+		AdminLocked: u.AdminLocked,
+	}
+	if len(u.Groups) > 0 {
+		if err = json.Unmarshal(u.Groups, &au.Groups); err != nil {
+			return
+		}
+	}
+	if len(u.Actions) > 0 {
+		if err = json.Unmarshal(u.Actions, &au.Actions); err != nil {
+			return
+		}
+	}
+	if len(u.Context) > 0 {
+		if err = json.Unmarshal(u.Context, &au.Context); err != nil {
+			return
+		}
+	}
+	return
+}
+
+func (u *User) fromUser(au *beUser.User) (err error) {
+	u.RID = au.RID
+	u.EID = au.EID
+	u.Name = au.Name
+	u.Email = au.Email
+	u.Image = au.Image
+	u.Origin = au.Origin
+	u.Active = au.Active
+	u.AdminLocked = au.AdminLocked
+	if u.Groups, err = json.Marshal(au.Groups); err != nil {
+		return
+	} else if u.Actions, err = json.Marshal(au.Actions); err != nil {
+		return
+	} else if u.Context, err = json.Marshal(au.Context); err != nil {
+		return
+	}
+	return
+}
+
+func (g *Group) toActions() (actions feature.Actions, err error) {
+	actions = feature.Actions{}
+	if len(g.Actions) > 0 {
+		err = json.Unmarshal(g.Actions, &actions)
+	}
+	return
+}
@@ -0,0 +1,315 @@
+//go:build db_site_users || dbs || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package site_users
+
+import (
+	"fmt"
+
+	"github.com/mrz1836/go-sanitize"
+	"github.com/urfave/cli/v2"
+	"gorm.io/gorm"
+
+	sha "github.com/go-corelibs/shasum"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/feature/signaling"
+	site_environ "github.com/go-enjin/be/pkg/feature/site-environ"
+	uses_actions "github.com/go-enjin/be/pkg/feature/uses-actions"
+	uses_enjin_salt "github.com/go-enjin/be/pkg/feature/uses-enjin-salt"
+	uses_kvc "github.com/go-enjin/be/pkg/feature/uses-kvc"
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/maps"
+	beUser "github.com/go-enjin/be/types/users"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "db-site-users"
+
+type Feature interface {
+	feature.Feature
+	feature.SiteUsersProvider
+	feature.SiteUsersSearchProvider
+	feature.SiteUsersGroupsProvider
+	signaling.Signaling
+}
+
+type MakeFeature interface {
+	uses_kvc.MakeFeature[MakeFeature]
+
+	// SetConnection specifies the drivers/db/gorm connection name to use
+	SetConnection(name string) MakeFeature
+
+	// InitGroup will create the specific group on startup, does nothing if the group already exists
+	InitGroup(group feature.Group, actions ...feature.Action) MakeFeature
+
+	// SetRealIDPrefix specifies the prefix used when making real identifiers (RID) and defaults to the feature's kebab
+	// tag, set this to the tag of a previous site users feature (along with the same enjin salt) to preserve existing
+	// Enjin identifiers
+	SetRealIDPrefix(prefix string) MakeFeature
+
+	// SetEnjinSalt specifies the default random string to use for making new Enjin identifiers (EID) and is overridden
+	// by the corresponding command-line flag value (--db-site-users-enjin-salt)
+	SetEnjinSalt(value string) MakeFeature
+
+	Make() Feature
+}
+
+type CFeature struct {
+	feature.CFeature
+	signaling.CSignaling
+	uses_kvc.CUsesKVC[MakeFeature]
+	uses_actions.CUsesActions
+
+	env *site_environ.CSiteEnviron[MakeFeature]
+
+	enjinSalt *uses_enjin_salt.CUsesEnjinSalt
+
+	initGroups map[feature.Group]feature.Actions
+	initUsers  map[string]feature.Groups
+
+	ridPrefix  string
+	connection string
+	db         *gorm.DB
+	userTable  string
+	groupTable string
+
+	PermissionViewOwn     feature.Action
+	PermissionViewOther   feature.Action
+	PermissionUpdateOwn   feature.Action
+	PermissionUpdateOther feature.Action
+	PermissionDeleteOwn   feature.Action
+	PermissionDeleteOther feature.Action
+
+	PermissionSignUpUser  feature.Action
+	PermissionCreateUser  feature.Action
+	PermissionAdminPerms  feature.Action
+	PermissionAdminGroups feature.Action
+
+	userLocker feature.SyncRWLocker
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.Construct(f)
+	return f
+}
+
+func (f *CFeature) UsageNotes() (notes []string) {
+	notes = f.env.SiteEnvironUsageNotes()
+	return
+}
+
+func (f *CFeature) Construct(this interface{}) {
+	f.CFeature.Construct(this)
+	f.CUsesActions.ConstructUsesActions(f)
+	f.enjinSalt = uses_enjin_salt.New(this)
+	f.PermissionViewOwn = f.Action("view-own", "user")
+	f.PermissionViewOther = f.Action("view-other", "user")
+	f.PermissionUpdateOwn = f.Action("update-own", "user")
+	f.PermissionUpdateOther = f.Action("update-other", "user")
+	f.PermissionDeleteOwn = f.Action("delete-own", "user")
+	f.PermissionDeleteOther = f.Action("delete-other", "user")
+	f.PermissionSignUpUser = f.Action("sign-up", "user")
+	f.PermissionCreateUser = f.Action("create", "user")
+	f.PermissionAdminPerms = f.Action("admin-perms", "user")
+	f.PermissionAdminGroups = f.Action("admin-groups", "user")
+	return
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.CSignaling.InitSignaling()
+	f.CUsesKVC.InitUsesKVC(this)
+	f.initGroups = make(map[feature.Group]feature.Actions)
+	f.initUsers = make(map[string]feature.Groups)
+	f.env = site_environ.New[MakeFeature](this,
+		"init-group", "space separated permissions",
+		"init-user-email", "email addresses of users to have groups assigned",
+		"init-user-group", "space separated group names",
+	)
+	return
+}
+
+func (f *CFeature) SetConnection(name string) MakeFeature {
+	f.connection = name
+	return f
+}
+
+func (f *CFeature) SetRealIDPrefix(prefix string) MakeFeature {
+	f.ridPrefix = prefix
+	return f
+}
+
+func (f *CFeature) InitGroup(group feature.Group, actions ...feature.Action) MakeFeature {
+	f.initGroups[group] = f.initGroups[group].Append(actions...)
+	return f
+}
+
+func (f *CFeature) InitUser(email string, group ...feature.Group) MakeFeature {
+	if email = sanitize.Email(email, false); email != "" {
+		f.initUsers[email] = f.initUsers[email].Append(group...)
+	}
+	return f
+}
+
+func (f *CFeature) SetEnjinSalt(value string) MakeFeature {
+	f.enjinSalt.SetEnjinSalt(value)
+	return f
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	if f.connection == "" {
+		err = fmt.Errorf("calling .SetConnection on this feature is required")
+		return
+	} else if err = f.CFeature.Build(b); err != nil {
+		return
+	} else if err = f.CUsesKVC.BuildUsesKVC(); err != nil {
+		return
+	} else if err = f.enjinSalt.BuildEnjinSalt(b); err != nil {
+		return
+	}
+
+	b.AddCommands(&cli.Command{
+		Name:  f.KebabTag + "-import",
+		Usage: "import all users and groups from another site users feature",
+		Description: "User identifiers are re-made with this feature's real ID prefix and the\n" +
+			"import fails if any user's enjin ID would change, use SetRealIDPrefix to\n" +
+			"match the other feature or --allow-eid-change to import anyway.",
+		UsageText: globals.BinName + " " + f.KebabTag + "-import [options] <feature-tag>",
+		Flags: []cli.Flag{
+			&cli.BoolFlag{
+				Name:  "overwrite",
+				Usage: "replace existing users and groups instead of skipping them",
+			},
+			&cli.BoolFlag{
+				Name:  "allow-eid-change",
+				Usage: "import users even when their enjin ids change, orphaning everything keyed by the previous enjin ids",
+			},
+		},
+		Action: f.importAction,
+	})
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	} else if err = f.CUsesKVC.StartupUsesKVC(f.Enjin.Features()); err != nil {
+		return
+	} else if err = f.enjinSalt.StartupEnjinSalt(ctx); err != nil {
+		return
+	} else if err = f.env.StartupSiteEnviron(); err != nil {
+		return
+	} else if err = f.loadEnvironment(); err != nil {
+		return
+	}
+
+	readStore := f.KVC().MustBucket(ReadLocksBucket)
+	writeStore := f.KVC().MustBucket(WriteLocksBucket)
+	f.userLocker = f.Enjin.NewSyncRWLocker(f.Tag(), "user-lock", readStore, writeStore)
+
+	var ok bool
+	if v := f.Enjin.MustDB(f.connection); v == nil {
+		err = fmt.Errorf("database connection not found: %v", f.connection)
+		return
+	} else if f.db, ok = v.(*gorm.DB); !ok {
+		err = fmt.Errorf("connection error: %v; expected *gorm.DB, found %T", f.connection, v)
+		return
+	}
+
+	f.userTable = f.Tag().Snake() + "_users"
+	f.groupTable = f.Tag().Snake() + "_groups"
+
+	if err = f.db.Table(f.userTable).AutoMigrate(&User{}); err != nil {
+		err = fmt.Errorf("error migrating %v table: %v", f.userTable, err)
+		return
+	} else if err = f.db.Table(f.groupTable).AutoMigrate(&Group{}); err != nil {
+		err = fmt.Errorf("error migrating %v table: %v", f.groupTable, err)
+		return
+	}
+
+	for _, group := range maps.SortedKeys(f.initGroups) {
+		var actions feature.Actions
+		if f.GroupPresent(group) {
+			if actions, err = f.getGroup(f.db, group); err != nil {
+				err = fmt.Errorf("error getting group permissions %q: %v", group, err)
+				return
+			}
+		}
+		actions = actions.Append(f.initGroups[group]...)
+		if err = f.setGroup(f.db, group, actions...); err != nil {
+			err = fmt.Errorf("error initializing group %q: %v", group, err)
+			return
+		}
+	}
+
+	for _, email := range maps.SortedKeys(f.initUsers) {
+		rid := f.MakeRealID(email)
+		eid := f.MakeEnjinID(rid)
+		groups := f.initUsers[email]
+		_ = f.updateUser(eid, func(au *beUser.User) (err error) {
+			au.Groups = au.Groups.Append(groups...)
+			return
+		})
+	}
+
+	return
+}
+
+func (f *CFeature) UserActions() (list feature.Actions) {
+
+	list = feature.Actions{
+		f.PermissionViewOwn,
+		f.PermissionViewOther,
+		f.PermissionUpdateOwn,
+		f.PermissionUpdateOther,
+		f.PermissionDeleteOwn,
+		f.PermissionDeleteOther,
+		f.PermissionAdminPerms,
+		f.PermissionAdminGroups,
+	}
+
+	return
+}
+
+func (f *CFeature) MakeRealID(email string) (rid string) {
+	if f.ridPrefix != "" {
+		rid = f.ridPrefix + "_" + email
+		return
+	}
+	rid = f.KebabTag + "_" + email
+	return
+}
+
+func (f *CFeature) MakeEnjinID(rid string) (eid string) {
+	eid, _ = sha.BriefSum(f.enjinSalt.GetEnjinSalt() + rid)
+	return
+}
@@ -18,6 +18,7 @@ import (
 	"encoding/json"
 	"net/http"
 
+	clPath "github.com/go-corelibs/path"
 	beErrors "github.com/go-enjin/be/pkg/errors"
 	"github.com/go-enjin/be/pkg/feature"
 	"github.com/go-enjin/be/pkg/signals"
@@ -123,3 +124,14 @@ func (f *CFeature) DeleteGroup(r *http.Request, group feature.Group) (err error)
 	f.Emit(signals.PostDeleteGroup, f.Tag().String(), r, group, err)
 	return
 }
+
+func (f *CFeature) ListGroups(r *http.Request) (groups feature.Groups, err error) {
+	if !userbase.CurrentUserCan(r, f.PermissionAdminGroups) {
+		err = beErrors.ErrPermissionDenied
+		return
+	}
+	for _, file := range f.MountPoints.ListFiles(f.groupPath) {
+		groups = groups.Append(feature.Group(clPath.Base(file)))
+	}
+	return
+}
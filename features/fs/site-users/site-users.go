@@ -47,6 +47,7 @@ const Tag feature.Tag = "fs-site-users"
 type Feature interface {
 	feature.Feature
 	feature.SiteUsersProvider
+	feature.SiteUsersGroupsProvider
 	signaling.Signaling
 }
 
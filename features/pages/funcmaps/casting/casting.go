@@ -77,6 +77,7 @@ func (f *CFeature) Build(b feature.Buildable) (err error) {
 }
 
 func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	err = f.CFeature.Startup(ctx)
 	return
 }
 
@@ -30,6 +30,7 @@ import (
 	beContext "github.com/go-enjin/be/pkg/context"
 	"github.com/go-enjin/be/pkg/feature"
 	"github.com/go-enjin/be/pkg/lang"
+	"github.com/go-enjin/be/pkg/lang/icu"
 	"github.com/go-enjin/be/pkg/log"
 )
 
@@ -80,12 +81,15 @@ func (f *CFeature) Build(b feature.Buildable) (err error) {
 }
 
 func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	err = f.CFeature.Startup(ctx)
 	return
 }
 
 func (f *CFeature) MakeFuncMap(ctx beContext.Context) (fm feature.FuncMap) {
 	fm = feature.FuncMap{
-		"cmpLang": CmpLang,
+		"cmpLang":  CmpLang,
+		"_select":  icu.Choose,
+		"_ordinal": icu.Ordinal,
 	}
 	if f.Enjin != nil {
 		fm["_"] = f.makeUnderscore(ctx)
@@ -35,6 +35,7 @@ import (
 	"github.com/go-enjin/be/pkg/globals"
 	"github.com/go-enjin/be/pkg/log"
 	"github.com/go-enjin/be/pkg/request/argv"
+	"github.com/go-enjin/be/pkg/trace"
 )
 
 var (
@@ -238,7 +239,11 @@ func (f *CFeature) ProcessRequestPageType(r *http.Request, p feature.Page) (pg f
 
 		if input != "" {
 			// perform search
-			if results, err := f.search.PerformSearch(reqLangTag, query, numPerPage, pageIndex); err != nil {
+			_, span := trace.StartRequestChild(r, "search "+f.search.Tag().String(), "search.query", query)
+			results, err := f.search.PerformSearch(reqLangTag, query, numPerPage, pageIndex)
+			span.SetError(err)
+			span.End()
+			if err != nil {
 				p.Context().SetSpecific("SiteSearchError", err.Error())
 			} else {
 				numPages := int(math.Ceil(float64(results.Total) / float64(numPerPage)))
@@ -0,0 +1,209 @@
+//go:build requests_cors || requests || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors
+
+import (
+	"net/http"
+	"sort"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net/headers/policy/cors"
+)
+
+const Tag feature.Tag = "requests-cors"
+
+// PageContextKey is the page front-matter key for page-specific CORS config,
+// which is merged with any matching path rule; only path rules can allow
+// credentials
+const PageContextKey = "cors"
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+type MakeFeature interface {
+	Make() Feature
+
+	// AddRule configures the CORS policy for all request paths starting with
+	// the given prefix, the longest matching prefix is used for each request
+	AddRule(prefix string, config cors.Config) MakeFeature
+	// ParseRule is a convenience wrapper around cors.ParseConfig and AddRule
+	ParseRule(prefix string, ctx map[string]interface{}) MakeFeature
+}
+
+type Feature interface {
+	feature.Feature
+	feature.UseMiddleware
+}
+
+type CFeature struct {
+	feature.CFeature
+
+	rules    map[string]cors.Config
+	prefixes []string
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.rules = make(map[string]cors.Config)
+}
+
+func (f *CFeature) AddRule(prefix string, config cors.Config) MakeFeature {
+	if prefix == "" {
+		prefix = "/"
+	}
+	if existing, present := f.rules[prefix]; present {
+		config = existing.Merge(config)
+	} else {
+		f.prefixes = append(f.prefixes, prefix)
+		// longest prefixes first
+		sort.SliceStable(f.prefixes, func(i, j int) bool {
+			return len(f.prefixes[i]) > len(f.prefixes[j])
+		})
+	}
+	f.rules[prefix] = config
+	return f
+}
+
+func (f *CFeature) ParseRule(prefix string, ctx map[string]interface{}) MakeFeature {
+	config, err := cors.ParseConfig(ctx)
+	if err != nil {
+		log.FatalDF(1, "error parsing %q cors rule: %v", prefix, err)
+	}
+	return f.AddRule(prefix, config)
+}
+
+func (f *CFeature) Make() Feature {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	envPrefix := f.Tag().ScreamingSnake()
+	b.AddFlags(
+		&cli.StringSliceFlag{
+			Name:     f.KebabTag + "-allow-origins",
+			Usage:    "origins allowed for all request paths (exact, wildcard or /regexp/)",
+			EnvVars:  b.MakeEnvKeys(envPrefix, "ALLOW_ORIGINS"),
+			Category: f.KebabTag,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+
+	if key := f.KebabTag + "-allow-origins"; ctx.IsSet(key) {
+		var config cors.Config
+		for _, value := range ctx.StringSlice(key) {
+			if value = strings.TrimSpace(value); value == "" {
+				continue
+			}
+			var origin cors.Origin
+			if origin, err = cors.ParseOrigin(value); err != nil {
+				return
+			}
+			config.AllowOrigins = append(config.AllowOrigins, origin)
+		}
+		f.AddRule("/", config)
+	}
+
+	log.DebugF("%v - cors rules configured for: %v", f.Tag(), f.prefixes)
+	return
+}
+
+func (f *CFeature) Use(s feature.System) (fn feature.MiddlewareFn) {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Header.Get("Origin") == "" {
+				// not a cross-origin request
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			config, found := f.findConfig(r)
+			if !found {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				// preflight requests are answered before the page router
+				if !config.ApplyPreflight(w, r) {
+					log.DebugRF(r, "cors preflight denied for origin: %q", r.Header.Get("Origin"))
+				}
+				f.Enjin.Serve204(w, r)
+				return
+			}
+
+			if !config.ApplyHeaders(w, r) {
+				log.DebugRF(r, "cors request denied for origin: %q", r.Header.Get("Origin"))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// findConfig returns the longest matching path rule merged with any page
+// front-matter config
+func (f *CFeature) findConfig(r *http.Request) (config cors.Config, found bool) {
+	for _, prefix := range f.prefixes {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			config = f.rules[prefix]
+			break
+		}
+	}
+
+	if pg := f.Enjin.FindPage(r, message.GetTag(r), r.URL.Path); pg != nil {
+		if ctxCors, ok := pg.Context().Get(PageContextKey).(map[string]interface{}); ok {
+			if parsed, err := cors.ParseConfig(ctxCors); err != nil {
+				log.ErrorRF(r, "%v page cors errors:\n%v", pg.Url(), err)
+			} else {
+				if parsed.AllowCredentials {
+					// only the path rules can allow credentials
+					log.WarnRF(r, "%v page cors allow-credentials ignored", pg.Url())
+					parsed.AllowCredentials = false
+				}
+				config = config.Merge(parsed)
+			}
+		}
+	}
+
+	found = !config.IsEmpty()
+	return
+}
@@ -0,0 +1,127 @@
+//go:build requests_csrf || requests || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csrf
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"html"
+	"html/template"
+	"net/http"
+	"strings"
+
+	beContext "github.com/go-enjin/be/pkg/context"
+	"github.com/go-enjin/be/pkg/crypto"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/request"
+)
+
+const (
+	// KeyCsrfToken is the request context key for the current CSRF token
+	KeyCsrfToken request.Key = "csrf-token"
+
+	gTokenSize = 32
+)
+
+// GetRequestToken returns the CSRF token for the given request, which is only
+// present when the csrf feature middleware has processed the request
+func GetRequestToken(r *http.Request) (token string) {
+	token, _ = request.String(r, KeyCsrfToken)
+	return
+}
+
+func setRequestToken(r *http.Request, token string) (modified *http.Request) {
+	modified = request.Set(r, KeyCsrfToken, token)
+	return
+}
+
+func isUnsafeMethod(method string) (unsafe bool) {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodTrace:
+		return false
+	}
+	return true
+}
+
+// isExempt returns true for requests authenticated with "Authorization: Bearer"
+// headers (which are not sent automatically by browsers) and for request paths
+// starting with any of the exempt prefixes
+func (f *CFeature) isExempt(r *http.Request) (exempt bool) {
+	if scheme, _, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+		return true
+	}
+	for _, prefix := range f.exempt {
+		if strings.HasPrefix(r.URL.Path, prefix) {
+			return true
+		}
+	}
+	return
+}
+
+func (f *CFeature) getCookieToken(r *http.Request) (token string, present bool) {
+	if cookie, err := r.Cookie(f.cookieName); err == nil && len(cookie.Value) == gTokenSize*2 {
+		token, present = cookie.Value, true
+	}
+	return
+}
+
+func (f *CFeature) issueToken(w http.ResponseWriter, r *http.Request) (token string, err error) {
+	if token, err = crypto.RandomValue(gTokenSize); err != nil {
+		return
+	}
+	http.SetCookie(w, &http.Cookie{
+		Name:     f.cookieName,
+		Value:    token,
+		Path:     "/",
+		Secure:   f.secureCookies || r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	})
+	return
+}
+
+// validRequestToken compares the cookie token with the one submitted in either
+// the request header or the form field
+func (f *CFeature) validRequestToken(token string, r *http.Request) (valid bool) {
+	submitted := r.Header.Get(f.headerName)
+	if submitted == "" {
+		submitted = r.FormValue(f.fieldName)
+	}
+	valid = submitted != "" && subtle.ConstantTimeCompare([]byte(submitted), []byte(token)) == 1
+	return
+}
+
+func (f *CFeature) MakeFuncMap(ctx beContext.Context) (fm feature.FuncMap) {
+	r, _ := ctx.Get("R").(*http.Request)
+	fm = feature.FuncMap{
+		"CsrfToken": func() (token string) {
+			token = GetRequestToken(r)
+			return
+		},
+		"CsrfField": func() (field template.HTML) {
+			if token := GetRequestToken(r); token != "" {
+				field = template.HTML(fmt.Sprintf(
+					`<input type="hidden" name="%s" value="%s" />`,
+					html.EscapeString(f.fieldName),
+					html.EscapeString(token),
+				))
+			}
+			return
+		},
+	}
+	return
+}
@@ -0,0 +1,217 @@
+//go:build requests_csrf || requests || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csrf
+
+import (
+	"net/http"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+)
+
+const Tag feature.Tag = "requests-csrf"
+
+const (
+	DefaultCookieName = "enjin-csrf-token"
+	DefaultHeaderName = "X-CSRF-Token"
+	DefaultFieldName  = "_csrf"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+type MakeFeature interface {
+	Make() Feature
+
+	// SetCookieName overrides the DefaultCookieName
+	SetCookieName(name string) MakeFeature
+	// SetHeaderName overrides the DefaultHeaderName
+	SetHeaderName(name string) MakeFeature
+	// SetFieldName overrides the DefaultFieldName
+	SetFieldName(name string) MakeFeature
+	// SetSecureCookies configures the Secure flag of the token cookie
+	SetSecureCookies(secure bool) MakeFeature
+
+	// Exempt excludes request paths starting with any of the given prefixes
+	// from token validation
+	Exempt(prefixes ...string) MakeFeature
+}
+
+type Feature interface {
+	feature.Feature
+	feature.UseMiddleware
+	feature.FuncMapProvider
+}
+
+type CFeature struct {
+	feature.CFeature
+
+	cookieName    string
+	headerName    string
+	fieldName     string
+	secureCookies bool
+	exempt        []string
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.cookieName = DefaultCookieName
+	f.headerName = DefaultHeaderName
+	f.fieldName = DefaultFieldName
+}
+
+func (f *CFeature) SetCookieName(name string) MakeFeature {
+	f.cookieName = name
+	return f
+}
+
+func (f *CFeature) SetHeaderName(name string) MakeFeature {
+	f.headerName = name
+	return f
+}
+
+func (f *CFeature) SetFieldName(name string) MakeFeature {
+	f.fieldName = name
+	return f
+}
+
+func (f *CFeature) SetSecureCookies(secure bool) MakeFeature {
+	f.secureCookies = secure
+	return f
+}
+
+func (f *CFeature) Exempt(prefixes ...string) MakeFeature {
+	f.exempt = append(f.exempt, prefixes...)
+	return f
+}
+
+func (f *CFeature) Make() Feature {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	envPrefix := f.Tag().ScreamingSnake()
+	b.AddFlags(
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-cookie-name",
+			Usage:    "specify the name of the CSRF token cookie",
+			Value:    f.cookieName,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "COOKIE_NAME"),
+			Category: f.KebabTag,
+		},
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-header-name",
+			Usage:    "specify the name of the CSRF token request header",
+			Value:    f.headerName,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "HEADER_NAME"),
+			Category: f.KebabTag,
+		},
+		&cli.BoolFlag{
+			Name:     f.KebabTag + "-secure-cookies",
+			Usage:    "set the Secure flag on CSRF token cookies",
+			Value:    f.secureCookies,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "SECURE_COOKIES"),
+			Category: f.KebabTag,
+		},
+		&cli.StringSliceFlag{
+			Name:     f.KebabTag + "-exempt",
+			Usage:    "request path prefixes excluded from CSRF token validation",
+			EnvVars:  b.MakeEnvKeys(envPrefix, "EXEMPT"),
+			Category: f.KebabTag,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+
+	if key := f.KebabTag + "-cookie-name"; ctx.IsSet(key) {
+		if v := strings.TrimSpace(ctx.String(key)); v != "" {
+			f.cookieName = v
+		}
+	}
+
+	if key := f.KebabTag + "-header-name"; ctx.IsSet(key) {
+		if v := strings.TrimSpace(ctx.String(key)); v != "" {
+			f.headerName = v
+		}
+	}
+
+	if key := f.KebabTag + "-secure-cookies"; ctx.IsSet(key) {
+		f.secureCookies = ctx.Bool(key)
+	}
+
+	if key := f.KebabTag + "-exempt"; ctx.IsSet(key) {
+		for _, prefix := range ctx.StringSlice(key) {
+			if prefix = strings.TrimSpace(prefix); prefix != "" {
+				f.exempt = append(f.exempt, prefix)
+			}
+		}
+	}
+
+	log.DebugF("%v - cookie=%q, header=%q, field=%q, exempt=%v", f.Tag(), f.cookieName, f.headerName, f.fieldName, f.exempt)
+	return
+}
+
+func (f *CFeature) Use(s feature.System) feature.MiddlewareFn {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+
+			token, present := f.getCookieToken(r)
+			if !present {
+				var err error
+				if token, err = f.issueToken(w, r); err != nil {
+					log.ErrorRF(r, "error issuing csrf token: %v", err)
+					f.Enjin.ServeInternalServerError(w, r)
+					return
+				}
+			}
+			r = setRequestToken(r, token)
+
+			if isUnsafeMethod(r.Method) && !f.isExempt(r) {
+				if !present || !f.validRequestToken(token, r) {
+					log.WarnRF(r, "csrf token validation failed: %v %v", r.Method, r.URL.Path)
+					f.Enjin.ServeForbidden(w, r)
+					return
+				}
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -54,6 +54,7 @@ type MakeFeature interface {
 type Feature interface {
 	feature.Feature
 	feature.UseMiddleware
+	feature.RequestDenier
 }
 
 type CFeature struct {
@@ -181,6 +182,14 @@ func (f *CFeature) Use(s feature.System) (fn feature.MiddlewareFn) {
 	}
 }
 
+// DenyAddress blocks the given address for the configured deny duration
+func (f *CFeature) DenyAddress(address string) {
+	if address != "" {
+		f.manager.Deny(address)
+		log.DebugF("%v - address denied: %v", f.Tag(), address)
+	}
+}
+
 func (f *CFeature) CheckRequestDenied(req *http.Request) (address string, denied bool) {
 	var err error
 	var addr string
@@ -109,6 +109,15 @@ func (f *CFeature) PrepareServePage(ctx context.Context, t feature.Theme, p feat
 	ctx.SetSpecific("Language", parsedTag.String())
 	ctx.SetSpecific("LanguageTag", parsedTag)
 
+	// LanguageFallback is true when the page is not available in the
+	// requested language and is being served in one of its fallbacks
+	var fallback bool
+	if !language.Compare(parsedTag, language.Und) {
+		fallback = !language.Compare(reqLangTag, language.Und) && !language.Compare(parsedTag, reqLangTag)
+		w.Header().Set("Content-Language", parsedTag.String())
+	}
+	ctx.SetSpecific("LanguageFallback", fallback)
+
 	out = ctx
 	return
 }
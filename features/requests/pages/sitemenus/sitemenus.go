@@ -140,6 +140,24 @@ func (f *CFeature) PrepareServePage(ctx beContext.Context, t feature.Theme, p fe
 		}
 	}
 
+	// provide any menus missing from the requested language using the first
+	// fallback language that has them
+	for _, fallback := range f.Enjin.SiteLanguageFallbacks(reqLangTag) {
+		for _, mp := range f.Enjin.GetMenuProviders() {
+			for name, m := range mp.GetMenus(fallback) {
+				camel := strcase.ToCamel(name)
+				if _, present := allMenus[camel]; !present {
+					if v, ok := siteMenu[camel]; ok {
+						allMenus[camel] = v
+					} else {
+						allMenus[camel] = m
+						log.DebugRF(r, "providing [%v] fallback [%v] menu: %v (.SiteMenu.%v)", reqLangTag.String(), fallback.String(), name, camel)
+					}
+				}
+			}
+		}
+	}
+
 	if len(allMenus) > 0 {
 		if v, present := allMenus["MainMenu"]; present {
 			if vt, ok := v.(menu.Menu); ok {
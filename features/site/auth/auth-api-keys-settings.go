@@ -0,0 +1,129 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/go-corelibs/x-text/message"
+	beContext "github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/forms"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) ApiKeysSettingsInfo(r *http.Request) (info *feature.CSiteFeatureInfo) {
+	printer := message.GetPrinter(r)
+	info = feature.NewSiteFeatureInfo(
+		f.KebabTag,
+		gApiKeysPanelKey,
+		"fa-solid fa-key",
+		printer.Sprintf("API Keys"),
+	)
+	info.Usage = printer.Sprintf(`API keys are used with "Authorization: Bearer" request headers and are limited to the permissions selected when created.`)
+	return
+}
+
+func (f *CFeature) MakeApiKeysSettingsPanel(settingsPath string) (handler http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if allowed := f.Site().RequireVerification(settingsPath, w, r); !allowed {
+			return
+		}
+
+		var claims *feature.CSiteAuthClaims
+		if claims = f.getPrivateClaims(r); claims == nil || !userbase.CurrentUserCan(r, f.Action("manage-own", "api-keys")) {
+			// api keys cannot be managed using api keys
+			f.Enjin.ServeNotFound(w, r)
+			return
+		}
+
+		au := userbase.GetCurrentUser(r)
+		printer := message.GetPrinter(r)
+
+		var newToken string
+
+		if r.Method == http.MethodPost {
+			_ = r.ParseForm()
+			if nonce := request.SafeQueryFormValue(r, ApiKeysNonceName); nonce != "" {
+				if !f.Enjin.VerifyNonce(ApiKeysNonceKey, nonce) {
+					r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+				} else {
+
+					switch request.SafeQueryFormValue(r, "submit") {
+
+					case "create":
+						name := forms.StrictSanitize(r.FormValue("name"))
+						selected := feature.ParseActions(r.Form["actions"]...)
+						var expires time.Time
+						if days, err := strconv.Atoi(r.FormValue("expires")); err == nil && days > 0 {
+							expires = time.Now().AddDate(0, 0, days)
+						}
+						if name == "" || selected.Len() == 0 {
+							r = feature.AddErrorNotice(r, true, berrs.IncompleteFormError(printer))
+						} else if token, err := f.CreateUserApiKey(r, claims.EID, name, expires, selected...); err != nil {
+							log.ErrorRF(r, "error creating user api key: %v", err)
+							r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+						} else {
+							newToken = token
+							r = feature.AddImportantNotice(r, true, printer.Sprintf(`Copy the new API key now, it will not be shown again.`))
+						}
+
+					case "revoke":
+						if id := request.SafeQueryFormValue(r, "api-key"); id != "" {
+							if err := f.RevokeUserApiKey(r, claims.EID, id); err != nil {
+								log.ErrorRF(r, "error revoking user api key: %v", err)
+								r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+							} else {
+								f.Site().PushInfoNotice(claims.EID, true, printer.Sprintf(`The API key has been revoked.`))
+								f.Enjin.ServeRedirect(settingsPath, w, r)
+								return
+							}
+						}
+
+					}
+
+				}
+			}
+		}
+
+		keys, err := f.ListUserApiKeys(r, claims.EID)
+		if err != nil {
+			log.ErrorRF(r, "error listing user api keys: %v", err)
+		}
+
+		ctx := beContext.Context{
+			"FeatureInfo": f.ApiKeysSettingsInfo(r),
+			"FormAction":  settingsPath,
+			"Nonces": feature.Nonces{
+				{Name: ApiKeysNonceName, Key: ApiKeysNonceKey},
+			},
+			"ApiKeys":          keys,
+			"NewApiKey":        newToken,
+			"AvailableActions": au.GetActions(),
+		}
+
+		t := f.Site().SiteTheme()
+		if err = f.Site().PrepareAndServePage("site-auth", "api-keys--manage", r.URL.Path, t, w, r, ctx); err != nil {
+			log.ErrorRF(r, "error preparing and serving api-keys--manage page: %v", err)
+			panic(err)
+		}
+	}
+}
@@ -0,0 +1,328 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"crypto/subtle"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	sha "github.com/go-corelibs/shasum"
+	"github.com/go-enjin/be/pkg/crypto"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+const (
+	privateApiKeyKey privateRequestKey = "site-auth-user-api-key-request-context-key"
+)
+
+var _ feature.User = (*apiKeyUser)(nil)
+
+// apiKeyUser limits the actions of the user to those granted to the api key
+type apiKeyUser struct {
+	feature.User
+
+	key *feature.SiteAuthApiKey
+}
+
+func (u *apiKeyUser) GetActions() (actions feature.Actions) {
+	actions = u.key.Actions.FilterKnown(u.User.GetActions())
+	return
+}
+
+func (u *apiKeyUser) Can(actions ...feature.Action) (allowed bool) {
+	if u.GetAdminLocked() {
+		return
+	}
+	allowed = u.GetActions().HasOneOf(actions)
+	return
+}
+
+func (u *apiKeyUser) CanAll(actions ...feature.Action) (allowed bool) {
+	if u.GetAdminLocked() {
+		return
+	}
+	allowed = u.GetActions().HasAllOf(actions)
+	return
+}
+
+func (f *CFeature) ApiKeysAllowed() (allowed bool) {
+	allowed = f.allowApiKeys
+	return
+}
+
+func (f *CFeature) CreateUserApiKey(r *http.Request, eid, name string, expires time.Time, actions ...feature.Action) (token string, err error) {
+	if !f.allowApiKeys {
+		err = berrs.ErrNotImplemented
+		return
+	} else if userbase.GetCurrentEID(r) != eid || !userbase.CurrentUserCan(r, f.Action("manage-own", "api-keys")) {
+		err = berrs.ErrPermissionDenied
+		return
+	} else if len(actions) == 0 {
+		err = fmt.Errorf("at least one action is required")
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	su.LockUser(r, eid)
+	defer su.UnlockUser(r, eid)
+
+	var au feature.User
+	if au, err = su.RetrieveUser(r, eid); err != nil {
+		return
+	} else if unknown := au.GetActions().FilterUnknown(actions); len(unknown) > 0 {
+		err = fmt.Errorf("%w: %v", berrs.ErrPermissionDenied, unknown)
+		return
+	}
+
+	if current := f.getPrivateApiKey(r); current != nil {
+		// keys created using an api key cannot exceed the scope of that key
+		if unknown := current.Actions.FilterUnknown(actions); len(unknown) > 0 {
+			err = fmt.Errorf("%w: %v", berrs.ErrPermissionDenied, unknown)
+			return
+		}
+		if current.Expires > 0 && (expires.IsZero() || expires.Unix() > current.Expires) {
+			// nor can they outlive the key used to create them
+			expires = time.Unix(current.Expires, 0)
+		}
+	}
+
+	var id, secret, hash string
+	if id, err = crypto.RandomValue(5); err != nil {
+		return
+	} else if secret, err = crypto.RandomValue(32); err != nil {
+		return
+	} else if hash, err = sha.Sum256([]byte(secret)); err != nil {
+		return
+	}
+
+	key := &feature.SiteAuthApiKey{
+		ID:      id,
+		Name:    name,
+		Hash:    hash,
+		Actions: actions,
+		Created: time.Now().Unix(),
+	}
+	if !expires.IsZero() {
+		key.Expires = expires.Unix()
+	}
+
+	keys := f.getUserApiKeysUnsafe(au)
+	if err = f.setUserApiKeysUnsafe(r, au, append(keys, key)); err != nil {
+		return
+	}
+
+	token = eid + "." + id + "." + secret
+	return
+}
+
+func (f *CFeature) ListUserApiKeys(r *http.Request, eid string) (keys feature.SiteAuthApiKeys, err error) {
+	if err = f.checkApiKeysPermission(r, eid); err != nil {
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	su.RLockUser(r, eid)
+	defer su.RUnlockUser(r, eid)
+
+	var au feature.User
+	if au, err = su.RetrieveUser(r, eid); err != nil {
+		return
+	}
+	keys = f.getUserApiKeysUnsafe(au).Redacted()
+	return
+}
+
+func (f *CFeature) RevokeUserApiKey(r *http.Request, eid, id string) (err error) {
+	if err = f.checkApiKeysPermission(r, eid); err != nil {
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	su.LockUser(r, eid)
+	defer su.UnlockUser(r, eid)
+
+	var au feature.User
+	if au, err = su.RetrieveUser(r, eid); err != nil {
+		return
+	}
+	keys := f.getUserApiKeysUnsafe(au)
+	if keys.Get(id) == nil {
+		err = berrs.ErrTokenNotFound
+		return
+	}
+	err = f.setUserApiKeysUnsafe(r, au, keys.Prune(id))
+	return
+}
+
+func (f *CFeature) RevokeUserApiKeys(r *http.Request, eid string) (err error) {
+	if err = f.checkApiKeysPermission(r, eid); err != nil {
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	su.LockUser(r, eid)
+	defer su.UnlockUser(r, eid)
+
+	var au feature.User
+	if au, err = su.RetrieveUser(r, eid); err != nil {
+		return
+	}
+	err = f.setUserApiKeysUnsafe(r, au, nil)
+	return
+}
+
+func (f *CFeature) checkApiKeysPermission(r *http.Request, eid string) (err error) {
+	if userbase.GetCurrentEID(r) == eid {
+		if !userbase.CurrentUserCan(r, f.Action("manage-own", "api-keys")) {
+			err = berrs.ErrPermissionDenied
+		}
+	} else if !userbase.CurrentUserCan(r, f.Action("revoke-other", "api-keys")) {
+		err = berrs.ErrPermissionDenied
+	}
+	return
+}
+
+func (f *CFeature) apiKeysContextKey() (key string) {
+	key = ".secure." + f.KebabTag + "-api-keys"
+	return
+}
+
+func (f *CFeature) getUserApiKeysUnsafe(au feature.User) (keys feature.SiteAuthApiKeys) {
+	keys = feature.ParseSiteAuthApiKeys(au.UnsafeContext().Get(f.apiKeysContextKey()))
+	return
+}
+
+func (f *CFeature) setUserApiKeysUnsafe(r *http.Request, au feature.User, keys feature.SiteAuthApiKeys) (err error) {
+	auCtx := au.UnsafeContext()
+	if keys.Len() == 0 {
+		auCtx.Delete(f.apiKeysContextKey())
+	} else if err = auCtx.SetKV(f.apiKeysContextKey(), keys); err != nil {
+		return
+	}
+	err = f.Site().SiteUsers().SetUserContext(r, au.GetEID(), auCtx)
+	return
+}
+
+// parseBearerToken returns the token value of the Authorization header, if
+// the header is present and is a Bearer token
+func parseBearerToken(r *http.Request) (token string, present bool) {
+	if value := r.Header.Get("Authorization"); value != "" {
+		if scheme, v, ok := strings.Cut(value, " "); ok && strings.EqualFold(scheme, "Bearer") {
+			token = strings.TrimSpace(v)
+			present = token != ""
+		}
+	}
+	return
+}
+
+// verifyApiKey validates the token given and returns the user and key it
+// belongs to, updating the key's last-used timestamp
+func (f *CFeature) verifyApiKey(r *http.Request, token string) (au feature.User, key *feature.SiteAuthApiKey, err error) {
+	parts := strings.SplitN(token, ".", 3)
+	if len(parts) != 3 || !userbase.IsValidEID(parts[0]) || parts[1] == "" || parts[2] == "" {
+		err = berrs.ErrTokenNotFound
+		return
+	}
+	eid, id, secret := parts[0], parts[1], parts[2]
+
+	su := f.Site().SiteUsers()
+	if !su.UserPresent(eid) {
+		err = berrs.ErrTokenNotFound
+		return
+	}
+
+	su.LockUser(r, eid)
+	defer su.UnlockUser(r, eid)
+
+	if au, err = su.RetrieveUser(r, eid); err != nil {
+		return
+	}
+
+	var hash string
+	keys := f.getUserApiKeysUnsafe(au)
+	if key = keys.Get(id); key == nil {
+		err = berrs.ErrTokenNotFound
+		return
+	} else if hash, err = sha.Sum256([]byte(secret)); err != nil {
+		return
+	} else if subtle.ConstantTimeCompare([]byte(hash), []byte(key.Hash)) != 1 {
+		key = nil
+		err = berrs.ErrTokenNotFound
+		return
+	} else if key.IsExpired() {
+		key = nil
+		err = berrs.ErrTokenExpired
+		return
+	}
+
+	if now := time.Now(); now.Sub(time.Unix(key.Used, 0)) > time.Minute {
+		// avoid writing to the user context on every single request
+		key.Used = now.Unix()
+		if ee := f.setUserApiKeysUnsafe(r, au, keys); ee != nil {
+			log.ErrorRF(r, "error updating api key last-used: %v", ee)
+		}
+	}
+
+	key = key.Redacted()
+	return
+}
+
+// authenticateApiKeyRequest is the Bearer token counterpart to
+// AuthenticateSiteRequest and AuthorizeUserSignIn
+func (f *CFeature) authenticateApiKeyRequest(token string, w http.ResponseWriter, r *http.Request) (handled bool, modified *http.Request) {
+	if handled = !f.allowApiKeys; handled {
+		f.Enjin.Serve401(w, r)
+		return
+	}
+
+	au, key, err := f.verifyApiKey(r, token)
+	if err != nil {
+		log.WarnRF(r, "api key authentication failed: %v", err)
+		handled = true
+		f.Enjin.Serve401(w, r)
+		return
+	} else if au.IsVisitor() || au.GetAdminLocked() || !f.IsUserAllowed(strings.ToLower(au.GetEmail())) {
+		log.WarnRF(r, "api key authentication denied for user: %v", au.GetEID())
+		handled = true
+		f.Enjin.ServeForbidden(w, r)
+		return
+	}
+
+	scoped := &apiKeyUser{User: au, key: key}
+	r = request.Set(userbase.SetCurrentUser(scoped, r), privateApiKeyKey, key)
+
+	for _, sf := range f.Site().SiteFeatures() {
+		if uu, ok := sf.This().(feature.SiteUserRequestModifier); ok {
+			if mm := uu.ModifyUserRequest(scoped, r); mm != nil {
+				r = mm
+			}
+		}
+	}
+
+	modified = r
+	return
+}
+
+func (f *CFeature) getPrivateApiKey(r *http.Request) (key *feature.SiteAuthApiKey) {
+	key, _ = request.Value[*feature.SiteAuthApiKey](r, privateApiKeyKey)
+	return
+}
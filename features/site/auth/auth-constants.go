@@ -33,10 +33,16 @@ const (
 
 	SettingsNonceKey  = "settings--form"
 	SettingsNonceName = "settings--nonce"
+	ApiKeysNonceKey   = "api-keys--form"
+	ApiKeysNonceName  = "api-keys--nonce"
+	SessionsNonceKey  = "sessions--form"
+	SessionsNonceName = "sessions--nonce"
 )
 
 const (
 	gRedirectKey        = "redirect"
 	gVerifyTargetKey    = "verify-target"
 	gVerifyingTargetKey = "verifying-target"
+	gApiKeysPanelKey    = "api-keys"
+	gSessionsPanelKey   = "sessions"
 )
@@ -32,5 +32,13 @@ var (
 	DefaultSessionDuration  = time.Hour
 	DefaultVerifiedDuration = time.Minute * 10
 
+	DefaultLockoutAccountFailures = 5
+	DefaultLockoutAddressFailures = 20
+	DefaultLockoutDuration        = time.Minute * 15
+	DefaultLockoutDelay           = time.Second
+
+	DefaultThrottleAccountRequests = 5
+	DefaultThrottleAddressRequests = 20
+
 	DefaultDeleteOwnUserConfirmation = "Please delete my own user, thank you!"
 )
@@ -0,0 +1,273 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/Shopify/gomail"
+
+	"github.com/go-corelibs/x-text/message"
+	beContext "github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/kvs"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net"
+	"github.com/go-enjin/be/pkg/request"
+)
+
+const (
+	LockoutEmailTemplate = "site-auth--lockout"
+)
+
+// authFailures tracks the failed attempts of an account or IP address
+type authFailures struct {
+	Count int   `json:"count"`
+	Last  int64 `json:"last"`
+	Until int64 `json:"until"`
+}
+
+func (f *CFeature) startupLockout() (err error) {
+	if !f.lockoutEnabled {
+		// brute-force protection is optional
+		return
+	} else if err = f.lockout.StartupUsesKVC(f.Enjin.Features()); err != nil {
+		return
+	}
+
+	f.lockoutBucket = f.lockout.KVC().MustBucket("site-auth-failures")
+	lockerBucket := f.lockout.KVC().MustBucket("site-auth-failures-locker")
+	f.lockoutLocker = f.Enjin.NewSyncLocker(f.Tag(), "site-auth-failures-locker", lockerBucket)
+
+	if f.lockoutEmailAccount == "" {
+		// lockout emails are optional
+	} else if f.lockoutEmailSender = f.Enjin.FindEmailAccount(f.lockoutEmailAccount); f.lockoutEmailSender == nil {
+		err = fmt.Errorf("%v email sender not found", f.lockoutEmailAccount)
+	} else if f.lockoutEmailProviderTag.IsNil() {
+		err = fmt.Errorf(".SetLockoutEmail requires an email provider")
+	} else if epf, ok := f.Enjin.Features().Get(f.lockoutEmailProviderTag); !ok {
+		err = fmt.Errorf("%v email provider feature not found", f.lockoutEmailProviderTag)
+	} else if ep, ok := epf.This().(feature.EmailProvider); !ok {
+		err = fmt.Errorf("%v feature is not a feature.EmailProvider", f.lockoutEmailProviderTag)
+	} else {
+		f.lockoutEmailProvider = ep
+	}
+	return
+}
+
+func (f *CFeature) CheckAuthAttempt(r *http.Request, eid string) (err error) {
+	if !f.lockoutEnabled {
+		return
+	}
+
+	now := time.Now()
+	address, _ := net.GetIpFromRequest(r)
+	for _, key := range f.lockoutKeys(eid, address) {
+		if failures := f.getAuthFailures(r, key); failures != nil {
+			if failures.Until > 0 && now.Unix() < failures.Until {
+				err = berrs.ErrTooManyAttempts
+				return
+			} else if failures.Count > 0 && now.Before(time.Unix(failures.Last, 0).Add(f.lockoutDelay(failures.Count))) {
+				err = berrs.ErrTooManyAttempts
+				return
+			}
+		}
+	}
+	return
+}
+
+func (f *CFeature) RecordAuthFailure(r *http.Request, eid string) {
+	if !f.lockoutEnabled {
+		return
+	}
+
+	address, _ := net.GetIpFromRequest(r)
+
+	if eid != "" {
+		if locked := f.incrementAuthFailures(r, "account:"+eid, f.lockoutAccounts); locked {
+			log.WarnRF(r, "site user %q locked out for %v after %d failed attempts", eid, f.lockoutDuration, f.lockoutAccounts)
+			if err := f.sendLockoutEmail(r, eid); err != nil {
+				log.ErrorRF(r, "error sending lockout email to %q: %v", eid, err)
+			}
+		}
+	}
+
+	if address != "" {
+		if locked := f.incrementAuthFailures(r, "address:"+address, f.lockoutAddresses); locked {
+			log.WarnRF(r, "address %q locked out for %v after %d failed attempts", address, f.lockoutDuration, f.lockoutAddresses)
+			for _, rd := range feature.FilterTyped[feature.RequestDenier](f.Enjin.Features().List()) {
+				rd.DenyAddress(address)
+			}
+		}
+	}
+}
+
+func (f *CFeature) RecordAuthSuccess(r *http.Request, eid string) {
+	if !f.lockoutEnabled {
+		return
+	}
+	// the address failures are not reset, signing into one account would
+	// otherwise clear the failures against other accounts from the address
+	if eid != "" {
+		f.resetAuthFailures(r, "account:"+eid)
+	}
+}
+
+func (f *CFeature) ThrottleAuthRequest(r *http.Request, eid string) (err error) {
+	if !f.lockoutEnabled {
+		return
+	}
+
+	address, _ := net.GetIpFromRequest(r)
+	if eid != "" && !f.incrementAuthRequests(r, "requests:account:"+eid, DefaultThrottleAccountRequests) {
+		err = berrs.ErrTooManyAttempts
+	} else if address != "" && !f.incrementAuthRequests(r, "requests:address:"+address, DefaultThrottleAddressRequests) {
+		err = berrs.ErrTooManyAttempts
+	}
+	return
+}
+
+func (f *CFeature) lockoutKeys(eid, address string) (keys []string) {
+	if eid != "" {
+		keys = append(keys, "account:"+eid)
+	}
+	if address != "" {
+		keys = append(keys, "address:"+address)
+	}
+	return
+}
+
+// lockoutDelay returns the progressive delay required between attempts after
+// the given number of failures, doubling with each failure and capped at the
+// lockout duration
+func (f *CFeature) lockoutDelay(count int) (delay time.Duration) {
+	delay = DefaultLockoutDelay
+	for i := 1; i < count && delay < f.lockoutDuration; i++ {
+		delay *= 2
+	}
+	if delay > f.lockoutDuration {
+		delay = f.lockoutDuration
+	}
+	return
+}
+
+func (f *CFeature) getAuthFailures(r *http.Request, key string) (failures *authFailures) {
+	if data, err := kvs.ForRequest(f.lockoutBucket, r).Get(key); err == nil && len(data) > 0 {
+		failures = &authFailures{}
+		if err = json.Unmarshal(data, failures); err != nil {
+			failures = nil
+		}
+	}
+	return
+}
+
+func (f *CFeature) resetAuthFailures(r *http.Request, key string) {
+	f.lockoutLocker.Lock(key)
+	defer f.lockoutLocker.Unlock(key)
+	if err := kvs.ForRequest(f.lockoutBucket, r).Delete(key); err != nil {
+		log.ErrorRF(r, "error resetting failed attempts of %q: %v", key, err)
+	}
+}
+
+// incrementAuthRequests counts a request against the given key and returns
+// false, without counting, when the maximum requests within the lockout
+// duration have been made already
+func (f *CFeature) incrementAuthRequests(r *http.Request, key string, maximum int) (allowed bool) {
+	f.lockoutLocker.Lock(key)
+	defer f.lockoutLocker.Unlock(key)
+
+	now := time.Now()
+	requests := f.getAuthFailures(r, key)
+	if requests == nil || now.After(time.Unix(requests.Last, 0).Add(f.lockoutDuration)) {
+		// no recent requests, start counting again
+		requests = &authFailures{}
+	} else if maximum > 0 && requests.Count >= maximum {
+		return
+	}
+
+	requests.Count += 1
+	requests.Last = now.Unix()
+	if data, err := json.Marshal(requests); err != nil {
+		log.ErrorF("error encoding throttled requests of %q: %v", key, err)
+	} else if err = kvs.ForRequest(f.lockoutBucket, r).Set(key, data); err != nil {
+		log.ErrorF("error storing throttled requests of %q: %v", key, err)
+	}
+	allowed = true
+	return
+}
+
+// incrementAuthFailures counts a failure against the given key and returns
+// true when this failure reached the maximum allowed, starting a lockout
+func (f *CFeature) incrementAuthFailures(r *http.Request, key string, maximum int) (locked bool) {
+	f.lockoutLocker.Lock(key)
+	defer f.lockoutLocker.Unlock(key)
+
+	now := time.Now()
+	failures := f.getAuthFailures(r, key)
+	if failures == nil || now.After(time.Unix(failures.Last, 0).Add(f.lockoutDuration)) {
+		// no recent failures, start counting again
+		failures = &authFailures{}
+	}
+
+	failures.Count += 1
+	failures.Last = now.Unix()
+	if locked = maximum > 0 && failures.Count == maximum; locked {
+		failures.Until = now.Add(f.lockoutDuration).Unix()
+	}
+
+	if data, err := json.Marshal(failures); err != nil {
+		log.ErrorF("error encoding failed attempts of %q: %v", key, err)
+	} else if err = kvs.ForRequest(f.lockoutBucket, r).Set(key, data); err != nil {
+		log.ErrorF("error storing failed attempts of %q: %v", key, err)
+	}
+	return
+}
+
+func (f *CFeature) sendLockoutEmail(r *http.Request, eid string) (err error) {
+	if f.lockoutEmailProvider == nil {
+		return
+	}
+
+	var au feature.User
+	if au, err = f.Site().SiteUsers().RetrieveUser(r, eid); err != nil {
+		// accounts which do not exist have no one to notify
+		err = nil
+		return
+	}
+
+	printer := message.GetPrinter(r)
+	address, _ := net.GetIpFromRequest(r)
+
+	var msg *gomail.Message
+	if msg, err = f.lockoutEmailProvider.NewEmail(LockoutEmailTemplate, beContext.Context{
+		"Name":       au.GetName(),
+		"Email":      au.GetEmail(),
+		"Address":    address,
+		"Duration":   f.lockoutDuration.String(),
+		"Expiration": time.Now().Add(f.lockoutDuration),
+		"SignInUrl":  request.ParseDomainUrl(r) + f.SiteAuthSignInPath(),
+		"SiteName":   f.Enjin.SiteName(),
+	}); err != nil {
+		return
+	}
+	msg.SetHeader("To", au.GetEmail())
+	msg.SetHeader("Subject", printer.Sprintf("%[1]s Account Locked", f.Enjin.SiteName()))
+	err = f.lockoutEmailSender.SendEmail(r, f.lockoutEmailAccount, msg)
+	return
+}
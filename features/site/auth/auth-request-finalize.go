@@ -26,6 +26,12 @@ func (f *CFeature) FinalizeSiteRequest(w http.ResponseWriter, r *http.Request) (
 	var au feature.User
 	var claims *feature.CSiteAuthClaims
 
+	if key := f.getPrivateApiKey(r); key != nil {
+		// api key requests have no session to finalize
+		m = r
+		return
+	}
+
 	if claims = f.getPrivateClaims(r); claims == nil {
 		m = f.resetCurrentUser(w, r)
 		return
@@ -44,8 +50,16 @@ func (f *CFeature) FinalizeSiteRequest(w http.ResponseWriter, r *http.Request) (
 		return
 	}
 
+	var sessions feature.SiteAuthSessions
+	if sessions, err = f.updateUserSessionUnsafe(r, au, claims); err != nil {
+		// the session was revoked during this request
+		m = f.resetCurrentUser(w, r)
+		return
+	}
+
 	auCtx := au.UnsafeContext()
 	_ = auCtx.SetKV(".last-seen", time.Now())
+	_ = auCtx.SetKV(f.sessionsContextKey(), sessions)
 
 	if err = f.Site().SiteUsers().SetUserContext(r, claims.EID, auCtx); err != nil {
 		log.ErrorRF(r, "error setting user context: %v", err)
@@ -37,6 +37,12 @@ func (f *CFeature) AuthenticateSiteRequest(w http.ResponseWriter, r *http.Reques
 		// this page is the login page
 	}
 
+	if token, present := parseBearerToken(r); present {
+		// api key requests do not use cookies
+		handled, modified = f.authenticateApiKeyRequest(token, w, r)
+		return
+	}
+
 	var err error
 	var claims *feature.CSiteAuthClaims
 	if claims, err = f.VerifyJWT(r); err != nil {
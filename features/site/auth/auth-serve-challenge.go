@@ -169,7 +169,24 @@ func (f *CFeature) ProcessChallengeRequest(w http.ResponseWriter, r *http.Reques
 				pFactor = names[0]
 			}
 
-			if handled, redirect = mfp.ProcessChallenge(pFactor, challenge, f, claims, w, r); handled {
+			if err := f.CheckAuthAttempt(r, claims.EID); err != nil {
+				log.WarnRF(r, "challenge attempt denied for %q: %v", claims.EID, err)
+				r = feature.AddErrorNotice(r, true, berrs.TooManyAttemptsError(printer))
+				f.ServeChallengeRequest(w, r)
+				return
+			}
+
+			handled, redirect = mfp.ProcessChallenge(pFactor, challenge, f, claims, w, r)
+			if submit == "challenge" {
+				// only actual challenge responses count as attempts
+				if _, ok := claims.GetFactor(mfp.Tag().Kebab(), pFactor); ok {
+					f.RecordAuthSuccess(r, claims.EID)
+				} else {
+					f.RecordAuthFailure(r, claims.EID)
+				}
+			}
+
+			if handled {
 				// challenge handled
 				return
 			} else {
@@ -45,6 +45,15 @@ func (f *CFeature) HandleSignOutPage(w http.ResponseWriter, r *http.Request) {
 		log.ErrorRF(r, "sign-out page handler error: %v", err)
 	}
 
+	if claims := f.getPrivateClaims(r); claims != nil && claims.ID != "" {
+		if _, ee := f.revokeUserSessions(r, claims.EID, func(session *feature.SiteAuthSession) (revoke bool) {
+			revoke = session.ID == claims.ID
+			return
+		}); ee != nil {
+			log.ErrorRF(r, "error revoking signed-out session: %v", ee)
+		}
+	}
+
 	r = f.resetCurrentUser(w, r)
 
 	if redirect != "" {
@@ -161,9 +161,18 @@ func (f *CFeature) ProcessVerificationRequest(verifyTarget string, w http.Respon
 							if tag := f.mfa.Features.Find(kebab); tag.IsNil() {
 								log.ErrorRF(r, "invalid challenge provision received")
 								r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+							} else if err := f.CheckAuthAttempt(r, claims.EID); err != nil {
+								log.WarnRF(r, "verification attempt denied for %q: %v", claims.EID, err)
+								r = feature.AddErrorNotice(r, true, berrs.TooManyAttemptsError(printer))
 							} else {
 								mfp := f.mfa.Features.Get(tag)
-								if handled, redirect = mfp.ProcessVerification(verifyTarget, pFactor, challenge, f, claims, w, r); handled {
+								handled, redirect = mfp.ProcessVerification(verifyTarget, pFactor, challenge, f, claims, w, r)
+								if _, verified := claims.GetVerifiedFactor(verifyTarget); verified {
+									f.RecordAuthSuccess(r, claims.EID)
+								} else {
+									f.RecordAuthFailure(r, claims.EID)
+								}
+								if handled {
 									// challenge handled
 									return
 								} else if redirect == "" {
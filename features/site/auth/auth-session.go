@@ -42,6 +42,7 @@ func (f *CFeature) MakeAuthClaims(aud, email string, ctx context.Context) (claim
 		Email:   email,
 		Context: ctx,
 		RegisteredClaims: jwt.RegisteredClaims{
+			ID:        makeSessionID(),
 			Issuer:    f.KebabTag,
 			Subject:   eid,
 			Audience:  []string{audience},
@@ -100,6 +101,8 @@ func (f *CFeature) VerifyJWT(r *http.Request) (claims *feature.CSiteAuthClaims,
 	} else if token == nil || !token.Valid {
 		err = errors.ErrBadRequest
 		return
+	} else if err = f.checkSessionRevoked(r, claims); err != nil {
+		return
 	}
 	claims.ResetFactorValueTypes()
 	return
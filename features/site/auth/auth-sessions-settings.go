@@ -0,0 +1,114 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+
+	"github.com/go-corelibs/x-text/message"
+	beContext "github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) SessionsSettingsInfo(r *http.Request) (info *feature.CSiteFeatureInfo) {
+	printer := message.GetPrinter(r)
+	info = feature.NewSiteFeatureInfo(
+		f.KebabTag,
+		gSessionsPanelKey,
+		"fa-solid fa-display",
+		printer.Sprintf("Active Sessions"),
+	)
+	info.Usage = printer.Sprintf(`Review the devices currently signed into your account and sign out of any that are unfamiliar.`)
+	return
+}
+
+func (f *CFeature) MakeSessionsSettingsPanel(settingsPath string) (handler http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if allowed := f.Site().RequireVerification(settingsPath, w, r); !allowed {
+			return
+		}
+
+		var claims *feature.CSiteAuthClaims
+		if claims = f.getPrivateClaims(r); claims == nil || !userbase.CurrentUserCan(r, f.Action("manage-own", "sessions")) {
+			f.Enjin.ServeNotFound(w, r)
+			return
+		}
+
+		printer := message.GetPrinter(r)
+
+		if r.Method == http.MethodPost {
+			_ = r.ParseForm()
+			if nonce := request.SafeQueryFormValue(r, SessionsNonceName); nonce != "" {
+				if !f.Enjin.VerifyNonce(SessionsNonceKey, nonce) {
+					r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+				} else {
+
+					switch request.SafeQueryFormValue(r, "submit") {
+
+					case "revoke":
+						if id := request.SafeQueryFormValue(r, "session"); id != "" && id != claims.ID {
+							if err := f.RevokeUserSession(r, claims.EID, id); err != nil {
+								log.ErrorRF(r, "error revoking user session: %v", err)
+								r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+							} else {
+								f.Site().PushInfoNotice(claims.EID, true, printer.Sprintf(`The session has been signed out.`))
+								f.Enjin.ServeRedirect(settingsPath, w, r)
+								return
+							}
+						}
+
+					case "revoke-others":
+						if err := f.RevokeUserSessions(r, claims.EID, claims.ID); err != nil {
+							log.ErrorRF(r, "error revoking other user sessions: %v", err)
+							r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+						} else {
+							f.Site().PushInfoNotice(claims.EID, true, printer.Sprintf(`All other sessions have been signed out.`))
+							f.Enjin.ServeRedirect(settingsPath, w, r)
+							return
+						}
+
+					}
+
+				}
+			}
+		}
+
+		sessions, err := f.ListUserSessions(r, claims.EID)
+		if err != nil {
+			log.ErrorRF(r, "error listing user sessions: %v", err)
+		}
+
+		ctx := beContext.Context{
+			"FeatureInfo": f.SessionsSettingsInfo(r),
+			"FormAction":  settingsPath,
+			"Nonces": feature.Nonces{
+				{Name: SessionsNonceName, Key: SessionsNonceKey},
+			},
+			"Sessions":       sessions,
+			"CurrentSession": claims.ID,
+		}
+
+		t := f.Site().SiteTheme()
+		if err = f.Site().PrepareAndServePage("site-auth", "sessions--manage", r.URL.Path, t, w, r, ctx); err != nil {
+			log.ErrorRF(r, "error preparing and serving sessions--manage page: %v", err)
+			panic(err)
+		}
+	}
+}
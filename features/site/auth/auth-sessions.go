@@ -0,0 +1,249 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package auth
+
+import (
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/go-enjin/be/pkg/crypto"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) ListUserSessions(r *http.Request, eid string) (sessions feature.SiteAuthSessions, err error) {
+	if err = f.checkSessionsPermission(r, eid); err != nil {
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	su.RLockUser(r, eid)
+	defer su.RUnlockUser(r, eid)
+
+	var au feature.User
+	if au, err = su.RetrieveUser(r, eid); err != nil {
+		return
+	}
+	sessions = f.getUserSessionsUnsafe(au).Active()
+	return
+}
+
+func (f *CFeature) RevokeUserSession(r *http.Request, eid, id string) (err error) {
+	if err = f.checkSessionsPermission(r, eid); err != nil {
+		return
+	}
+	var count int
+	if count, err = f.revokeUserSessions(r, eid, func(session *feature.SiteAuthSession) (revoke bool) {
+		revoke = session.ID == id
+		return
+	}); err == nil && count == 0 {
+		err = berrs.ErrTokenNotFound
+	}
+	return
+}
+
+func (f *CFeature) RevokeUserSessions(r *http.Request, eid string, except ...string) (err error) {
+	if err = f.checkSessionsPermission(r, eid); err != nil {
+		return
+	}
+	lookup := make(map[string]struct{})
+	for _, id := range except {
+		lookup[id] = struct{}{}
+	}
+	_, err = f.revokeUserSessions(r, eid, func(session *feature.SiteAuthSession) (revoke bool) {
+		_, excepted := lookup[session.ID]
+		revoke = !excepted
+		return
+	})
+	return
+}
+
+// revokeUserSessions marks all active sessions selected by fn as revoked and
+// returns the number of sessions revoked
+func (f *CFeature) revokeUserSessions(r *http.Request, eid string, fn func(session *feature.SiteAuthSession) (revoke bool)) (count int, err error) {
+	su := f.Site().SiteUsers()
+	su.LockUser(r, eid)
+	defer su.UnlockUser(r, eid)
+
+	var au feature.User
+	if au, err = su.RetrieveUser(r, eid); err != nil {
+		return
+	}
+
+	now := time.Now().Unix()
+	sessions := f.getUserSessionsUnsafe(au)
+	for _, session := range sessions {
+		if session.Revoked == 0 && fn(session) {
+			session.Revoked = now
+			count += 1
+		}
+	}
+	if count == 0 {
+		return
+	}
+	err = f.setUserSessionsUnsafe(r, au, sessions)
+	return
+}
+
+func (f *CFeature) checkSessionsPermission(r *http.Request, eid string) (err error) {
+	if userbase.GetCurrentEID(r) == eid {
+		if !userbase.CurrentUserCan(r, f.Action("manage-own", "sessions")) {
+			err = berrs.ErrPermissionDenied
+		}
+	} else if !userbase.CurrentUserCan(r, f.Action("revoke-other", "sessions")) {
+		err = berrs.ErrPermissionDenied
+	}
+	return
+}
+
+func (f *CFeature) sessionsContextKey() (key string) {
+	key = ".secure." + f.KebabTag + "-sessions"
+	return
+}
+
+func (f *CFeature) getUserSessionsUnsafe(au feature.User) (sessions feature.SiteAuthSessions) {
+	sessions = feature.ParseSiteAuthSessions(au.UnsafeContext().Get(f.sessionsContextKey()))
+	return
+}
+
+func (f *CFeature) setUserSessionsUnsafe(r *http.Request, au feature.User, sessions feature.SiteAuthSessions) (err error) {
+	auCtx := au.UnsafeContext()
+	if sessions.Len() == 0 {
+		auCtx.Delete(f.sessionsContextKey())
+	} else if err = auCtx.SetKV(f.sessionsContextKey(), sessions); err != nil {
+		return
+	}
+	err = f.Site().SiteUsers().SetUserContext(r, au.GetEID(), auCtx)
+	return
+}
+
+// makeSessionID returns a new random session ID for use as the JWT ID claim
+func makeSessionID() (id string) {
+	var err error
+	if id, err = crypto.RandomValue(16); err != nil {
+		log.ErrorF("error generating random session id: %v", err)
+	}
+	return
+}
+
+// checkSessionRevoked returns ErrSessionRevoked if the session of the claims
+// given has been revoked
+func (f *CFeature) checkSessionRevoked(r *http.Request, claims *feature.CSiteAuthClaims) (err error) {
+	if claims.ID == "" {
+		// legacy tokens are given a session ID during finalization
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	if !su.UserPresent(claims.EID) {
+		return
+	}
+
+	su.RLockUser(r, claims.EID)
+	defer su.RUnlockUser(r, claims.EID)
+
+	var au feature.User
+	if au, err = su.RetrieveUser(r, claims.EID); err != nil {
+		return
+	}
+	if session := f.getUserSessionsUnsafe(au).Get(claims.ID); session != nil && session.Revoked > 0 {
+		err = berrs.ErrSessionRevoked
+	}
+	return
+}
+
+// updateUserSessionUnsafe records the current request as the most recent
+// activity of the claims session, the returned sessions are pruned of any
+// which have expired
+func (f *CFeature) updateUserSessionUnsafe(r *http.Request, au feature.User, claims *feature.CSiteAuthClaims) (sessions feature.SiteAuthSessions, err error) {
+	if claims.ID == "" {
+		claims.ID = makeSessionID()
+	}
+
+	now := time.Now().Unix()
+	sessions = f.getUserSessionsUnsafe(au).PruneExpired(f.sessionDuration)
+
+	session := sessions.Get(claims.ID)
+	if session == nil {
+		session = &feature.SiteAuthSession{
+			ID:      claims.ID,
+			Created: now,
+		}
+		sessions = append(sessions, session)
+	} else if session.Revoked > 0 {
+		err = berrs.ErrSessionRevoked
+		return
+	}
+
+	userAgent := r.Header.Get("User-Agent")
+	session.LastSeen = now
+	session.IP, _ = net.GetIpFromRequest(r)
+	session.UserAgent = userAgent
+	session.Device = parseUserAgentDevice(userAgent)
+	return
+}
+
+var (
+	gUserAgentBrowsers = [][2]string{
+		{"Edg/", "Edge"},
+		{"OPR/", "Opera"},
+		{"Firefox/", "Firefox"},
+		{"Chrome/", "Chrome"},
+		{"Safari/", "Safari"},
+		{"curl/", "curl"},
+	}
+	gUserAgentPlatforms = [][2]string{
+		{"Android", "Android"},
+		{"iPhone", "iPhone"},
+		{"iPad", "iPad"},
+		{"Windows", "Windows"},
+		{"Mac OS X", "macOS"},
+		{"CrOS", "ChromeOS"},
+		{"Linux", "Linux"},
+	}
+)
+
+// parseUserAgentDevice returns a short human-readable description of the
+// browser and platform of the given user agent string
+func parseUserAgentDevice(userAgent string) (device string) {
+	var browser, platform string
+	for _, pair := range gUserAgentBrowsers {
+		if strings.Contains(userAgent, pair[0]) {
+			browser = pair[1]
+			break
+		}
+	}
+	for _, pair := range gUserAgentPlatforms {
+		if strings.Contains(userAgent, pair[0]) {
+			platform = pair[1]
+			break
+		}
+	}
+	switch {
+	case browser != "" && platform != "":
+		device = browser + " (" + platform + ")"
+	case browser != "":
+		device = browser
+	case platform != "":
+		device = platform
+	default:
+		device = "Unknown"
+	}
+	return
+}
@@ -146,6 +146,18 @@ func (f *CFeature) ServeSettingsPanelSelectorPage(settingsPath string, w http.Re
 		}
 	}
 
+	if f.allowApiKeys && userbase.CurrentUserCan(r, f.Action("manage-own", "api-keys")) {
+		order = append(order, gApiKeysPanelKey)
+		paths[gApiKeysPanelKey] = settingsPath + "/" + gApiKeysPanelKey
+		infos[gApiKeysPanelKey] = f.ApiKeysSettingsInfo(r)
+	}
+
+	if f.getPrivateClaims(r) != nil && userbase.CurrentUserCan(r, f.Action("manage-own", "sessions")) {
+		order = append(order, gSessionsPanelKey)
+		paths[gSessionsPanelKey] = settingsPath + "/" + gSessionsPanelKey
+		infos[gSessionsPanelKey] = f.SessionsSettingsInfo(r)
+	}
+
 	ctx.SetSpecific("PanelsOrder", order)
 	ctx.SetSpecific("PanelsPaths", paths)
 	ctx.SetSpecific("PanelsInfos", infos)
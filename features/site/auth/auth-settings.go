@@ -47,6 +47,20 @@ func (f *CFeature) SiteSettingsPanel(settingsPath string) (serve, handle http.Ha
 		}
 	}
 
+	if f.allowApiKeys {
+		h := f.MakeApiKeysSettingsPanel(settingsPath + "/" + gApiKeysPanelKey)
+		serveLookup[gApiKeysPanelKey] = h
+		serveOrder = append(serveOrder, gApiKeysPanelKey)
+		handleLookup[gApiKeysPanelKey] = h
+		handleOrder = append(handleOrder, gApiKeysPanelKey)
+	}
+
+	h := f.MakeSessionsSettingsPanel(settingsPath + "/" + gSessionsPanelKey)
+	serveLookup[gSessionsPanelKey] = h
+	serveOrder = append(serveOrder, gSessionsPanelKey)
+	handleLookup[gSessionsPanelKey] = h
+	handleOrder = append(handleOrder, gSessionsPanelKey)
+
 	if len(serveLookup) > 0 {
 		serve = f.MakeServeSiteSettingsPanel(settingsPath, serveOrder, serveLookup)
 	}
@@ -32,6 +32,7 @@ import (
 	"github.com/go-enjin/be/pkg/feature"
 	site_environ "github.com/go-enjin/be/pkg/feature/site-environ"
 	site_including "github.com/go-enjin/be/pkg/feature/site-including"
+	uses_kvc "github.com/go-enjin/be/pkg/feature/uses-kvc"
 	"github.com/go-enjin/be/pkg/log"
 	"github.com/go-enjin/be/pkg/maps"
 	"github.com/go-enjin/be/pkg/menu"
@@ -62,6 +63,21 @@ type MakeFeature interface {
 	DenySignupsFrom(emails ...string) MakeFeature
 	AllowSignupsFrom(emails ...string) MakeFeature
 
+	// SetApiKeysAllowed enables users to create personal API keys for use
+	// with "Authorization: Bearer" request headers
+	SetApiKeysAllowed(allowed bool) MakeFeature
+
+	// SetLockoutCache enables brute-force protection, using the named
+	// KeyValueCache to track failed sign-in and challenge attempts
+	SetLockoutCache(tag feature.Tag, name string) MakeFeature
+	// SetLockoutPolicy specifies the number of failed attempts allowed per
+	// account and per IP address before a temporary lockout of the given
+	// duration
+	SetLockoutPolicy(accountFailures, addressFailures int, duration time.Duration) MakeFeature
+	// SetLockoutEmail specifies the email account and feature.EmailProvider
+	// to use when notifying users of their account being locked out
+	SetLockoutEmail(account string, provider feature.Tag) MakeFeature
+
 	SetSecretKey(aud string, key []byte) MakeFeature
 	SetXsrfHeaderName(headerName string) MakeFeature
 	SetXsrfCookieName(cookieName string) MakeFeature
@@ -97,6 +113,21 @@ type CFeature struct {
 	deniedEmails  []string
 	allowedEmails []string
 
+	allowApiKeys bool
+
+	lockout          *uses_kvc.CUsesKVC[MakeFeature]
+	lockoutEnabled   bool
+	lockoutBucket    feature.KeyValueStore
+	lockoutLocker    feature.SyncLocker
+	lockoutAccounts  int
+	lockoutAddresses int
+	lockoutDuration  time.Duration
+
+	lockoutEmailAccount     string
+	lockoutEmailProviderTag feature.Tag
+	lockoutEmailSender      feature.EmailSender
+	lockoutEmailProvider    feature.EmailProvider
+
 	secretKeys   map[string][]byte
 	audienceKeys map[string][]byte
 
@@ -156,6 +187,10 @@ func (f *CFeature) Init(this interface{}) {
 	f.sab = site_including.New[feature.SiteUserSetupStage, MakeFeature](this)
 	f.mfa = site_including.New[feature.SiteMultiFactorProvider, MakeFeature](this)
 	f.mfb = site_including.New[feature.SiteMultiFactorProvider, MakeFeature](this)
+	f.lockout = uses_kvc.NewUsesKVC[MakeFeature](this)
+	f.lockoutAccounts = DefaultLockoutAccountFailures
+	f.lockoutAddresses = DefaultLockoutAddressFailures
+	f.lockoutDuration = DefaultLockoutDuration
 	f.signInPath = DefaultSignInPath
 	f.signOutPath = DefaultSignOutPath
 	f.challengePath = DefaultChallengePath
@@ -225,6 +260,30 @@ func (f *CFeature) AllowSignupsFrom(emails ...string) MakeFeature {
 	return f
 }
 
+func (f *CFeature) SetApiKeysAllowed(allowed bool) MakeFeature {
+	f.allowApiKeys = allowed
+	return f
+}
+
+func (f *CFeature) SetLockoutCache(tag feature.Tag, name string) MakeFeature {
+	f.lockoutEnabled = true
+	f.lockout.SetKeyValueCache(tag, name)
+	return f
+}
+
+func (f *CFeature) SetLockoutPolicy(accountFailures, addressFailures int, duration time.Duration) MakeFeature {
+	f.lockoutAccounts = accountFailures
+	f.lockoutAddresses = addressFailures
+	f.lockoutDuration = duration
+	return f
+}
+
+func (f *CFeature) SetLockoutEmail(account string, provider feature.Tag) MakeFeature {
+	f.lockoutEmailAccount = account
+	f.lockoutEmailProviderTag = provider
+	return f
+}
+
 func (f *CFeature) SetSecretKey(aud string, value []byte) MakeFeature {
 	if aud == "" {
 		aud = DefaultAudience
@@ -383,6 +442,33 @@ func (f *CFeature) Build(b feature.Buildable) (err error) {
 			EnvVars:  b.MakeEnvKeys(fns.denyEmails),
 			Category: category,
 		},
+		&cli.BoolFlag{
+			Name:     fns.allowApiKeys,
+			Usage:    "allow users to create personal API keys",
+			EnvVars:  b.MakeEnvKeys(fns.allowApiKeys),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     fns.lockoutAccounts,
+			Usage:    "number of failed attempts before an account is temporarily locked out",
+			Value:    f.lockoutAccounts,
+			EnvVars:  b.MakeEnvKeys(fns.lockoutAccounts),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     fns.lockoutAddresses,
+			Usage:    "number of failed attempts before an IP address is temporarily locked out",
+			Value:    f.lockoutAddresses,
+			EnvVars:  b.MakeEnvKeys(fns.lockoutAddresses),
+			Category: category,
+		},
+		&cli.Int64Flag{
+			Name:     fns.lockoutDuration,
+			Usage:    "specify the lockout duration",
+			Value:    int64(f.lockoutDuration.Seconds()),
+			EnvVars:  b.MakeEnvKeys(fns.lockoutDuration),
+			Category: category,
+		},
 		&cli.StringFlag{
 			Name:     fns.signInPath,
 			Usage:    "specify the sign-in sub-path",
@@ -441,6 +527,25 @@ func (f *CFeature) Startup(ctx *cli.Context) (err error) {
 		}
 	}
 
+	if ctx.IsSet(fns.allowApiKeys) {
+		f.SetApiKeysAllowed(ctx.Bool(fns.allowApiKeys))
+	}
+
+	if ctx.IsSet(fns.lockoutAccounts) {
+		f.lockoutAccounts = ctx.Int(fns.lockoutAccounts)
+	}
+	if ctx.IsSet(fns.lockoutAddresses) {
+		f.lockoutAddresses = ctx.Int(fns.lockoutAddresses)
+	}
+	if ctx.IsSet(fns.lockoutDuration) {
+		if v := ctx.Int64(fns.lockoutDuration); v > 0 {
+			f.lockoutDuration = time.Second * time.Duration(v)
+		}
+	}
+	if err = f.startupLockout(); err != nil {
+		return
+	}
+
 	if ctx.IsSet(fns.secretKey) {
 		if v := ctx.String(fns.secretKey); v != "" {
 			f.audienceKeys[DefaultAudience] = []byte(v)
@@ -563,6 +668,8 @@ func (f *CFeature) PostStartup(_ *cli.Context) (err error) {
 		"jwt-cookie-name":  f.jwtCookieName,
 		"xsrf-cookie-name": f.xsrfCookieName,
 		"xsrf-header-name": f.xsrfHeaderName,
+		"allow-api-keys":   f.allowApiKeys,
+		"lockout-enabled":  f.lockoutEnabled,
 		"site-users":       f.Site().SiteUsers().Tag(),
 		"sap-features":     f.sap.Features.Tags(),
 		"sab-features":     f.sab.Features.Tags(),
@@ -579,6 +686,10 @@ func (f *CFeature) UserActions() (actions feature.Actions) {
 		f.Action("access", "feature"),
 		f.Action("reset-own", "multi-factors"),
 		f.Action("reset-other", "multi-factors"),
+		f.Action("manage-own", "api-keys"),
+		f.Action("revoke-other", "api-keys"),
+		f.Action("manage-own", "sessions"),
+		f.Action("revoke-other", "sessions"),
 	}
 	return
 }
@@ -701,34 +812,42 @@ func (f *CFeature) ResetUserFactors(r *http.Request, eid string) (err error) {
 }
 
 type flagNames struct {
-	category        string
-	secretKey       string
-	sessionDuration string
-	xsrfHeaderName  string
-	xsrfCookieName  string
-	jwtCookieName   string
-	secureCookies   string
-	allowSignups    string
-	allowEmails     string
-	denyEmails      string
-	signInPath      string
-	signOutPath     string
+	category         string
+	secretKey        string
+	sessionDuration  string
+	xsrfHeaderName   string
+	xsrfCookieName   string
+	jwtCookieName    string
+	secureCookies    string
+	allowSignups     string
+	allowEmails      string
+	denyEmails       string
+	allowApiKeys     string
+	lockoutAccounts  string
+	lockoutAddresses string
+	lockoutDuration  string
+	signInPath       string
+	signOutPath      string
 }
 
 func (f *CFeature) makeFlagNames() (fn flagNames) {
 	category := f.KebabTag
 	return flagNames{
-		category:        category,
-		secretKey:       category + "-secret-key",
-		sessionDuration: category + "-session-duration",
-		xsrfHeaderName:  category + "-xsrf-header-name",
-		xsrfCookieName:  category + "-xsrf-cookie-name",
-		jwtCookieName:   category + "-jwt-cookie-name",
-		secureCookies:   category + "-secure-cookies",
-		allowSignups:    category + "-allow-signups",
-		allowEmails:     category + "-allow-emails",
-		denyEmails:      category + "-deny-emails",
-		signInPath:      category + "-sign-in-path",
-		signOutPath:     category + "-sign-out-path",
+		category:         category,
+		secretKey:        category + "-secret-key",
+		sessionDuration:  category + "-session-duration",
+		xsrfHeaderName:   category + "-xsrf-header-name",
+		xsrfCookieName:   category + "-xsrf-cookie-name",
+		jwtCookieName:    category + "-jwt-cookie-name",
+		secureCookies:    category + "-secure-cookies",
+		allowSignups:     category + "-allow-signups",
+		allowEmails:      category + "-allow-emails",
+		denyEmails:       category + "-deny-emails",
+		allowApiKeys:     category + "-allow-api-keys",
+		lockoutAccounts:  category + "-lockout-account-failures",
+		lockoutAddresses: category + "-lockout-address-failures",
+		lockoutDuration:  category + "-lockout-duration",
+		signInPath:       category + "-sign-in-path",
+		signOutPath:      category + "-sign-out-path",
 	}
 }
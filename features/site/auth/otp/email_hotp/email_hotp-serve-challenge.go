@@ -46,9 +46,15 @@ func (f *CFeature) ProcessChallenge(name, challenge string, saf feature.SiteAuth
 	switch r.FormValue("submit") {
 
 	case f.SiteMultiFactorKey(), f.KebabTag:
-		hotp := f.makeHotp(userSecret)
-		if m := f.sendNewToken(email, hotp.At(int(count)), r); m != nil {
-			r = m
+		if ee := saf.ThrottleAuthRequest(r, claims.EID); ee != nil {
+			// throttled, limiting inbox flooding
+			log.WarnRF(r, "new token email throttled for %q: %v", claims.EID, ee)
+			r = feature.AddErrorNotice(r, true, berrs.TooManyAttemptsError(printer))
+		} else {
+			hotp := f.makeHotp(userSecret)
+			if m := f.sendNewToken(email, hotp.At(int(count)), r); m != nil {
+				r = m
+			}
 		}
 		handled = true
 		saf.ServeChallengeRequest(w, r)
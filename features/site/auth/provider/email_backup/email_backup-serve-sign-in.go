@@ -80,6 +80,22 @@ func (f *CFeature) SiteAuthSignInHandler(w http.ResponseWriter, r *http.Request,
 
 		checkEmailMessage := printer.Sprintf("If both the email addresses are correct, an email with the confirmation token has been sent.")
 
+		if ee := saf.CheckAuthAttempt(r, eid); ee != nil {
+			// locked out, without revealing anything to the requester
+			log.WarnRF(r, "backup-email sign-in denied for %q: %v", email, ee)
+			r = feature.AddInfoNotice(r, true, checkEmailMessage)
+			f.ServeSignInConfirmationPage(email, backupEmail, saf, w, r)
+			return
+		} else if request.SafeQueryFormHash10(r, "token") == "" {
+			if ee = saf.ThrottleAuthRequest(r, eid); ee != nil {
+				// throttled, limiting inbox flooding and backup-email guessing
+				log.WarnRF(r, "backup-email sign-in throttled for %q: %v", email, ee)
+				r = feature.AddInfoNotice(r, true, checkEmailMessage)
+				f.ServeSignInConfirmationPage(email, backupEmail, saf, w, r)
+				return
+			}
+		}
+
 		if su := f.Site().SiteUsers(); su.UserPresent(eid) {
 
 			if _, present := f.getProvisionByEmail(eid, backupEmail, r); !present {
@@ -103,16 +119,19 @@ func (f *CFeature) SiteAuthSignInHandler(w http.ResponseWriter, r *http.Request,
 	// both email address fields received are correct (primary exists with backup provisioned to that same user)
 	// check for token or send email with token
 	emailTokenKey := "sign-in-backup-email-token-" + strcase.ToKebab(email)
+	eid := f.Site().SiteUsers().MakeEnjinID(f.Site().SiteUsers().MakeRealID(email))
 
 	if token = request.SafeQueryFormHash10(r, "token"); token != "" {
 
 		if f.Enjin.VerifyToken(emailTokenKey, token) {
 			ctx := context.Context{}
 			claims = saf.MakeAuthClaims(f.SiteFeatureKey(), email, ctx)
+			saf.RecordAuthSuccess(r, eid)
 			return
 		}
 
 		log.ErrorRF(r, "invalid backup sign-in token received for: %q", email)
+		saf.RecordAuthFailure(r, eid)
 		r = feature.AddErrorNotice(r, true, printer.Sprintf("sign-in backup token expired or invalid"))
 		saf.ServeSignInPage(w, r) // start from scratch
 		return
@@ -104,6 +104,20 @@ func (f *CFeature) SiteAuthSignInHandler(w http.ResponseWriter, r *http.Request,
 			return
 		}
 
+		if ee := saf.CheckAuthAttempt(r, eid); ee != nil {
+			// locked out, without revealing anything to the requester
+			log.WarnRF(r, "sign-in email denied for %q: %v", email, ee)
+			r = feature.AddInfoNotice(r, true, emailSentMessage)
+			f.ServeSignInConfirmationPage(email, saf, w, r)
+			return
+		} else if ee = saf.ThrottleAuthRequest(r, eid); ee != nil {
+			// throttled, limiting inbox flooding
+			log.WarnRF(r, "sign-in email throttled for %q: %v", email, ee)
+			r = feature.AddInfoNotice(r, true, emailSentMessage)
+			f.ServeSignInConfirmationPage(email, saf, w, r)
+			return
+		}
+
 		duration := time.Minute * 5
 		_, emailLinkToken := f.Enjin.CreateTokenWith(emailTokenKey, duration)
 		emailLinkNonce := f.Enjin.CreateNonce(SignInLinkNonceKey)
@@ -140,17 +154,26 @@ func (f *CFeature) SiteAuthSignInHandler(w http.ResponseWriter, r *http.Request,
 		return
 	}
 
-	if !f.Enjin.VerifyToken(emailTokenKey, token) {
+	su := f.Site().SiteUsers()
+	eid := su.MakeEnjinID(su.MakeRealID(email))
+
+	if ee := saf.CheckAuthAttempt(r, eid); ee != nil {
+		log.WarnRF(r, "sign-in token denied for %q: %v", email, ee)
+		r = feature.AddErrorNotice(r, true, berrs.TooManyAttemptsError(printer))
+		f.ServeSignInConfirmationPage(email, saf, w, r)
+		return
+	} else if !f.Enjin.VerifyToken(emailTokenKey, token) {
 		log.ErrorRF(r, "invalid sign-in token received for: %q", email)
+		saf.RecordAuthFailure(r, eid)
 		r = feature.AddErrorNotice(r, true, printer.Sprintf("sign-in token expired or invalid"))
 		f.ServeSignInConfirmationPage(email, saf, w, r)
 		return
 	}
+	saf.RecordAuthSuccess(r, eid)
 
 	ctx := context.Context{}
 	claims = saf.MakeAuthClaims(f.SiteFeatureKey(), email, ctx)
 
-	su := f.Site().SiteUsers()
 	if active, ee := su.GetUserActive(r, claims.EID); ee == nil && !active {
 		err = errors.New(berrs.UnexpectedError(printer))
 
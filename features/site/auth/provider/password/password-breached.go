@@ -0,0 +1,66 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"bufio"
+	"crypto/sha1"
+	"encoding/hex"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// isBreached looks up the password within the breached list directory, which
+// is expected to contain one file per five character SHA-1 hash prefix (as
+// produced by the Pwned Passwords downloader), each file listing the
+// remaining "SUFFIX:COUNT" hash suffixes. Only the range file for the prefix
+// is ever read, the same k-anonymity model as the online range API.
+func (f *CFeature) isBreached(password string) (breached bool, err error) {
+	if f.breachedList == "" {
+		return
+	}
+
+	sum := sha1.Sum([]byte(password))
+	hash := strings.ToUpper(hex.EncodeToString(sum[:]))
+	prefix, suffix := hash[:5], hash[5:]
+
+	var fh *os.File
+	if fh, err = os.Open(filepath.Join(f.breachedList, prefix)); err != nil {
+		if os.IsNotExist(err) {
+			if fh, err = os.Open(filepath.Join(f.breachedList, prefix+".txt")); err != nil {
+				if os.IsNotExist(err) {
+					// no range file means no known breaches
+					err = nil
+				}
+				return
+			}
+		} else {
+			return
+		}
+	}
+	defer fh.Close()
+
+	scanner := bufio.NewScanner(fh)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if value, _, _ := strings.Cut(line, ":"); strings.EqualFold(value, suffix) {
+			breached = true
+			return
+		}
+	}
+	err = scanner.Err()
+	return
+}
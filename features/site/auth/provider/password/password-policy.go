@@ -0,0 +1,83 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"github.com/go-corelibs/x-text/message"
+)
+
+const (
+	DefaultMinLength  = 12
+	DefaultMaxLength  = 256
+	DefaultMinClasses = 2
+)
+
+// countCharacterClasses returns the number of distinct character classes
+// (lower, upper, digit, symbol) present in the given password
+func countCharacterClasses(password string) (count int) {
+	var lower, upper, digit, symbol bool
+	for _, r := range password {
+		switch {
+		case unicode.IsLower(r):
+			lower = true
+		case unicode.IsUpper(r):
+			upper = true
+		case unicode.IsDigit(r):
+			digit = true
+		default:
+			symbol = true
+		}
+	}
+	for _, present := range []bool{lower, upper, digit, symbol} {
+		if present {
+			count += 1
+		}
+	}
+	return
+}
+
+// checkPasswordPolicy returns a list of translated problems with the password
+// given, an empty list means the password is acceptable
+func (f *CFeature) checkPasswordPolicy(printer *message.Printer, email, password, confirm string) (problems []string) {
+	length := utf8.RuneCountInString(password)
+
+	if password != confirm {
+		problems = append(problems, printer.Sprintf("The passwords given do not match"))
+	}
+	if length < f.minLength {
+		problems = append(problems, printer.Sprintf("Passwords must be at least %[1]d characters long", f.minLength))
+	} else if length > DefaultMaxLength {
+		problems = append(problems, printer.Sprintf("Passwords must be at most %[1]d characters long", DefaultMaxLength))
+	}
+	if countCharacterClasses(password) < f.minClasses {
+		problems = append(problems, printer.Sprintf("Passwords must use at least %[1]d of: lowercase, uppercase, digits and symbols", f.minClasses))
+	}
+	if name, _, _ := strings.Cut(strings.ToLower(email), "@"); len(name) >= 3 && strings.Contains(strings.ToLower(password), name) {
+		problems = append(problems, printer.Sprintf("Passwords must not contain your email address"))
+	}
+
+	if len(problems) == 0 {
+		if breached, err := f.isBreached(password); err != nil {
+			problems = append(problems, printer.Sprintf("Unable to check the password against known breaches, please try again later"))
+		} else if breached {
+			problems = append(problems, printer.Sprintf("This password has appeared in a known data breach, please choose another"))
+		}
+	}
+	return
+}
@@ -0,0 +1,107 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"net/http"
+	"time"
+
+	"github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) parseEID(eid string, r *http.Request) (parsed string) {
+	if parsed = eid; parsed == "" {
+		parsed = userbase.GetCurrentEID(r)
+	}
+	return
+}
+
+// getStoredPassword returns the password hash of the user and the time it
+// was last changed
+func (f *CFeature) getStoredPassword(eid string, r *http.Request) (hash string, changed time.Time, err error) {
+	eid = f.parseEID(eid, r)
+	var secure context.Context
+	if secure, err = f.ssc.Get(eid, r, f.Site().SiteUsers()); err != nil {
+		return
+	} else if hash = secure.String("hash", ""); hash == "" {
+		err = berrs.ErrSecretNotFound
+		return
+	}
+	changed = time.Unix(secure.Int64("changed", 0), 0)
+	return
+}
+
+func (f *CFeature) hasStoredPassword(eid string, r *http.Request) (present bool) {
+	_, _, err := f.getStoredPassword(eid, r)
+	present = err == nil
+	return
+}
+
+// setStoredPassword hashes the password with the current parameters and
+// stores it within the user's secure context
+func (f *CFeature) setStoredPassword(eid, password string, r *http.Request) (err error) {
+	eid = f.parseEID(eid, r)
+
+	err = f.storePassword(eid, password, time.Now(), r)
+	return
+}
+
+// checkStoredPassword verifies the password given, rehashing it when the
+// stored hash used different parameters than currently configured
+func (f *CFeature) checkStoredPassword(eid, password string, r *http.Request) (valid, expired bool) {
+	hash, changed, err := f.getStoredPassword(eid, r)
+	if err != nil {
+		// hash a dummy value to keep the timing consistent with real users
+		_, _ = HashPassword(password, f.hashParams)
+		return
+	}
+
+	var params HashParams
+	if valid, params, err = VerifyPassword(password, hash); err != nil || !valid {
+		valid = false
+		return
+	}
+
+	expired = f.rotationPeriod > 0 && time.Now().After(changed.Add(f.rotationPeriod))
+
+	if !expired && (params.Time != f.hashParams.Time || params.Memory != f.hashParams.Memory || params.Threads != f.hashParams.Threads || params.KeyLen != f.hashParams.KeyLen) {
+		// keep the same changed time, only upgrading the hash parameters
+		if ee := f.storePassword(eid, password, changed, r); ee != nil {
+			log.ErrorRF(r, "error rehashing password of %q: %v", eid, ee)
+		}
+	}
+	return
+}
+
+func (f *CFeature) storePassword(eid, password string, changed time.Time, r *http.Request) (err error) {
+	var hash string
+	if hash, err = HashPassword(password, f.hashParams); err != nil {
+		return
+	}
+	su := f.Site().SiteUsers()
+	su.LockUser(r, eid)
+	defer su.UnlockUser(r, eid)
+	var secure context.Context
+	if secure, err = f.ssc.GetUnsafe(eid, r, su); err != nil {
+		return
+	}
+	secure.SetSpecific("hash", hash)
+	secure.SetSpecific("changed", changed.Unix())
+	err = f.ssc.SetUnsafe(eid, r, su, secure)
+	return
+}
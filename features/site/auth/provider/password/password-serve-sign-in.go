@@ -0,0 +1,412 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/iancoleman/strcase"
+
+	clStrings "github.com/go-corelibs/strings"
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/features/site/auth"
+	"github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+)
+
+var (
+	ResetTokenDuration  = time.Minute * 30
+	RotateTokenDuration = time.Minute * 10
+)
+
+func (f *CFeature) SiteAuthSignInHandler(w http.ResponseWriter, r *http.Request, saf feature.SiteAuthFeature) (claims *feature.CSiteAuthClaims, redirect string, err error) {
+	printer := message.GetPrinter(r)
+
+	var email string
+	if email = request.SafeQueryFormEmail(r, "email"); email == "" {
+		r = feature.AddErrorNotice(r, true, berrs.IncompleteFormError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	switch action := request.SafeQueryFormValue(r, "action"); action {
+	case "forgot":
+		f.processForgotRequest(email, saf, w, r)
+	case "reset":
+		claims = f.processResetRequest(email, saf, w, r)
+	case "rotate":
+		claims = f.processRotateRequest(email, saf, w, r)
+	case "sign-up":
+		claims = f.processSignUpRequest(email, saf, w, r)
+	default:
+		claims = f.processSignInRequest(email, saf, w, r)
+	}
+	return
+}
+
+func (f *CFeature) SiteAuthLoginCallback(w http.ResponseWriter, r *http.Request, saf feature.SiteAuthFeature) (err error) {
+	return
+}
+
+func (f *CFeature) SiteAuthSignOutHandler(w http.ResponseWriter, r *http.Request, saf feature.SiteAuthFeature) (handled bool, redirect string, err error) {
+	// nothing to do, site auth handles claims reset
+	return
+}
+
+func (f *CFeature) verifyFormNonce(key, name string, r *http.Request) (valid bool) {
+	if r.Method == http.MethodPost {
+		if nonce := request.SafeQueryFormValue(r, name); nonce != "" {
+			valid = f.Enjin.VerifyNonce(key, nonce)
+		}
+	}
+	return
+}
+
+func (f *CFeature) processSignInRequest(email string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) (claims *feature.CSiteAuthClaims) {
+	printer := message.GetPrinter(r)
+	invalidMessage := printer.Sprintf("Invalid email address or password")
+
+	if !f.verifyFormNonce(SignInFormNonceKey, SignInFormNonceName, r) {
+		r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	eid := su.MakeEnjinID(su.MakeRealID(email))
+
+	if err := saf.CheckAuthAttempt(r, eid); err != nil {
+		log.WarnRF(r, "password sign-in denied for %q: %v", email, err)
+		r = feature.AddErrorNotice(r, true, berrs.TooManyAttemptsError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	valid, expired := f.checkStoredPassword(eid, r.FormValue("password"), r)
+	if valid {
+		if active, locked, _, ee := su.GetUserStatus(r, eid); ee != nil || !active || locked {
+			log.WarnRF(r, "password sign-in attempted with inactive or admin-locked account: %q", email)
+			valid = false
+		}
+	}
+
+	if !valid {
+		saf.RecordAuthFailure(r, eid)
+		r = feature.AddErrorNotice(r, true, invalidMessage)
+		saf.ServeSignInPage(w, r)
+		return
+	}
+	saf.RecordAuthSuccess(r, eid)
+
+	if expired {
+		// the password is correct, but must be changed before continuing
+		r = feature.AddImportantNotice(r, false, printer.Sprintf("Your password has expired and must be changed"))
+		f.ServeRotatePage(email, saf, w, r)
+		return
+	}
+
+	claims = saf.MakeAuthClaims(f.SiteFeatureKey(), email, context.Context{})
+	return
+}
+
+func (f *CFeature) processSignUpRequest(email string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) (claims *feature.CSiteAuthClaims) {
+	printer := message.GetPrinter(r)
+
+	if !f.verifyFormNonce(SignInFormNonceKey, SignInFormNonceName, r) {
+		r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	eid := su.MakeEnjinID(su.MakeRealID(email))
+
+	if err := saf.CheckAuthAttempt(r, eid); err != nil {
+		log.WarnRF(r, "password sign-up denied for %q: %v", email, err)
+		r = feature.AddErrorNotice(r, true, berrs.TooManyAttemptsError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	} else if err = saf.ThrottleAuthRequest(r, eid); err != nil {
+		log.WarnRF(r, "password sign-up throttled for %q: %v", email, err)
+		r = feature.AddErrorNotice(r, true, berrs.TooManyAttemptsError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	} else if su.UserPresent(eid) || !saf.IsUserAllowed(email) {
+		log.WarnRF(r, "password sign-up denied for existing or disallowed email: %q", email)
+		r = feature.AddErrorNotice(r, true, printer.Sprintf("Unable to create an account with the details given"))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	password := r.FormValue("password")
+	if problems := f.checkPasswordPolicy(printer, email, password, r.FormValue("confirm")); len(problems) > 0 {
+		for _, problem := range problems {
+			r = feature.AddErrorNotice(r, true, problem)
+		}
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	candidate := saf.MakeAuthClaims(f.SiteFeatureKey(), email, context.Context{})
+	if err := su.SignUpUser(r, candidate); err != nil {
+		log.ErrorRF(r, "error signing up new password user: %q - %v", email, err)
+		r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	} else if err = f.setStoredPassword(candidate.EID, password, r); err != nil {
+		log.ErrorRF(r, "error storing new user password: %q - %v", email, err)
+		r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	claims = candidate
+	return
+}
+
+func (f *CFeature) processForgotRequest(email string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) {
+	printer := message.GetPrinter(r)
+	sentMessage := printer.Sprintf("If an account exists for %[1]s, a password reset email has been sent.", email)
+
+	if !f.ResetAllowed() {
+		r = feature.AddErrorNotice(r, true, printer.Sprintf("Password resets are not available on this site"))
+		saf.ServeSignInPage(w, r)
+		return
+	} else if !f.verifyFormNonce(SignInFormNonceKey, SignInFormNonceName, r) {
+		r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	eid := su.MakeEnjinID(su.MakeRealID(email))
+
+	if err := saf.CheckAuthAttempt(r, eid); err != nil {
+		// locked out, without revealing anything to the requester
+		log.WarnRF(r, "password reset email denied for %q: %v", email, err)
+		r = feature.AddInfoNotice(r, true, sentMessage)
+		saf.ServeSignInPage(w, r)
+		return
+	} else if err = saf.ThrottleAuthRequest(r, eid); err != nil {
+		// throttled, limiting inbox flooding
+		log.WarnRF(r, "password reset email throttled for %q: %v", email, err)
+		r = feature.AddInfoNotice(r, true, sentMessage)
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	if active, locked, _, ee := su.GetUserStatus(r, eid); ee != nil || !active || locked {
+		log.WarnRF(r, "password reset requested for unknown, inactive or admin-locked account: %q", email)
+		r = feature.AddInfoNotice(r, true, sentMessage)
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	_, resetToken := f.Enjin.CreateTokenWith(f.resetTokenKey(email), ResetTokenDuration)
+	link := fmt.Sprintf(
+		"%s%s?%s",
+		request.ParseDomainUrl(r),
+		saf.SiteAuthSignInPath(),
+		url.Values{
+			auth.SignInNonceName: {f.Enjin.CreateNonce(auth.SignInNonceKey)},
+			"audience":           {f.SiteFeatureKey()},
+			"action":             {"reset"},
+			"email":              {email},
+			"token":              {resetToken},
+			ResetLinkNonceName:   {f.Enjin.CreateNonce(ResetLinkNonceKey)},
+		}.Encode(),
+	)
+
+	bodyCtx := context.Context{
+		"Name":       clStrings.NameFromEmail(email),
+		"Email":      email,
+		"Link":       link,
+		"Token":      resetToken,
+		"Duration":   strings.TrimSuffix(ResetTokenDuration.Round(time.Minute).String(), "0s"),
+		"Expiration": time.Now().Add(ResetTokenDuration),
+		"SiteName":   f.Enjin.SiteName(),
+	}
+
+	subject := printer.Sprintf("%[1]s Password Reset", f.Enjin.SiteName())
+	if err := f.sendUserEmail(r, email, subject, "password--reset", bodyCtx); err != nil {
+		log.ErrorRF(r, "error sending password reset email: %q - %v", email, err)
+	}
+
+	r = feature.AddInfoNotice(r, true, sentMessage)
+	saf.ServeSignInPage(w, r)
+	return
+}
+
+func (f *CFeature) processResetRequest(email string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) (claims *feature.CSiteAuthClaims) {
+	printer := message.GetPrinter(r)
+	expiredMessage := printer.Sprintf("Password reset link expired or invalid")
+
+	if !f.ResetAllowed() {
+		f.Enjin.ServeNotFound(w, r)
+		return
+	}
+
+	token := request.SafeQueryFormHash10(r, "token")
+
+	if r.Method == http.MethodGet {
+		// arriving from the reset email link
+		if nonce := request.SafeQueryFormValue(r, ResetLinkNonceName); nonce == "" || !f.Enjin.VerifyNonce(ResetLinkNonceKey, nonce) {
+			r = feature.AddErrorNotice(r, true, berrs.IncompleteLinkError(printer))
+			saf.ServeSignInPage(w, r)
+			return
+		} else if token == "" || !f.Enjin.VerifyToken(f.resetTokenKey(email), token) {
+			r = feature.AddErrorNotice(r, true, expiredMessage)
+			saf.ServeSignInPage(w, r)
+			return
+		}
+		f.ServeResetPage(email, saf, w, r)
+		return
+	}
+
+	if !f.verifyFormNonce(ResetFormNonceKey, ResetFormNonceName, r) {
+		r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	} else if token == "" || !f.Enjin.VerifyToken(f.resetTokenKey(email), token) {
+		r = feature.AddErrorNotice(r, true, expiredMessage)
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	password := r.FormValue("password")
+	if problems := f.checkPasswordPolicy(printer, email, password, r.FormValue("confirm")); len(problems) > 0 {
+		for _, problem := range problems {
+			r = feature.AddErrorNotice(r, true, problem)
+		}
+		f.ServeResetPage(email, saf, w, r)
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	eid := su.MakeEnjinID(su.MakeRealID(email))
+	if err := f.setStoredPassword(eid, password, r); err != nil {
+		log.ErrorRF(r, "error storing reset password: %q - %v", email, err)
+		r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	saf.RecordAuthSuccess(r, eid)
+	r = feature.AddInfoNotice(r, true, printer.Sprintf("Your password has been changed"))
+	claims = saf.MakeAuthClaims(f.SiteFeatureKey(), email, context.Context{})
+	return
+}
+
+func (f *CFeature) processRotateRequest(email string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) (claims *feature.CSiteAuthClaims) {
+	printer := message.GetPrinter(r)
+
+	if !f.verifyFormNonce(RotateFormNonceKey, RotateFormNonceName, r) {
+		r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	} else if token := request.SafeQueryFormHash10(r, "token"); token == "" || !f.Enjin.VerifyToken(f.rotateTokenKey(email), token) {
+		r = feature.AddErrorNotice(r, true, printer.Sprintf("Password change expired, please sign in again"))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	eid := su.MakeEnjinID(su.MakeRealID(email))
+
+	password := r.FormValue("password")
+	problems := f.checkPasswordPolicy(printer, email, password, r.FormValue("confirm"))
+	if hash, _, err := f.getStoredPassword(eid, r); err == nil {
+		if same, _, _ := VerifyPassword(password, hash); same {
+			problems = append(problems, printer.Sprintf("The new password must be different from the current password"))
+		}
+	}
+	if len(problems) > 0 {
+		for _, problem := range problems {
+			r = feature.AddErrorNotice(r, true, problem)
+		}
+		f.ServeRotatePage(email, saf, w, r)
+		return
+	}
+
+	if err := f.setStoredPassword(eid, password, r); err != nil {
+		log.ErrorRF(r, "error storing rotated password: %q - %v", email, err)
+		r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+		saf.ServeSignInPage(w, r)
+		return
+	}
+
+	claims = saf.MakeAuthClaims(f.SiteFeatureKey(), email, context.Context{})
+	return
+}
+
+func (f *CFeature) resetTokenKey(email string) (key string) {
+	key = "password-reset-token-" + strcase.ToKebab(email)
+	return
+}
+
+func (f *CFeature) rotateTokenKey(email string) (key string) {
+	key = "password-rotate-token-" + strcase.ToKebab(email)
+	return
+}
+
+// ServeResetPage serves the form for choosing a new password, the form is
+// given a new reset token as the one received has been used
+func (f *CFeature) ServeResetPage(email string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) {
+	_, token := f.Enjin.CreateTokenWith(f.resetTokenKey(email), ResetTokenDuration)
+	f.servePasswordPage("password--reset", email, token, feature.Nonces{
+		{Name: auth.SignInNonceName, Key: auth.SignInNonceKey},
+		{Name: ResetFormNonceName, Key: ResetFormNonceKey},
+	}, saf, w, r)
+}
+
+// ServeRotatePage serves the form for changing an expired password, the form
+// is given a short-lived token proving the current password was just given
+func (f *CFeature) ServeRotatePage(email string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) {
+	_, token := f.Enjin.CreateTokenWith(f.rotateTokenKey(email), RotateTokenDuration)
+	f.servePasswordPage("password--rotate", email, token, feature.Nonces{
+		{Name: auth.SignInNonceName, Key: auth.SignInNonceKey},
+		{Name: RotateFormNonceName, Key: RotateFormNonceKey},
+	}, saf, w, r)
+}
+
+func (f *CFeature) servePasswordPage(pageType, email, token string, nonces feature.Nonces, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) {
+	printer := message.GetPrinter(r)
+
+	ctx := context.Context{
+		"FeatureInfo":  f.SiteFeatureInfo(r),
+		"FormAction":   saf.SiteAuthSignInPath(),
+		"Nonces":       nonces,
+		"EmailAddress": email,
+		"Token":        token,
+		"PasswordPolicy": printer.Sprintf(
+			"Passwords must be at least %[1]d characters long and use at least %[2]d of: lowercase, uppercase, digits and symbols",
+			f.minLength, f.minClasses,
+		),
+	}
+
+	t := saf.Site().SiteTheme()
+	r = request.SetHomePath(r, saf.SiteAuthSignInPath())
+	if err := saf.Site().PrepareAndServePage("site-auth", pageType, saf.SiteAuthSignInPath(), t, w, r, ctx); err != nil {
+		log.ErrorRF(r, "error preparing and serving %v page: %v", pageType, err)
+		panic(err)
+	}
+}
@@ -0,0 +1,125 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"net/http"
+
+	clPath "github.com/go-corelibs/path"
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) SiteAuthSettingsPanel(settingsPath string, saf feature.SiteAuthFeature) (serve, handle http.HandlerFunc) {
+	// settingsPath is the path to this feature's settings panel
+	serve = f.MakeSiteSettingsPanel(settingsPath, saf)
+	handle = f.MakeSiteSettingsPanel(settingsPath, saf)
+	return
+}
+
+func (f *CFeature) MakeSiteSettingsPanel(settingsPath string, saf feature.SiteAuthFeature) (handler http.HandlerFunc) {
+	return func(w http.ResponseWriter, r *http.Request) {
+
+		if _, ok := clPath.MatchCut(r.URL.Path, settingsPath); ok {
+			if allowed := f.Site().RequireVerification(settingsPath, w, r); !allowed {
+				return
+			}
+			if r.Method == http.MethodPost {
+				var redirect string
+				if r, redirect = f.ProcessManageRequest(settingsPath, r); redirect != "" {
+					f.Enjin.ServeRedirect(redirect, w, r)
+					return
+				}
+			}
+			f.ServeManagePage(settingsPath, saf, w, r)
+			return
+		}
+
+		log.ErrorRF(r, "bad routing, password settings panel handler received %q request for: %q", r.Method, r.URL.Path)
+		f.Enjin.ServeInternalServerError(w, r)
+	}
+}
+
+// ProcessManageRequest validates and stores a new password for the current
+// user, returning a non-empty redirect on success and the request modified
+// with any user notices otherwise
+func (f *CFeature) ProcessManageRequest(settingsPath string, r *http.Request) (modified *http.Request, redirect string) {
+	modified = r
+	printer := message.GetPrinter(r)
+	au := userbase.GetCurrentUser(r)
+	eid := au.GetEID()
+	email := au.GetEmail()
+
+	_ = r.ParseForm()
+	if nonce := request.SafeQueryFormValue(r, ManageNonceName); nonce == "" || !f.Enjin.VerifyNonce(ManageNonceKey, nonce) {
+		modified = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		return
+	}
+
+	if f.hasStoredPassword(eid, r) {
+		// changing an existing password requires knowing the current one
+		if valid, _ := f.checkStoredPassword(eid, r.FormValue("current"), r); !valid {
+			modified = feature.AddErrorNotice(r, true, printer.Sprintf("The current password given is incorrect"))
+			return
+		}
+	}
+
+	password := r.FormValue("password")
+	if problems := f.checkPasswordPolicy(printer, email, password, r.FormValue("confirm")); len(problems) > 0 {
+		for _, problem := range problems {
+			modified = feature.AddErrorNotice(modified, true, problem)
+		}
+		return
+	}
+
+	if err := f.setStoredPassword(eid, password, r); err != nil {
+		log.ErrorRF(r, "error storing user password: %v", err)
+		modified = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+		return
+	}
+
+	f.Site().PushInfoNotice(eid, true, printer.Sprintf("Your password has been changed"))
+	redirect = settingsPath
+	return
+}
+
+func (f *CFeature) ServeManagePage(settingsPath string, saf feature.SiteAuthFeature, w http.ResponseWriter, r *http.Request) {
+	printer := message.GetPrinter(r)
+	eid := userbase.GetCurrentEID(r)
+
+	ctx := context.Context{
+		"FeatureInfo": f.SiteFeatureInfo(r),
+		"FormAction":  settingsPath,
+		"Nonces": feature.Nonces{
+			{Name: ManageNonceName, Key: ManageNonceKey},
+		},
+		"HasPassword": f.hasStoredPassword(eid, r),
+		"PasswordPolicy": printer.Sprintf(
+			"Passwords must be at least %[1]d characters long and use at least %[2]d of: lowercase, uppercase, digits and symbols",
+			f.minLength, f.minClasses,
+		),
+	}
+
+	t := f.Site().SiteTheme()
+	if err := f.Site().PrepareAndServePage("site-auth", "password--manage", settingsPath, t, w, r, ctx); err != nil {
+		log.ErrorRF(r, "error preparing and serving password--manage page: %v", err)
+		panic(err)
+	}
+}
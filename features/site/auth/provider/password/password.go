@@ -0,0 +1,305 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package password
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/pkg/feature"
+	site_secure_context "github.com/go-enjin/be/pkg/feature/site-secure-context"
+	"github.com/go-enjin/be/types/site"
+)
+
+const (
+	SignInFormNonceKey  = "password--sign-in--form"
+	SignInFormNonceName = "password--sign-in--nonce"
+	ResetLinkNonceKey   = "password--reset--link"
+	ResetLinkNonceName  = "nonce"
+	ResetFormNonceKey   = "password--reset--form"
+	ResetFormNonceName  = "password--reset--nonce"
+	RotateFormNonceKey  = "password--rotate--form"
+	RotateFormNonceName = "password--rotate--nonce"
+	ManageNonceKey      = "password--manage--form"
+	ManageNonceName     = "password--manage--nonce"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "site-auth-provider-password"
+
+type Feature interface {
+	feature.SiteFeature
+	feature.SiteAuthProvider
+	feature.SiteAuthSettingsPanel
+}
+
+type MakeFeature interface {
+	feature.SiteMakeFeature[MakeFeature]
+
+	// SetEmailAccount specifies the email account to use when sending
+	// password reset emails, the reset flow is disabled without an account
+	SetEmailAccount(account string) MakeFeature
+	SetEmailProvider(tag feature.Tag) MakeFeature
+
+	// SetHashParams specifies the argon2id parameters for new password hashes
+	SetHashParams(params HashParams) MakeFeature
+	// SetPasswordPolicy specifies the minimum length and minimum number of
+	// character classes (lower, upper, digit, symbol) of new passwords
+	SetPasswordPolicy(minLength, minClasses int) MakeFeature
+	// SetBreachedList specifies the directory of Pwned Passwords range files
+	// used to reject known breached passwords
+	SetBreachedList(path string) MakeFeature
+	// SetRotationPeriod forces users to change passwords older than the
+	// given duration, zero disables rotation
+	SetRotationPeriod(period time.Duration) MakeFeature
+
+	Make() Feature
+}
+
+type CFeature struct {
+	site.CSiteFeature[MakeFeature]
+
+	emailProviderTag feature.Tag
+	emailSender      feature.EmailSender
+	emailProvider    feature.EmailProvider
+	emailAccount     string
+
+	hashParams     HashParams
+	minLength      int
+	minClasses     int
+	breachedList   string
+	rotationPeriod time.Duration
+
+	ssc *site_secure_context.CSecureContext
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.SetSiteFeatureKey("password")
+	f.SetSiteFeatureIcon("fa-solid fa-lock")
+	f.SetSiteFeatureLabel(func(printer *message.Printer) (label string) {
+		label = printer.Sprintf("Password")
+		return
+	})
+	f.CSiteFeature.Construct(f)
+	f.ssc = site_secure_context.New(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CSiteFeature.Init(this)
+	f.hashParams = DefaultHashParams
+	f.minLength = DefaultMinLength
+	f.minClasses = DefaultMinClasses
+	return
+}
+
+func (f *CFeature) SetEmailAccount(account string) MakeFeature {
+	f.emailAccount = account
+	return f
+}
+
+func (f *CFeature) SetEmailProvider(tag feature.Tag) MakeFeature {
+	f.emailProviderTag = tag
+	return f
+}
+
+func (f *CFeature) SetHashParams(params HashParams) MakeFeature {
+	f.hashParams = params
+	return f
+}
+
+func (f *CFeature) SetPasswordPolicy(minLength, minClasses int) MakeFeature {
+	f.minLength = minLength
+	f.minClasses = minClasses
+	return f
+}
+
+func (f *CFeature) SetBreachedList(path string) MakeFeature {
+	f.breachedList = path
+	return f
+}
+
+func (f *CFeature) SetRotationPeriod(period time.Duration) MakeFeature {
+	f.rotationPeriod = period
+	return f
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	if err = f.CSiteFeature.Build(b); err != nil {
+		return
+	} else if err = f.ssc.Build(b); err != nil {
+		return
+	}
+	b.AddFlags(
+		&cli.UintFlag{
+			Name:     f.KebabTag + "-argon2-time",
+			Usage:    "specify the argon2id time (iterations) parameter",
+			Value:    uint(f.hashParams.Time),
+			EnvVars:  b.MakeEnvKeys(f.KebabTag + "-argon2-time"),
+			Category: f.KebabTag,
+		},
+		&cli.UintFlag{
+			Name:     f.KebabTag + "-argon2-memory",
+			Usage:    "specify the argon2id memory parameter, in KiB",
+			Value:    uint(f.hashParams.Memory),
+			EnvVars:  b.MakeEnvKeys(f.KebabTag + "-argon2-memory"),
+			Category: f.KebabTag,
+		},
+		&cli.UintFlag{
+			Name:     f.KebabTag + "-argon2-threads",
+			Usage:    "specify the argon2id threads parameter",
+			Value:    uint(f.hashParams.Threads),
+			EnvVars:  b.MakeEnvKeys(f.KebabTag + "-argon2-threads"),
+			Category: f.KebabTag,
+		},
+		&cli.IntFlag{
+			Name:     f.KebabTag + "-min-length",
+			Usage:    "specify the minimum password length",
+			Value:    f.minLength,
+			EnvVars:  b.MakeEnvKeys(f.KebabTag + "-min-length"),
+			Category: f.KebabTag,
+		},
+		&cli.IntFlag{
+			Name:     f.KebabTag + "-min-classes",
+			Usage:    "specify the minimum number of character classes",
+			Value:    f.minClasses,
+			EnvVars:  b.MakeEnvKeys(f.KebabTag + "-min-classes"),
+			Category: f.KebabTag,
+		},
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-breached-list",
+			Usage:    "specify the directory of Pwned Passwords range files",
+			EnvVars:  b.MakeEnvKeys(f.KebabTag + "-breached-list"),
+			Category: f.KebabTag,
+		},
+		&cli.Int64Flag{
+			Name:     f.KebabTag + "-rotation-days",
+			Usage:    "force users to change passwords older than this many days (0 disables)",
+			Value:    int64(f.rotationPeriod.Hours() / 24),
+			EnvVars:  b.MakeEnvKeys(f.KebabTag + "-rotation-days"),
+			Category: f.KebabTag,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CSiteFeature.Startup(ctx); err != nil {
+		return
+	} else if err = f.ssc.Startup(ctx); err != nil {
+		return
+	}
+
+	if key := f.KebabTag + "-argon2-time"; ctx.IsSet(key) {
+		f.hashParams.Time = uint32(ctx.Uint(key))
+	}
+	if key := f.KebabTag + "-argon2-memory"; ctx.IsSet(key) {
+		f.hashParams.Memory = uint32(ctx.Uint(key))
+	}
+	if key := f.KebabTag + "-argon2-threads"; ctx.IsSet(key) {
+		f.hashParams.Threads = uint8(ctx.Uint(key))
+	}
+	if f.hashParams.Time < 1 || f.hashParams.Memory < 8*uint32(f.hashParams.Threads) || f.hashParams.Threads < 1 {
+		err = fmt.Errorf("invalid argon2id parameters: %+v", f.hashParams)
+		return
+	}
+
+	if key := f.KebabTag + "-min-length"; ctx.IsSet(key) {
+		f.minLength = ctx.Int(key)
+	}
+	if key := f.KebabTag + "-min-classes"; ctx.IsSet(key) {
+		f.minClasses = ctx.Int(key)
+	}
+
+	if key := f.KebabTag + "-breached-list"; ctx.IsSet(key) {
+		f.breachedList = ctx.String(key)
+	}
+	if f.breachedList != "" {
+		if info, ee := os.Stat(f.breachedList); ee != nil || !info.IsDir() {
+			err = fmt.Errorf("breached list is not a directory: %q", f.breachedList)
+			return
+		}
+	}
+
+	if key := f.KebabTag + "-rotation-days"; ctx.IsSet(key) {
+		f.rotationPeriod = time.Hour * 24 * time.Duration(ctx.Int64(key))
+	}
+
+	if f.emailAccount == "" {
+		// password reset emails are optional
+		return
+	} else if f.emailSender = f.Enjin.FindEmailAccount(f.emailAccount); f.emailSender == nil {
+		err = fmt.Errorf("%v email sender not found", f.emailAccount)
+		return
+	} else if f.emailProviderTag.IsNil() {
+		err = fmt.Errorf(".SetEmailProvider is required")
+		return
+	} else if epf, ok := f.Enjin.Features().Get(f.emailProviderTag); !ok {
+		err = fmt.Errorf("%v email provider feature not found", f.emailProviderTag)
+		return
+	} else if ep, ok := epf.This().(feature.EmailProvider); !ok {
+		err = fmt.Errorf("%v feature is not a feature.EmailProvider", f.emailProviderTag)
+		return
+	} else {
+		f.emailProvider = ep
+	}
+
+	return
+}
+
+func (f *CFeature) SiteFeatureInfo(r *http.Request) (info *feature.CSiteFeatureInfo) {
+	printer := message.GetPrinter(r)
+	info = feature.NewSiteFeatureInfo(
+		f.KebabTag,
+		f.SiteFeatureKey(),
+		f.SiteFeatureIcon(),
+		f.SiteFeatureLabel(printer),
+	)
+	info.Usage = printer.Sprintf("Password sign-ins require your email address and the password chosen for this site.")
+	info.Hint = printer.Sprintf("Sign in with password")
+	info.Placeholder = printer.Sprintf("password")
+	return
+}
+
+func (f *CFeature) IsBackupProvider() (backup bool) {
+	return false
+}
+
+// ResetAllowed returns true if password reset emails can be sent
+func (f *CFeature) ResetAllowed() (allowed bool) {
+	allowed = f.emailProvider != nil
+	return
+}
@@ -0,0 +1,134 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_api
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+
+	beContext "github.com/go-enjin/be/pkg/context"
+	bePkgEditor "github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+// getOperation returns the editor's own operation for the given key, these
+// are the same operations, with the same permissions, as the editor uses for
+// its html forms
+func getOperation(ef feature.EditorFeature, key string) (op *feature.EditorOperation, ok bool) {
+	if ops := ef.GetFileOperations(); ops != nil {
+		op, ok = ops[key]
+		ok = ok && op != nil
+	}
+	return
+}
+
+// makeOperationForm translates the API request into the form context the
+// editor operation handlers expect from the html editor forms
+func makeOperationForm(op string, req *Request) (form beContext.Context) {
+	form = beContext.Context{}
+	for k, v := range req.Form {
+		form[k] = v
+	}
+	form["submit"] = op
+	form["return"] = "file"
+	if req.Body != nil {
+		form["body"] = *req.Body
+	}
+	switch op {
+	case bePkgEditor.CopyActionKey, bePkgEditor.MoveActionKey, bePkgEditor.TranslateActionKey:
+		form[op+"~dst-fsid"] = req.FSID
+		form[op+"~dst-lang"] = req.Lang
+		form[op+"~dst-path"] = req.Path
+		form[op+"~dst-name"] = req.Name
+	}
+	return
+}
+
+// prepareOperationPage builds the editor page and context given to the
+// editor operation handlers, the same as the editor prepares them for its
+// html forms
+func (f *CFeature) prepareOperationPage(ef feature.EditorFeature, info *bePkgEditor.File, r *http.Request) (pg feature.Page, ctx beContext.Context, err error) {
+	if pg, ctx, err = ef.PrepareEditPage("file-editor", ef.GetEditorType(), r); err != nil {
+		return
+	} else if pg == nil {
+		err = fmt.Errorf("editor page not found")
+		return
+	}
+	ctx.SetSpecific("EditFSID", info.FSID)
+	if info.Locale != nil {
+		ctx.SetSpecific("EditLang", info.Locale.String())
+	} else {
+		ctx.SetSpecific("EditCode", info.Code)
+	}
+	ctx.SetSpecific("EditPath", info.Path)
+	ctx.SetSpecific("EditFile", info.File)
+	ctx.SetSpecific("FileInfo", info)
+	return
+}
+
+// runOperation performs the editor operation on behalf of the current user,
+// the user notices produced by the operation are returned instead of being
+// left for the next html page view and any error notices are reported as
+// failed
+func (f *CFeature) runOperation(ef feature.EditorFeature, op *feature.EditorOperation, form beContext.Context, info *bePkgEditor.File, r *http.Request) (notices feature.UserNotices, failed bool) {
+	eid := userbase.GetCurrentEID(r)
+
+	// the editor handlers use r.PostFormValue in some cases
+	m := r.Clone(r.Context())
+	m.PostForm = url.Values{}
+	for k, v := range form {
+		if value, ok := v.(string); ok {
+			m.PostForm.Set(k, value)
+		}
+	}
+	m.PostForm.Set("submit", op.Key)
+	m.Form = m.PostForm
+
+	pending := f.Site().PullNotices(eid)
+	defer func() {
+		f.Site().PushNotices(eid, pending...)
+	}()
+
+	pg, ctx, err := f.prepareOperationPage(ef, info, m)
+	if err != nil {
+		log.ErrorRF(r, "error preparing %v editor page: %v", ef.Tag(), err)
+		notices = append(notices, feature.MakeErrorNotice(true, "error preparing editor page"))
+		failed = true
+		return
+	}
+
+	if op.Validate != nil {
+		if err = op.Validate(m, pg, ctx, form, info, eid); err != nil {
+			notices = append(notices, feature.MakeErrorNotice(true, err.Error()))
+			failed = true
+			return
+		}
+	}
+
+	if op.Operation != nil {
+		_ = op.Operation(m, pg, ctx, form, info, eid)
+	}
+
+	notices = f.Site().PullNotices(eid)
+	for _, notice := range notices {
+		if failed = notice.Type == "error"; failed {
+			break
+		}
+	}
+	return
+}
@@ -0,0 +1,90 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_api
+
+import (
+	"net/http"
+
+	"github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+)
+
+const (
+	gEditorRequestKey request.Key = "site-content-api-editor"
+)
+
+// Response is the JSON structure returned by all content-api endpoints
+type Response struct {
+	Status  int                 `json:"status"`
+	Error   string              `json:"error,omitempty"`
+	Editors []*EditorInfo       `json:"editors,omitempty"`
+	File    *editor.File        `json:"file,omitempty"`
+	Body    *string             `json:"body,omitempty"`
+	Draft   *string             `json:"draft,omitempty"`
+	Dirs    editor.Files        `json:"dirs,omitempty"`
+	Files   editor.Files        `json:"files,omitempty"`
+	Notices feature.UserNotices `json:"notices,omitempty"`
+}
+
+// EditorInfo describes one of the feature.EditorFeature instances exposed
+type EditorInfo struct {
+	Key         string       `json:"key"`
+	Tag         string       `json:"tag"`
+	Type        string       `json:"type"`
+	FileSystems editor.Files `json:"filesystems,omitempty"`
+}
+
+// Request is the JSON structure accepted by the PUT and POST endpoints
+type Request struct {
+	// Op is the editor operation key, see editor.PublishActionKey and friends
+	Op string `json:"op"`
+	// Body is the file contents for PUT requests and optionally for publish
+	Body *string `json:"body,omitempty"`
+	// FSID is the destination filesystem for copy, move and translate
+	FSID string `json:"fsid,omitempty"`
+	// Lang is the destination locale for copy, move and translate
+	Lang string `json:"lang,omitempty"`
+	// Path is the destination directory path for copy, move and translate
+	Path string `json:"path,omitempty"`
+	// Name is the destination file name for copy, move and translate
+	Name string `json:"name,omitempty"`
+	// Form is any additional editor form fields the operation requires, such
+	// as the "matter" of the pages editor change operation
+	Form map[string]interface{} `json:"form,omitempty"`
+}
+
+func setRequestEditor(r *http.Request, ef feature.EditorFeature) (modified *http.Request) {
+	modified = request.Set(r, gEditorRequestKey, ef)
+	return
+}
+
+func getRequestEditor(r *http.Request) (ef feature.EditorFeature) {
+	ef, _ = request.Value[feature.EditorFeature](r, gEditorRequestKey)
+	return
+}
+
+func (f *CFeature) serveResponse(status int, response *Response, w http.ResponseWriter, r *http.Request) {
+	response.Status = status
+	if err := f.Enjin.ServeStatusJSON(status, response, w, r); err != nil {
+		log.ErrorRF(r, "error serving %v json response: %v", f.Tag(), err)
+		f.Enjin.ServeInternalServerError(w, r)
+	}
+}
+
+func (f *CFeature) serveError(status int, message string, w http.ResponseWriter, r *http.Request) {
+	f.serveResponse(status, &Response{Error: message}, w, r)
+}
@@ -0,0 +1,142 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_api
+
+import (
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+
+	clPath "github.com/go-corelibs/path"
+	"github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) ServeListEditors(w http.ResponseWriter, r *http.Request) {
+	response := &Response{}
+	for _, ef := range f.editors.Features {
+		if userbase.CurrentUserCan(r, ef.Action("access", "feature")) {
+			info := &EditorInfo{
+				Key: ef.GetEditorKey(),
+				Tag: ef.Tag().String(),
+			}
+			if userbase.CurrentUserCan(r, ef.Action("view", "file-browser")) {
+				info.FileSystems = ef.ListFileSystems()
+			}
+			response.Editors = append(response.Editors, info)
+		}
+	}
+	f.serveResponse(http.StatusOK, response, w, r)
+}
+
+func (f *CFeature) ServeListFileSystems(w http.ResponseWriter, r *http.Request) {
+	ef := getRequestEditor(r)
+	if !userbase.CurrentUserCan(r, ef.Action("view", "file-browser")) {
+		f.serveError(http.StatusForbidden, "permission denied", w, r)
+		return
+	}
+	f.serveResponse(http.StatusOK, &Response{Dirs: ef.ListFileSystems()}, w, r)
+}
+
+func (f *CFeature) ServeListLocales(w http.ResponseWriter, r *http.Request) {
+	ef := getRequestEditor(r)
+	if !userbase.CurrentUserCan(r, ef.Action("view", "file-browser")) {
+		f.serveError(http.StatusForbidden, "permission denied", w, r)
+		return
+	}
+	fsid := chi.URLParam(r, "fsid")
+	if list := ef.ListFileSystemLocales(fsid); len(list) > 0 {
+		f.serveResponse(http.StatusOK, &Response{Dirs: list}, w, r)
+		return
+	}
+	f.serveError(http.StatusNotFound, "filesystem not found", w, r)
+}
+
+func (f *CFeature) ServeReadPath(w http.ResponseWriter, r *http.Request) {
+	ef := getRequestEditor(r)
+
+	fsid, code, file, locale := ef.ParseEditorUrlParams(r)
+	if fsid == "" || locale == nil {
+		f.serveError(http.StatusNotFound, "filesystem not found", w, r)
+		return
+	}
+
+	if info := f.parseFileInfo(ef, r); info != nil {
+		// file requested
+		if !userbase.CurrentUserCan(r, ef.Action("view", "file-editor")) {
+			f.serveError(http.StatusForbidden, "permission denied", w, r)
+			return
+		}
+		response := &Response{File: info}
+		if data, err := ef.ReadFile(info); err != nil {
+			log.ErrorRF(r, "error reading file: %v - %v", info.FilePath(), err)
+			f.serveError(http.StatusInternalServerError, "error reading file", w, r)
+			return
+		} else {
+			body := string(data)
+			response.Body = &body
+		}
+		if info.HasDraft {
+			if data, err := ef.ReadDraft(info); err != nil {
+				log.ErrorRF(r, "error reading draft: %v - %v", info.FilePath(), err)
+			} else {
+				draft := string(data)
+				response.Draft = &draft
+			}
+		}
+		f.serveResponse(http.StatusOK, response, w, r)
+		return
+	}
+
+	// directory requested
+	if !userbase.CurrentUserCan(r, ef.Action("view", "file-browser")) {
+		f.serveError(http.StatusForbidden, "permission denied", w, r)
+		return
+	}
+	if file != "" {
+		file = clPath.TrimSlashes(file)
+	}
+	f.serveResponse(http.StatusOK, &Response{
+		Dirs:  ef.ListFileSystemDirectories(r, fsid, code, file),
+		Files: ef.ListFileSystemFiles(r, fsid, code, file),
+	}, w, r)
+}
+
+// parseFileInfo returns the prepared editor.File for the request, or nil if the file does not exist
+func (f *CFeature) parseFileInfo(ef feature.EditorFeature, r *http.Request) (info *editor.File) {
+	if info = f.parseNewFileInfo(ef, r); info == nil {
+		return
+	} else if !ef.FileExists(info) {
+		info = nil
+		return
+	}
+	info = ef.PrepareEditableFile(r, info)
+	return
+}
+
+// parseNewFileInfo returns the parsed editor.File for the request without checking if the file exists
+func (f *CFeature) parseNewFileInfo(ef feature.EditorFeature, r *http.Request) (info *editor.File) {
+	fsid, code, file, locale := ef.ParseEditorUrlParams(r)
+	if fsid == "" || locale == nil || file == "" {
+		return
+	}
+	if filePath := editor.MakeLangCodePath(locale.String(), file); filePath != "" {
+		info = editor.ParseFile(fsid, filePath)
+		info.Code = code
+	}
+	return
+}
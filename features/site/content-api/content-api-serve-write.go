@@ -0,0 +1,188 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_api
+
+import (
+	"encoding/json"
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	clPath "github.com/go-corelibs/path"
+	bePkgEditor "github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+var (
+	// MaxRequestBodySize is the maximum number of bytes accepted for PUT and POST requests
+	MaxRequestBodySize int64 = 1024 * 1024 * 16
+)
+
+func (f *CFeature) parseRequest(w http.ResponseWriter, r *http.Request) (req *Request, ok bool) {
+	req = &Request{}
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, MaxRequestBodySize))
+	if err := decoder.Decode(req); err != nil {
+		log.WarnRF(r, "error decoding %v request: %v", f.Tag(), err)
+		f.serveError(http.StatusBadRequest, "invalid request body", w, r)
+		return
+	}
+	req.Op = strings.TrimSpace(req.Op)
+	ok = true
+	return
+}
+
+// HandleWriteDraft creates new files or saves the request body as the draft
+// of an existing file, existing files are locked to the current user
+func (f *CFeature) HandleWriteDraft(w http.ResponseWriter, r *http.Request) {
+	ef := getRequestEditor(r)
+	eid := userbase.GetCurrentEID(r)
+
+	req, ok := f.parseRequest(w, r)
+	if !ok {
+		return
+	} else if req.Body == nil {
+		f.serveError(http.StatusBadRequest, "missing body", w, r)
+		return
+	}
+	body := strings.ReplaceAll(*req.Body, "\r", "")
+	req.Body = &body
+
+	info := f.parseFileInfo(ef, r)
+	if info == nil {
+		// new file
+		if info = f.parseNewFileInfo(ef, r); info == nil {
+			f.serveError(http.StatusNotFound, "file not found", w, r)
+			return
+		} else if !userbase.CurrentUserCan(r, ef.Action("create", "file-editor")) {
+			f.serveError(http.StatusForbidden, "permission denied", w, r)
+			return
+		} else if err := ef.WriteFile(info, []byte(body)); err != nil {
+			log.ErrorRF(r, "error writing new file: %v - %v", info.FilePath(), err)
+			f.serveError(http.StatusConflict, err.Error(), w, r)
+			return
+		}
+		info = ef.PrepareEditableFile(r, info)
+		f.serveResponse(http.StatusCreated, &Response{File: info}, w, r)
+		return
+	}
+
+	if !userbase.CurrentUserCan(r, ef.Action("edit", "file-editor")) {
+		f.serveError(http.StatusForbidden, "permission denied", w, r)
+		return
+	} else if info.ReadOnly {
+		f.serveError(http.StatusConflict, "file is read-only", w, r)
+		return
+	} else if info.Locked {
+		f.serveError(http.StatusConflict, "file is locked by another user", w, r)
+		return
+	} else if err := ef.LockEditorFile(eid, info.FSID, info.FilePath()); err != nil {
+		log.ErrorRF(r, "error locking file: %v - %v", info.FilePath(), err)
+		f.serveError(http.StatusConflict, err.Error(), w, r)
+		return
+	}
+
+	op, ok := getOperation(ef, bePkgEditor.CommitActionKey)
+	if !ok {
+		f.serveError(http.StatusBadRequest, "unsupported operation", w, r)
+		return
+	}
+	f.serveOperation(ef, op, req, info, w, r)
+}
+
+// HandleOperation performs the requested editor operation upon an existing file
+func (f *CFeature) HandleOperation(w http.ResponseWriter, r *http.Request) {
+	ef := getRequestEditor(r)
+
+	req, ok := f.parseRequest(w, r)
+	if !ok {
+		return
+	}
+
+	var op *feature.EditorOperation
+	if op, ok = getOperation(ef, req.Op); !ok || req.Op == bePkgEditor.DeletePathActionKey {
+		f.serveError(http.StatusBadRequest, "unknown operation", w, r)
+		return
+	}
+
+	info := f.parseFileInfo(ef, r)
+	if info == nil {
+		f.serveError(http.StatusNotFound, "file not found", w, r)
+		return
+	}
+
+	f.serveOperation(ef, op, req, info, w, r)
+}
+
+// HandleDelete removes an existing file, draft or empty directory; use the
+// "draft" query parameter to remove only the draft of the file
+func (f *CFeature) HandleDelete(w http.ResponseWriter, r *http.Request) {
+	ef := getRequestEditor(r)
+
+	if info := f.parseFileInfo(ef, r); info != nil {
+		key := bePkgEditor.DeleteActionKey
+		if r.URL.Query().Has("draft") {
+			if !info.HasDraft {
+				f.serveError(http.StatusNotFound, "draft not found", w, r)
+				return
+			}
+			key = bePkgEditor.DeleteDraftActionKey
+		}
+		if op, ok := getOperation(ef, key); ok {
+			f.serveOperation(ef, op, &Request{}, info, w, r)
+		} else {
+			f.serveError(http.StatusBadRequest, "unsupported operation", w, r)
+		}
+		return
+	}
+
+	fsid, code, dirs, locale := ef.ParseEditorUrlParams(r)
+	if fsid == "" || locale == nil || clPath.TrimSlashes(dirs) == "" {
+		f.serveError(http.StatusNotFound, "path not found", w, r)
+		return
+	}
+	info := bePkgEditor.ParseDirectory(fsid, bePkgEditor.MakeLangCodePath(locale.String(), clPath.TrimSlashes(dirs)))
+	info.Code = code
+	info.Name = filepath.Base(info.Path)
+
+	if op, ok := getOperation(ef, bePkgEditor.DeletePathActionKey); ok {
+		f.serveOperation(ef, op, &Request{}, info, w, r)
+		return
+	}
+	f.serveError(http.StatusBadRequest, "unsupported operation", w, r)
+}
+
+func (f *CFeature) serveOperation(ef feature.EditorFeature, op *feature.EditorOperation, req *Request, info *bePkgEditor.File, w http.ResponseWriter, r *http.Request) {
+	if !userbase.CurrentUserCan(r, op.Action) {
+		f.serveError(http.StatusForbidden, "permission denied", w, r)
+		return
+	}
+
+	notices, failed := f.runOperation(ef, op, makeOperationForm(op.Key, req), info, r)
+	if failed {
+		f.serveResponse(http.StatusUnprocessableEntity, &Response{
+			Error:   "operation failed",
+			Notices: notices,
+		}, w, r)
+		return
+	}
+
+	response := &Response{Notices: notices}
+	if info.File != "" && ef.FileExists(info) {
+		response.File = ef.PrepareEditableFile(r, info)
+	}
+	f.serveResponse(http.StatusOK, response, w, r)
+}
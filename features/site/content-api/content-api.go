@@ -0,0 +1,169 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package content_api
+
+import (
+	"fmt"
+	"net/http"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/pkg/feature"
+	site_including "github.com/go-enjin/be/pkg/feature/site-including"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+	"github.com/go-enjin/be/types/site"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "site-content-api"
+
+// Feature is a JSON REST API exposing the feature.EditorFeature operations
+// of the fs-editor features included with this site feature
+type Feature interface {
+	feature.SiteFeature
+	feature.UserActionsProvider
+}
+
+type MakeFeature interface {
+	feature.SiteMakeFeature[MakeFeature]
+
+	// IncludingEditors specifies the fs-editor features (by tag) to expose,
+	// these features must already be included with the site's fs-editor
+	IncludingEditors(tags ...feature.Tag) MakeFeature
+
+	Make() Feature
+}
+
+type CFeature struct {
+	site.CSiteFeature[MakeFeature]
+
+	editors *site_including.CSiteIncluding[feature.EditorFeature, MakeFeature]
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.SetSiteFeatureKey("content-api")
+	f.SetSiteFeatureIcon("fa-solid fa-code")
+	f.SetSiteFeatureLabel(func(printer *message.Printer) (label string) {
+		label = printer.Sprintf("Content API")
+		return
+	})
+	f.CSiteFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CSiteFeature.Init(this)
+	f.editors = site_including.New[feature.EditorFeature, MakeFeature](this)
+	return
+}
+
+func (f *CFeature) IncludingEditors(tags ...feature.Tag) MakeFeature {
+	f.editors.Including(tags...)
+	return f
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	if err = f.CSiteFeature.Build(b); err != nil {
+		return
+	}
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CSiteFeature.Startup(ctx); err != nil {
+		return
+	}
+	f.editors.StartupSiteIncluding(f.Enjin)
+	if f.editors.Features.Len() == 0 {
+		err = fmt.Errorf("%v requires at least one fs-editor feature, use .IncludingEditors", f.Tag())
+		return
+	}
+	log.DebugF("%v exposing editors: %v", f.Tag(), f.editors.Features.Tags())
+	return
+}
+
+func (f *CFeature) SetupSiteFeature(s feature.Site) (err error) {
+	if err = f.CSiteFeature.SetupSiteFeature(s); err != nil {
+		return
+	}
+	if f.Site().SiteAuth() == nil {
+		err = fmt.Errorf("%q feature requires a site with authentication configured", f.Tag())
+		return
+	}
+	return
+}
+
+func (f *CFeature) UserActions() (actions feature.Actions) {
+	actions = feature.Actions{
+		f.Action("access", "feature"),
+	}
+	return
+}
+
+func (f *CFeature) RouteSiteFeature(r chi.Router) {
+	r.Get("/", f.ServeListEditors)
+	r.Route("/{editor:[a-z0-9][-a-z0-9]+?[a-z0-9]*}", func(r chi.Router) {
+		r.Use(f.editorMiddleware)
+		r.Get("/", f.ServeListFileSystems)
+		r.Get("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}", f.ServeListLocales)
+		r.Get("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/", f.ServeListLocales)
+		r.Get("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/{lang:[a-zA-Z][-a-zA-Z]+?[a-zA-Z]*}", f.ServeReadPath)
+		r.Get("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/{lang:[a-zA-Z][-a-zA-Z]+?[a-zA-Z]*}/*", f.ServeReadPath)
+		r.Put("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/{lang:[a-zA-Z][-a-zA-Z]+?[a-zA-Z]*}/*", f.HandleWriteDraft)
+		r.Post("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/{lang:[a-zA-Z][-a-zA-Z]+?[a-zA-Z]*}/*", f.HandleOperation)
+		r.Delete("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/{lang:[a-zA-Z][-a-zA-Z]+?[a-zA-Z]*}/*", f.HandleDelete)
+	})
+}
+
+func (f *CFeature) findEditor(key string) (ef feature.EditorFeature) {
+	for _, ee := range f.editors.Features {
+		if ee.GetEditorKey() == key {
+			ef = ee
+			return
+		}
+	}
+	return
+}
+
+func (f *CFeature) editorMiddleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if ef := f.findEditor(chi.URLParam(r, "editor")); ef == nil {
+			f.serveError(http.StatusNotFound, "editor not found", w, r)
+		} else if !userbase.CurrentUserCan(r, ef.Action("access", "feature")) {
+			f.serveError(http.StatusNotFound, "editor not found", w, r)
+		} else {
+			next.ServeHTTP(w, setRequestEditor(r, ef))
+		}
+	})
+}
@@ -0,0 +1,378 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csp_reports
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	sha "github.com/go-corelibs/shasum"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net"
+)
+
+var (
+	// DefaultDuplicateWindow is the period in which identical reports from
+	// the same address are counted only once
+	DefaultDuplicateWindow = time.Minute
+	// MaxViolationDocuments limits the number of example document URLs kept
+	// with each aggregated violation
+	MaxViolationDocuments = 10
+	// MaxViolationEntries limits the number of distinct violations kept, the
+	// least recently reported violations are removed first
+	MaxViolationEntries = 500
+	// MaxAddressReports limits the number of reports accepted from the same
+	// address within the DefaultDuplicateWindow
+	MaxAddressReports = 100
+)
+
+const gViolationsKey = "violations"
+
+// Violation is the aggregate of all reports with the same directive and
+// blocked URI
+type Violation struct {
+	Directive   string   `json:"directive"`
+	BlockedURI  string   `json:"blocked-uri"`
+	Disposition string   `json:"disposition,omitempty"`
+	Documents   []string `json:"documents,omitempty"`
+	Count       int      `json:"count"`
+	First       int64    `json:"first"`
+	Last        int64    `json:"last"`
+}
+
+type Violations []*Violation
+
+func (v Violations) Len() int {
+	return len(v)
+}
+
+func (v Violations) Less(i, j int) bool {
+	if v[i].Count == v[j].Count {
+		return v[i].Last > v[j].Last
+	}
+	return v[i].Count > v[j].Count
+}
+
+func (v Violations) Swap(i, j int) {
+	v[i], v[j] = v[j], v[i]
+}
+
+type DirectiveCount struct {
+	Directive string
+	Count     int
+}
+
+type DirectiveCounts []*DirectiveCount
+
+func (d DirectiveCounts) Len() int {
+	return len(d)
+}
+
+func (d DirectiveCounts) Less(i, j int) bool {
+	if d[i].Count == d[j].Count {
+		return d[i].Directive < d[j].Directive
+	}
+	return d[i].Count > d[j].Count
+}
+
+func (d DirectiveCounts) Swap(i, j int) {
+	d[i], d[j] = d[j], d[i]
+}
+
+// violationReport is the normalized form of both application/csp-report and
+// Reporting API csp-violation reports
+type violationReport struct {
+	DocumentURI string
+	Directive   string
+	BlockedURI  string
+	SourceFile  string
+	Disposition string
+	Line        int
+	Column      int
+}
+
+func (v violationReport) fingerprint(address string) (sum string) {
+	sum, _ = sha.BriefSum([]byte(fmt.Sprintf(
+		"%s\n%s\n%s\n%s\n%s\n%d\n%d",
+		address, v.DocumentURI, v.Directive, v.BlockedURI, v.SourceFile, v.Line, v.Column,
+	)))
+	return
+}
+
+type cspReportBody struct {
+	Report struct {
+		DocumentURI        string `json:"document-uri"`
+		ViolatedDirective  string `json:"violated-directive"`
+		EffectiveDirective string `json:"effective-directive"`
+		BlockedURI         string `json:"blocked-uri"`
+		SourceFile         string `json:"source-file"`
+		Disposition        string `json:"disposition"`
+		LineNumber         int    `json:"line-number"`
+		ColumnNumber       int    `json:"column-number"`
+	} `json:"csp-report"`
+}
+
+type reportingApiBody struct {
+	Type string `json:"type"`
+	Body struct {
+		DocumentURL        string `json:"documentURL"`
+		EffectiveDirective string `json:"effectiveDirective"`
+		BlockedURL         string `json:"blockedURL"`
+		SourceFile         string `json:"sourceFile"`
+		Disposition        string `json:"disposition"`
+		LineNumber         int    `json:"lineNumber"`
+		ColumnNumber       int    `json:"columnNumber"`
+	} `json:"body"`
+}
+
+func parseViolationReports(contentType string, body []byte) (reports []violationReport, err error) {
+	mediaType, _, _ := mime.ParseMediaType(contentType)
+
+	switch mediaType {
+
+	case "application/csp-report", "application/json":
+		var report cspReportBody
+		if err = json.Unmarshal(body, &report); err != nil {
+			return
+		}
+		directive := report.Report.EffectiveDirective
+		if directive == "" {
+			directive, _, _ = strings.Cut(report.Report.ViolatedDirective, " ")
+		}
+		reports = append(reports, violationReport{
+			DocumentURI: report.Report.DocumentURI,
+			Directive:   directive,
+			BlockedURI:  report.Report.BlockedURI,
+			SourceFile:  report.Report.SourceFile,
+			Disposition: report.Report.Disposition,
+			Line:        report.Report.LineNumber,
+			Column:      report.Report.ColumnNumber,
+		})
+
+	case "application/reports+json":
+		var batch []reportingApiBody
+		if err = json.Unmarshal(body, &batch); err != nil {
+			return
+		}
+		for _, report := range batch {
+			if report.Type != "csp-violation" {
+				continue
+			}
+			reports = append(reports, violationReport{
+				DocumentURI: report.Body.DocumentURL,
+				Directive:   report.Body.EffectiveDirective,
+				BlockedURI:  report.Body.BlockedURL,
+				SourceFile:  report.Body.SourceFile,
+				Disposition: report.Body.Disposition,
+				Line:        report.Body.LineNumber,
+				Column:      report.Body.ColumnNumber,
+			})
+		}
+
+	default:
+		err = fmt.Errorf("unsupported report content type: %q", contentType)
+	}
+	return
+}
+
+// recentReports tracks report fingerprints for de-duplication and the number
+// of reports from each address for rate-limiting
+type recentReports struct {
+	window    time.Duration
+	seen      map[string]time.Time
+	addresses map[string]*addressReports
+
+	sync.Mutex
+}
+
+type addressReports struct {
+	count int
+	start time.Time
+}
+
+func newRecentReports(window time.Duration) (rr *recentReports) {
+	rr = &recentReports{
+		window:    window,
+		seen:      make(map[string]time.Time),
+		addresses: make(map[string]*addressReports),
+	}
+	return
+}
+
+// limited returns true if the address has sent MaxAddressReports within the
+// window, counting the given number of reports otherwise
+func (rr *recentReports) limited(address string, reports int) (limited bool) {
+	rr.Lock()
+	defer rr.Unlock()
+	now := time.Now()
+	for k, ar := range rr.addresses {
+		if now.Sub(ar.start) >= rr.window {
+			delete(rr.addresses, k)
+		}
+	}
+	ar, present := rr.addresses[address]
+	if !present {
+		ar = &addressReports{start: now}
+		rr.addresses[address] = ar
+	}
+	if limited = ar.count >= MaxAddressReports; !limited {
+		ar.count += reports
+	}
+	return
+}
+
+// duplicate returns true if the fingerprint was seen within the window,
+// recording the fingerprint otherwise
+func (rr *recentReports) duplicate(fingerprint string) (seen bool) {
+	rr.Lock()
+	defer rr.Unlock()
+	now := time.Now()
+	for k, stamp := range rr.seen {
+		if now.Sub(stamp) >= rr.window {
+			delete(rr.seen, k)
+		}
+	}
+	if _, seen = rr.seen[fingerprint]; !seen {
+		rr.seen[fingerprint] = now
+	}
+	return
+}
+
+// ReceiveReport is the csp.ReportReceiverFn for this feature, reports with
+// expired nonces are discarded
+func (f *CFeature) ReceiveReport(valid bool, contentType string, body []byte, r *http.Request) {
+	if !valid {
+		log.DebugRF(r, "discarding csp violation report with invalid or expired nonce")
+		return
+	}
+
+	reports, err := parseViolationReports(contentType, body)
+	if err != nil {
+		log.WarnRF(r, "error parsing csp violation report: %v", err)
+		return
+	}
+
+	address, _ := net.GetIpFromRequest(r)
+	if f.recent.limited(address, len(reports)) {
+		log.DebugRF(r, "discarding csp violation reports from rate-limited address: %v", address)
+		return
+	}
+
+	var accepted []violationReport
+	for _, report := range reports {
+		if report.Directive == "" || f.recent.duplicate(report.fingerprint(address)) {
+			continue
+		}
+		accepted = append(accepted, report)
+	}
+
+	if len(accepted) > 0 {
+		if err = f.recordViolations(accepted); err != nil {
+			log.ErrorRF(r, "error recording csp violations: %v", err)
+		}
+	}
+}
+
+func (f *CFeature) recordViolations(reports []violationReport) (err error) {
+	f.locker.Lock(gViolationsKey)
+	defer f.locker.Unlock(gViolationsKey)
+
+	violations := f.getViolations()
+	now := time.Now().Unix()
+
+	for _, report := range reports {
+		key := report.Directive + " " + report.BlockedURI
+		v, present := violations[key]
+		if !present {
+			v = &Violation{
+				Directive:  report.Directive,
+				BlockedURI: report.BlockedURI,
+				First:      now,
+			}
+			violations[key] = v
+			pruneViolations(violations, key)
+		}
+		v.Count += 1
+		v.Last = now
+		if report.Disposition != "" {
+			v.Disposition = report.Disposition
+		}
+		if report.DocumentURI != "" && len(v.Documents) < MaxViolationDocuments {
+			var known bool
+			for _, document := range v.Documents {
+				if known = document == report.DocumentURI; known {
+					break
+				}
+			}
+			if !known {
+				v.Documents = append(v.Documents, report.DocumentURI)
+			}
+		}
+	}
+
+	var data []byte
+	if data, err = json.Marshal(violations); err == nil {
+		err = f.bucket.Set(gViolationsKey, data)
+	}
+	return
+}
+
+// pruneViolations removes the least recently reported violations, except for
+// the keep key, until there are no more than MaxViolationEntries
+func pruneViolations(violations map[string]*Violation, keep string) {
+	for len(violations) > MaxViolationEntries {
+		var oldest string
+		for key, v := range violations {
+			if key != keep && (oldest == "" || v.Last < violations[oldest].Last) {
+				oldest = key
+			}
+		}
+		delete(violations, oldest)
+	}
+}
+
+func (f *CFeature) getViolations() (violations map[string]*Violation) {
+	violations = make(map[string]*Violation)
+	if data, err := f.bucket.Get(gViolationsKey); err == nil && len(data) > 0 {
+		if err = json.Unmarshal(data, &violations); err != nil {
+			log.ErrorF("error decoding csp violations: %v", err)
+			violations = make(map[string]*Violation)
+		}
+	}
+	return
+}
+
+// ListViolations returns all aggregated violations, most frequent first
+func (f *CFeature) ListViolations() (list Violations) {
+	for _, v := range f.getViolations() {
+		list = append(list, v)
+	}
+	sort.Sort(list)
+	return
+}
+
+// ClearViolations removes all aggregated violations
+func (f *CFeature) ClearViolations() (err error) {
+	f.locker.Lock(gViolationsKey)
+	defer f.locker.Unlock(gViolationsKey)
+	err = f.bucket.Delete(gViolationsKey)
+	return
+}
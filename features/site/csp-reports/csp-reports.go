@@ -0,0 +1,209 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package csp_reports
+
+import (
+	"net/http"
+	"sort"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-corelibs/x-text/message"
+	beContext "github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	uses_kvc "github.com/go-enjin/be/pkg/feature/uses-kvc"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/menu"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+	"github.com/go-enjin/be/types/site"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "site-csp-reports"
+
+const (
+	ReportsNonceKey  = "site-csp-reports--form"
+	ReportsNonceName = "site-csp-reports--nonce"
+)
+
+type Feature interface {
+	feature.SiteFeature
+}
+
+type MakeFeature interface {
+	feature.SiteMakeFeature[MakeFeature]
+	uses_kvc.MakeFeature[MakeFeature]
+
+	Make() Feature
+}
+
+type CFeature struct {
+	site.CSiteFeature[MakeFeature]
+	uses_kvc.CUsesKVC[MakeFeature]
+
+	bucket feature.KeyValueStore
+	locker feature.SyncLocker
+	recent *recentReports
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.SetSiteFeatureKey("csp-reports")
+	f.SetSiteFeatureIcon("fa-solid fa-shield-halved")
+	f.SetSiteFeatureLabel(func(printer *message.Printer) (label string) {
+		label = printer.Sprintf("CSP Reports")
+		return
+	})
+	f.CSiteFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CSiteFeature.Init(this)
+	f.CUsesKVC.InitUsesKVC(f)
+	f.recent = newRecentReports(DefaultDuplicateWindow)
+	return
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	if err = f.CSiteFeature.Build(b); err != nil {
+		return
+	} else if err = f.BuildUsesKVC(); err != nil {
+		return
+	}
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CSiteFeature.Startup(ctx); err != nil {
+		return
+	} else if err = f.CUsesKVC.StartupUsesKVC(f.Enjin.Features()); err != nil {
+		return
+	}
+
+	f.bucket = f.KVC().MustBucket("csp-violations")
+	lockerBucket := f.KVC().MustBucket("csp-violations-locker")
+	f.locker = f.Enjin.NewSyncLocker(f.Tag(), "csp-violations-locker", lockerBucket)
+
+	f.Enjin.ContentSecurityPolicy().AddReportReceiver(f.ReceiveReport)
+	return
+}
+
+func (f *CFeature) UserActions() (actions feature.Actions) {
+	actions = feature.Actions{
+		f.Action("access", "feature"),
+		f.Action("delete", "reports"),
+	}
+	return
+}
+
+func (f *CFeature) SiteFeatureMenu(r *http.Request) (m menu.Menu) {
+	info := f.SiteFeatureInfo(r)
+	m = menu.Menu{{
+		Text: info.Label,
+		Href: f.SiteFeaturePath(),
+		Icon: info.Icon,
+	}}
+	return
+}
+
+func (f *CFeature) RouteSiteFeature(r chi.Router) {
+	r.Post("/", f.HandleReports)
+	r.Get("/", f.RenderReports)
+}
+
+func (f *CFeature) HandleReports(w http.ResponseWriter, r *http.Request) {
+	printer := message.GetPrinter(r)
+	eid := userbase.GetCurrentEID(r)
+
+	if nonce := request.SafeQueryFormValue(r, ReportsNonceName); nonce == "" || !f.Enjin.VerifyNonce(ReportsNonceKey, nonce) {
+		r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		f.RenderReports(w, r)
+		return
+	} else if !userbase.CurrentUserCan(r, f.Action("delete", "reports")) {
+		r = feature.AddErrorNotice(r, true, berrs.PermissionDeniedError(printer))
+		f.RenderReports(w, r)
+		return
+	}
+
+	switch request.SafeQueryFormValue(r, "submit") {
+	case "clear":
+		if err := f.ClearViolations(); err != nil {
+			log.ErrorRF(r, "error clearing csp violations: %v", err)
+			r = feature.AddErrorNotice(r, true, berrs.UnexpectedError(printer))
+			f.RenderReports(w, r)
+			return
+		}
+		f.Site().PushInfoNotice(eid, true, printer.Sprintf("All CSP violation reports have been cleared."))
+	}
+
+	f.Enjin.ServeRedirect(f.SiteFeaturePath(), w, r)
+}
+
+func (f *CFeature) RenderReports(w http.ResponseWriter, r *http.Request) {
+	t := f.SiteFeatureTheme()
+	printer := message.GetPrinter(r)
+
+	violations := f.ListViolations()
+
+	var total int
+	directives := make(map[string]int)
+	for _, v := range violations {
+		total += v.Count
+		directives[v.Directive] += v.Count
+	}
+
+	var counts DirectiveCounts
+	for directive, count := range directives {
+		counts = append(counts, &DirectiveCount{Directive: directive, Count: count})
+	}
+	sort.Sort(counts)
+
+	ctx := beContext.Context{
+		"Title":      f.SiteFeatureLabel(printer),
+		"FormAction": f.SiteFeaturePath(),
+		"Nonces": feature.Nonces{
+			{Name: ReportsNonceName, Key: ReportsNonceKey},
+		},
+		"Violations":      violations,
+		"DirectiveCounts": counts,
+		"TotalReports":    total,
+		"CanDelete":       userbase.CurrentUserCan(r, f.Action("delete", "reports")),
+	}
+
+	if err := f.Site().PrepareAndServePage("site", "csp-reports", f.SiteFeaturePath(), t, w, r, ctx); err != nil {
+		log.ErrorRF(r, "error preparing %v csp-reports page: %v", f.Tag(), err)
+		f.Enjin.ServeInternalServerError(w, r)
+		return
+	}
+}
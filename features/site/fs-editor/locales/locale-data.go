@@ -20,6 +20,8 @@ import (
 
 	"github.com/go-corelibs/lang"
 	"github.com/go-corelibs/x-text/language"
+
+	"github.com/go-enjin/be/pkg/lang/icu"
 )
 
 type LocaleData struct {
@@ -39,6 +41,10 @@ func ConvertToPlaceholders(translation string, placeholders []*lang.Placeholder)
 
 func ConvertFromPlaceholders(translation string, placeholders []*lang.Placeholder) (modified string) {
 	modified = translation
+	if icu.IsMessageFormat(translation) {
+		// ICU MessageFormat arguments are resolved by name when loaded
+		return
+	}
 	for _, placeholder := range placeholders {
 		modified = strings.ReplaceAll(modified, "{"+placeholder.ID+"}", placeholder.String)
 	}
@@ -148,6 +154,20 @@ func (l *LocaleData) MakeGoTextData() (lookup map[language.Tag]*lang.GoText) {
 	return
 }
 
+// AddMessage adds the message for the given language, if the message is not
+// already present
+func (l *LocaleData) AddMessage(tag language.Tag, msg *LocaleMessage) (added bool) {
+	if _, present := l.Data[msg.Shasum]; !present {
+		l.Data[msg.Shasum] = make(map[language.Tag]*LocaleMessage)
+		l.Order = append(l.Order, msg.Shasum)
+	}
+	if _, present := l.Data[msg.Shasum][tag]; !present {
+		l.Data[msg.Shasum][tag] = msg.Copy()
+		added = true
+	}
+	return
+}
+
 func (l *LocaleData) AddMissingTranslations(defTag language.Tag, locales []language.Tag) {
 	for _, shasum := range l.Order {
 		if txs, ok := l.Data[shasum]; ok {
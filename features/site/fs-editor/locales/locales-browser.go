@@ -51,6 +51,7 @@ func (f *CFeature) RenderFileBrowser(w http.ResponseWriter, r *http.Request) {
 	ctx.SetSpecific("EditorEID", eid)
 	ctx.SetSpecific("EditFSID", fsid)
 	ctx.SetSpecific("EditLang", code)
+	ctx.SetSpecific("CoveragePath", f.GetCoveragePath())
 
 	info := &editor.File{
 		FSID:     fsid,
@@ -0,0 +1,155 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package locales
+
+import (
+	"net/http"
+
+	"github.com/go-corelibs/x-text/language"
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/pkg/editor"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/lang/coverage"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+const (
+	CoverageNonceKey  = "fs-editor-locales--coverage-form"
+	CoverageNonceName = "fs-editor-locales--coverage-nonce"
+)
+
+func (f *CFeature) GetCoveragePath() (path string) {
+	path = f.GetEditorPath() + "/coverage"
+	return
+}
+
+// MakeCoverageReport extracts the message keys used within the theme and
+// content filesystems and compares them against the loaded translations
+func (f *CFeature) MakeCoverageReport() (keys coverage.Keys, report *coverage.Report) {
+	keys = coverage.Keys{}
+	coverage.ExtractEnjin(f.Enjin, keys)
+	report = coverage.Build(f.Enjin, keys)
+	return
+}
+
+func (f *CFeature) RenderCoverage(w http.ResponseWriter, r *http.Request) {
+
+	if !f.Enjin.ValidateUserRequest(f.ViewBrowserAction, w, r) {
+		log.WarnRF(r, "user denied: %v", f.ViewBrowserAction)
+		f.Enjin.ServeNotFound(w, r)
+		return
+	}
+
+	pg, ctx, err := f.SelfEditor().PrepareEditPage("locale-coverage", f.EditorType, r)
+	if err != nil {
+		log.ErrorRF(r, "error preparing %v coverage page: %v", f.Tag(), err)
+		f.Enjin.ServeNotFound(w, r)
+		return
+	}
+
+	eid := userbase.GetCurrentEID(r)
+	_, report := f.MakeCoverageReport()
+
+	ctx.SetSpecific("EditorEID", eid)
+	ctx.SetSpecific("CoverageReport", report)
+	ctx.SetSpecific("FormAction", f.GetCoveragePath())
+	ctx.SetSpecific("Nonces", feature.Nonces{
+		{Name: CoverageNonceName, Key: CoverageNonceKey},
+	})
+	ctx.SetSpecific("CanWriteSkeleton", userbase.CurrentUserCan(r, f.UpdateFileAction))
+
+	var files editor.Files
+	for _, efs := range f.EditingFileSystems {
+		// skeleton messages are written per locale mount point
+		files = append(files, f.ListLocaleFileSystemLocales(r, efs.Tag().String())...)
+	}
+	ctx.SetSpecific("EditFiles", files)
+
+	printer := message.GetPrinter(r)
+	r = feature.AddUserNotices(r, f.Editor.Site().PullNotices(eid)...)
+	pg.SetTitle(printer.Sprintf("Translation Coverage"))
+	f.SelfEditor().ServePreparedEditPage(pg, ctx, w, r)
+}
+
+// ReceiveCoverage adds skeleton messages for all missing keys to the draft
+// locales of the requested filesystem, publishing the draft writes the
+// out.gotext.json files
+func (f *CFeature) ReceiveCoverage(w http.ResponseWriter, r *http.Request) {
+	printer := message.GetPrinter(r)
+	eid := userbase.GetCurrentEID(r)
+
+	redirect := f.GetCoveragePath()
+	defer func() {
+		f.Enjin.ServeRedirect(redirect, w, r)
+	}()
+
+	if nonce := request.SafeQueryFormValue(r, CoverageNonceName); nonce == "" || !f.Enjin.VerifyNonce(CoverageNonceKey, nonce) {
+		f.Editor.Site().PushErrorNotice(eid, true, berrs.FormExpiredError(printer))
+		return
+	} else if !userbase.CurrentUserCan(r, f.UpdateFileAction) {
+		f.Editor.Site().PushErrorNotice(eid, true, berrs.PermissionDeniedError(printer))
+		return
+	} else if request.SafeQueryFormValue(r, "submit") != "write-skeleton" {
+		f.Editor.Site().PushErrorNotice(eid, true, printer.Sprintf(`inconsistent operation requested`))
+		return
+	}
+
+	fsid := request.SafeQueryFormValue(r, "fsid")
+	code := request.SafeQueryFormValue(r, "code")
+	mountPoints := f.FindMountPoints(fsid, code)
+	if !mountPoints.HasRWFS() {
+		f.Editor.Site().PushErrorNotice(eid, true, printer.Sprintf(`locales are read-only: %[1]s`, fsid+"/"+code))
+		return
+	} else if locked, lockedBy, err := f.IsLocaleLocked(fsid, code); err == nil && locked && lockedBy != eid {
+		f.Editor.Site().PushErrorNotice(eid, true, printer.Sprintf(`locales are locked for editing by another user: %[1]s`, fsid+"/"+code))
+		return
+	}
+
+	ld, err := f.ReadDraftLocales(fsid, code, mountPoints, true)
+	if err != nil {
+		f.Editor.Site().PushErrorNotice(eid, true, printer.Sprintf("error reading locales data: %[1]s", err.Error()))
+		return
+	}
+
+	var added int
+	keys, report := f.MakeCoverageReport()
+	for _, lc := range report.Locales {
+		tag, ee := language.Parse(lc.Locale)
+		if ee != nil {
+			continue
+		}
+		for _, key := range lc.Missing {
+			if ld.AddMessage(tag, ParseNewMessage(key, keys.Comment(key))) {
+				added += 1
+			}
+		}
+	}
+
+	if added == 0 {
+		f.Editor.Site().PushInfoNotice(eid, true, printer.Sprintf(`no missing messages to add`))
+		return
+	}
+
+	if err = f.WriteDraftLocales(ld, mountPoints); err != nil {
+		f.Editor.Site().PushErrorNotice(eid, true, printer.Sprintf(`error writing draft changes: %[1]s`, err.Error()))
+		return
+	}
+
+	f.Editor.Site().PushInfoNotice(eid, true, printer.Sprintf(`%[1]d skeleton messages added to the draft locales, publish the draft to update the out.gotext.json files`, added))
+	redirect = f.GetEditorPath() + "/" + fsid + "/" + code
+}
@@ -20,6 +20,7 @@ import (
 
 	"github.com/go-corelibs/lang"
 	"github.com/go-corelibs/x-text/language"
+	"github.com/go-enjin/be/pkg/lang/icu"
 	"github.com/go-enjin/be/pkg/log"
 )
 
@@ -35,6 +36,12 @@ func (f *CFeature) performDraftChanges(r *http.Request, translations map[string]
 			for shasum, vv := range messages {
 				switch t := vv.(type) {
 				case string:
+					if icu.IsMessageFormat(t) {
+						if _, ee := icu.Parse(t); ee != nil {
+							err = fmt.Errorf(`invalid ICU MessageFormat translation: %v`, ee)
+							return
+						}
+					}
 					if err = ld.SetStringTranslation(tag, shasum, t); err != nil {
 						log.WarnRF(r, "error setting string translation: %v - %v - %v", code, shasum, err)
 						continue
@@ -278,16 +278,16 @@ func (f *CFeature) MakeTable(ld *LocaleData) (table []Row, err error) {
 			row := make(Row, 0)
 			var defMsg *LocaleMessage
 			if defMsg, ok = msgs[defTag]; ok {
-				row = append(row, &Cell{Locale: defTag, Src: defMsg, Msg: defMsg})
+				row = append(row, NewCell(defTag, defMsg, defMsg))
 			} else {
-				row = append(row, &Cell{Locale: defTag, Src: nil, Msg: nil})
+				row = append(row, NewCell(defTag, nil, nil))
 			}
 			for _, tag := range tags {
 				var msg *LocaleMessage
 				if msg, ok = msgs[tag]; ok {
-					row = append(row, &Cell{Locale: tag, Src: defMsg, Msg: msg})
+					row = append(row, NewCell(tag, defMsg, msg))
 				} else {
-					row = append(row, &Cell{Locale: tag, Src: defMsg, Msg: nil})
+					row = append(row, NewCell(tag, defMsg, nil))
 				}
 			}
 			table = append(table, row)
@@ -27,6 +27,7 @@ import (
 	"github.com/go-enjin/be/pkg/context"
 	"github.com/go-enjin/be/pkg/editor"
 	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/lang/icu"
 	"github.com/go-enjin/be/pkg/log"
 )
 
@@ -361,6 +362,9 @@ func (f *CFeature) OpChangeHandler(r *http.Request, pg feature.Page, ctx context
 		} else if key = cllang.ParsePluralCaseKey(key); key == "" {
 			f.Editor.Site().PushErrorNotice(eid, true, printer.Sprintf(`invalid plural translation case key`))
 			return
+		} else if icu.IsFormName(key) && !slices.Within(key, icu.CardinalForms(tag)) {
+			f.Editor.Site().PushErrorNotice(eid, true, printer.Sprintf(`plural category "%[1]s" is not used by %[2]s`, key, tag.String()))
+			return
 		}
 
 		if msg, ok := ld.Data[shasum][tag]; ok {
@@ -434,13 +438,16 @@ func (f *CFeature) OpChangeHandler(r *http.Request, pg feature.Page, ctx context
 			return
 		}
 
-		for _, msg := range ld.Data[shasum] {
+		for tag, msg := range ld.Data[shasum] {
+			// start with a case for each of the CLDR plural categories used
+			cases := make(map[string]string)
+			for _, form := range icu.CardinalForms(tag) {
+				cases[form] = msg.Translation.String
+			}
 			msg.Translation.Select = &Select{
 				Arg:     defArg,
 				Feature: "plural",
-				Cases: map[string]string{
-					"other": msg.Translation.String,
-				},
+				Cases:   cases,
 			}
 			msg.Translation.String = ""
 		}
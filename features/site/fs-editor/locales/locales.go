@@ -163,6 +163,9 @@ func (f *CFeature) SetupEditorRoute(r chi.Router) {
 	r.Use(f.Enjin.GetPanicHandler().PanicHandler)
 	//r.Use(argv.Middleware)
 
+	r.Post("/coverage", f.ReceiveCoverage)
+	r.Get("/coverage", f.RenderCoverage)
+
 	r.Post("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/{code:[a-zA-Z][-a-zA-Z]+?[a-zA-Z]*}/*", f.SelfEditor().ReceiveFileEditorChanges)
 	r.Post("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/{code:[a-zA-Z][-a-zA-Z]+?[a-zA-Z]*}", f.SelfEditor().ReceiveFileEditorChanges)
 	r.Post("/{fsid:[a-z0-9][-a-z0-9]+?[a-z0-9]*}/", f.SelfEditor().ReceiveFileEditorChanges)
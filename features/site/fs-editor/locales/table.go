@@ -16,6 +16,8 @@ package locales
 
 import (
 	"github.com/go-corelibs/x-text/language"
+
+	"github.com/go-enjin/be/pkg/lang/icu"
 )
 
 // TODO: expand upon Table idea and construct a generic type
@@ -24,6 +26,39 @@ type Cell struct {
 	Locale language.Tag
 	Src    *LocaleMessage
 	Msg    *LocaleMessage
+
+	// PluralForms are the CLDR plural categories used by the Locale
+	PluralForms []string
+	// MissingForms are the PluralForms not present in the Msg plural cases
+	MissingForms []string
+	// MessageFormat is true when the Msg translation is an ICU MessageFormat
+	// string, with MessageFormatError describing any syntax errors
+	MessageFormat      bool
+	MessageFormatError string
+}
+
+func NewCell(locale language.Tag, src, msg *LocaleMessage) (cell *Cell) {
+	cell = &Cell{
+		Locale:      locale,
+		Src:         src,
+		Msg:         msg,
+		PluralForms: icu.CardinalForms(locale),
+	}
+	if msg == nil || msg.Translation == nil {
+		return
+	}
+	if msg.Translation.Select != nil {
+		for _, form := range cell.PluralForms {
+			if _, present := msg.Translation.Select.Cases[form]; !present {
+				cell.MissingForms = append(cell.MissingForms, form)
+			}
+		}
+	} else if cell.MessageFormat = icu.IsMessageFormat(msg.Translation.String); cell.MessageFormat {
+		if _, err := icu.Parse(msg.Translation.String); err != nil {
+			cell.MessageFormatError = err.Error()
+		}
+	}
+	return
 }
 
 type Row []*Cell
@@ -159,7 +159,13 @@ func (f *CFeature) UpdateFileInfoForEditing(info *editor.File, r *http.Request)
 func (f *CFeature) GetTranslatedLocales(info *editor.File) (translations map[language.Tag]string) {
 	translations = map[language.Tag]string{}
 	if url := info.Url(); url != "" {
-		txs := f.Enjin.FindTranslations(url)
+		var txs feature.Pages
+		for _, p := range f.Enjin.FindTranslations(url) {
+			if !p.Context().Bool("LanguageFallback", false) {
+				// fallback language pages are not translations
+				txs = append(txs, p)
+			}
+		}
 		if dtag := f.Enjin.SiteDefaultLanguage(); dtag == *info.Locale {
 			// is default locale, find translations
 			for _, p := range txs {
@@ -185,7 +191,13 @@ func (f *CFeature) GetTranslatedLocales(info *editor.File) (translations map[lan
 func (f *CFeature) GetUntranslatedLocales(info *editor.File) (locales []language.Tag) {
 
 	if url := info.Url(); url != "" {
-		txs := f.Enjin.FindTranslationUrls(url)
+		txs := make(map[language.Tag]struct{})
+		for _, p := range f.Enjin.FindTranslations(url) {
+			if !p.Context().Bool("LanguageFallback", false) {
+				// fallback language pages are not translations
+				txs[p.LanguageTag()] = struct{}{}
+			}
+		}
 		for _, locale := range f.Enjin.SiteLocales() {
 			if _, present := txs[locale]; !present {
 				locales = append(locales, locale)
@@ -0,0 +1,227 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package link_checker
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-corelibs/x-text/message"
+	beContext "github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/linkcheck"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/menu"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+	"github.com/go-enjin/be/types/site"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "site-link-checker"
+
+const (
+	CheckerNonceKey  = "site-link-checker--form"
+	CheckerNonceName = "site-link-checker--nonce"
+)
+
+type Feature interface {
+	feature.SiteFeature
+}
+
+type MakeFeature interface {
+	feature.SiteMakeFeature[MakeFeature]
+
+	Make() Feature
+}
+
+// CFeature is a site dashboard panel for running the linkcheck report against
+// the live enjin and reviewing the most recent results
+type CFeature struct {
+	site.CSiteFeature[MakeFeature]
+
+	report  *linkcheck.Report
+	running bool
+	mutex   sync.RWMutex
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.SetSiteFeatureKey("link-checker")
+	f.SetSiteFeatureIcon("fa-solid fa-link-slash")
+	f.SetSiteFeatureLabel(func(printer *message.Printer) (label string) {
+		label = printer.Sprintf("Link Checker")
+		return
+	})
+	f.CSiteFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CSiteFeature.Init(this)
+	return
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	if err = f.CSiteFeature.Build(b); err != nil {
+		return
+	}
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CSiteFeature.Startup(ctx); err != nil {
+		return
+	}
+	return
+}
+
+func (f *CFeature) UserActions() (actions feature.Actions) {
+	actions = feature.Actions{
+		f.Action("access", "feature"),
+		f.Action("run", "report"),
+	}
+	return
+}
+
+func (f *CFeature) SiteFeatureMenu(r *http.Request) (m menu.Menu) {
+	info := f.SiteFeatureInfo(r)
+	m = menu.Menu{{
+		Text: info.Label,
+		Href: f.SiteFeaturePath(),
+		Icon: info.Icon,
+	}}
+	return
+}
+
+func (f *CFeature) RouteSiteFeature(r chi.Router) {
+	r.Post("/", f.HandleReport)
+	r.Get("/", f.RenderReport)
+}
+
+// Report returns the most recent report and true if a check is in progress
+func (f *CFeature) Report() (report *linkcheck.Report, running bool) {
+	f.mutex.RLock()
+	defer f.mutex.RUnlock()
+	report, running = f.report, f.running
+	return
+}
+
+// RunReport checks all pages in the background, using the given request to
+// determine the site URL; returns false if a check is already running
+func (f *CFeature) RunReport(r *http.Request) (started bool) {
+	f.mutex.Lock()
+	defer f.mutex.Unlock()
+	if f.running {
+		return
+	}
+	f.running = true
+
+	siteUrl := &url.URL{Scheme: "http", Host: r.Host}
+	if r.TLS != nil {
+		siteUrl.Scheme = "https"
+	}
+
+	go func() {
+		start := time.Now()
+		report := linkcheck.New(f.Enjin, f.Enjin.Router(), siteUrl).Run()
+		log.InfoF("%v checked %d links on %d pages in %v", f.Tag(), report.Links, report.Pages, time.Since(start))
+		f.mutex.Lock()
+		defer f.mutex.Unlock()
+		f.report = report
+		f.running = false
+	}()
+	return true
+}
+
+func (f *CFeature) HandleReport(w http.ResponseWriter, r *http.Request) {
+	printer := message.GetPrinter(r)
+	eid := userbase.GetCurrentEID(r)
+
+	if nonce := request.SafeQueryFormValue(r, CheckerNonceName); nonce == "" || !f.Enjin.VerifyNonce(CheckerNonceKey, nonce) {
+		r = feature.AddErrorNotice(r, true, berrs.FormExpiredError(printer))
+		f.RenderReport(w, r)
+		return
+	} else if !userbase.CurrentUserCan(r, f.Action("run", "report")) {
+		r = feature.AddErrorNotice(r, true, berrs.PermissionDeniedError(printer))
+		f.RenderReport(w, r)
+		return
+	}
+
+	switch request.SafeQueryFormValue(r, "submit") {
+	case "run":
+		if f.RunReport(r) {
+			f.Site().PushInfoNotice(eid, true, printer.Sprintf("The link checker has started, reload this page to see the results."))
+		} else {
+			f.Site().PushInfoNotice(eid, true, printer.Sprintf("The link checker is already running."))
+		}
+	}
+
+	f.Enjin.ServeRedirect(f.SiteFeaturePath(), w, r)
+}
+
+func (f *CFeature) RenderReport(w http.ResponseWriter, r *http.Request) {
+	t := f.SiteFeatureTheme()
+	printer := message.GetPrinter(r)
+
+	report, running := f.Report()
+
+	ctx := beContext.Context{
+		"Title":      f.SiteFeatureLabel(printer),
+		"FormAction": f.SiteFeaturePath(),
+		"Nonces": feature.Nonces{
+			{Name: CheckerNonceName, Key: CheckerNonceKey},
+		},
+		"Report":  report,
+		"Running": running,
+		"CanRun":  userbase.CurrentUserCan(r, f.Action("run", "report")),
+	}
+
+	if report != nil {
+		ctx.SetSpecific("IssueCounts", map[string]int{
+			string(linkcheck.Broken):             report.Count(linkcheck.Broken),
+			string(linkcheck.MissingTranslation): report.Count(linkcheck.MissingTranslation),
+			string(linkcheck.Redirect):           report.Count(linkcheck.Redirect),
+			string(linkcheck.Orphan):             report.Count(linkcheck.Orphan),
+		})
+	}
+
+	if err := f.Site().PrepareAndServePage("site", "link-checker", f.SiteFeaturePath(), t, w, r, ctx); err != nil {
+		log.ErrorRF(r, "error preparing %v link-checker page: %v", f.Tag(), err)
+		f.Enjin.ServeInternalServerError(w, r)
+		return
+	}
+}
@@ -27,6 +27,7 @@ import (
 	beContext "github.com/go-enjin/be/pkg/context"
 	berrs "github.com/go-enjin/be/pkg/errors"
 	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/lang"
 	"github.com/go-enjin/be/pkg/log"
 	"github.com/go-enjin/be/pkg/menu"
 	"github.com/go-enjin/be/pkg/request"
@@ -312,6 +313,11 @@ func (f *CFeature) RouteSiteFeature(r chi.Router) {
 
 func (f *CFeature) ModifyUserRequest(au feature.User, r *http.Request) (modified *http.Request) {
 	if f.siteLocaleEnabled {
+		if negotiated := lang.GetNegotiation(r); negotiated != nil && negotiated.Explicit {
+			// the user explicitly chose a language, remember it in their settings
+			modified = f.saveUserLocale(au, negotiated.Tag, r)
+			return
+		}
 		if locale, ok := au.GetSetting("locale").(string); ok && locale != "" {
 			if parsed, err := language.Parse(locale); err == nil {
 				locales := f.Enjin.SiteLocales()
@@ -329,6 +335,26 @@ func (f *CFeature) ModifyUserRequest(au feature.User, r *http.Request) (modified
 	return
 }
 
+// saveUserLocale updates the user's locale setting with the given language and
+// returns the request modified to use it
+func (f *CFeature) saveUserLocale(au feature.User, tag language.Tag, r *http.Request) (modified *http.Request) {
+	if !f.Enjin.SiteLocales().Has(tag) {
+		return
+	}
+	if locale, ok := au.GetSetting("locale").(string); !ok || locale != tag.String() {
+		eid := au.GetEID()
+		su := f.Site().SiteUsers()
+		su.LockUser(r, eid)
+		if err := su.SetUserSetting(r, eid, "locale", tag.String()); err != nil {
+			log.ErrorRF(r, "error setting user locale setting: %v - %v", eid, err)
+		}
+		su.UnlockUser(r, eid)
+	}
+	tag, printer := f.Enjin.MakeLanguagePrinter(tag.String())
+	modified = message.SetPrinter(message.SetTag(r, tag), printer)
+	return
+}
+
 func (f *CFeature) ServeProfilePage(w http.ResponseWriter, r *http.Request) {
 
 	var eid string
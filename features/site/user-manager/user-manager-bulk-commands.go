@@ -0,0 +1,223 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user_manager
+
+import (
+	"crypto/tls"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) buildBulkCommands(b feature.Buildable) {
+	b.AddCommands(
+		&cli.Command{
+			Name:      f.KebabTag + "-import-users",
+			Usage:     "create site users from a CSV or JSON file",
+			UsageText: globals.BinName + " " + f.KebabTag + "-import-users [options] <file>",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "csv or json, detected from the file extension by default",
+				},
+				&cli.BoolFlag{
+					Name:  "dry-run",
+					Usage: "validate all rows without creating any users",
+				},
+				&cli.BoolFlag{
+					Name:  "welcome",
+					Usage: "send a welcome email to each new user",
+				},
+				&cli.StringFlag{
+					Name:  "site-url",
+					Usage: "the site URL to use within welcome emails",
+					Value: "http://localhost",
+				},
+			},
+			Action: f.importUsersAction,
+		},
+		&cli.Command{
+			Name:      f.KebabTag + "-export-users",
+			Usage:     "write all site users, groups and actions to a CSV or JSON file",
+			UsageText: globals.BinName + " " + f.KebabTag + "-export-users [options] [file]",
+			Flags: []cli.Flag{
+				&cli.StringFlag{
+					Name:  "format",
+					Usage: "csv or json, detected from the file extension by default",
+				},
+			},
+			Action: f.exportUsersAction,
+		},
+	)
+}
+
+func (f *CFeature) importUsersAction(ctx *cli.Context) (err error) {
+	argv := ctx.Args().Slice()
+	if len(argv) != 1 {
+		cli.ShowCommandHelpAndExit(ctx, f.KebabTag+"-import-users", 1)
+	}
+
+	var format string
+	if format, err = bulkFormat(ctx.String("format"), argv[0], ""); err != nil {
+		return
+	}
+
+	var r *http.Request
+	if r, err = f.startupCommand(ctx, ctx.String("site-url")); err != nil {
+		return
+	}
+
+	var bulk *BulkUsers
+	if argv[0] == "-" {
+		bulk, err = ParseBulkUsers(format, os.Stdin)
+	} else {
+		var fh *os.File
+		if fh, err = os.Open(argv[0]); err != nil {
+			return
+		}
+		defer func() { _ = fh.Close() }()
+		bulk, err = ParseBulkUsers(format, fh)
+	}
+	if err != nil {
+		return
+	}
+
+	var results []*BulkResult
+	if results, err = f.ImportUsers(r, bulk, ctx.Bool("dry-run"), ctx.Bool("welcome")); err != nil {
+		return
+	}
+	// wait for the welcome emails before the command exits
+	f.welcoming.Wait()
+
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status] += 1
+		fmt.Println(result.String())
+	}
+	fmt.Printf("%d rows: %d valid, %d created, %d exist, %d errors\n", len(results), counts[BulkStatusValid], counts[BulkStatusCreated], counts[BulkStatusExists], counts[BulkStatusError])
+	if counts[BulkStatusError] > 0 {
+		err = fmt.Errorf("%d rows failed to import", counts[BulkStatusError])
+	}
+	return
+}
+
+func (f *CFeature) exportUsersAction(ctx *cli.Context) (err error) {
+	argv := ctx.Args().Slice()
+	if len(argv) > 1 {
+		cli.ShowCommandHelpAndExit(ctx, f.KebabTag+"-export-users", 1)
+	}
+	var output string
+	if len(argv) == 1 {
+		output = argv[0]
+	}
+
+	var format string
+	if format, err = bulkFormat(ctx.String("format"), output, BulkFormatJSON); err != nil {
+		return
+	}
+
+	var r *http.Request
+	if r, err = f.startupCommand(ctx, ""); err != nil {
+		return
+	}
+
+	var bulk *BulkUsers
+	if bulk, err = f.ExportUsers(r); err != nil {
+		return
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" && output != "-" {
+		var fh *os.File
+		if fh, err = os.Create(output); err != nil {
+			return
+		}
+		defer func() { _ = fh.Close() }()
+		w = fh
+	}
+	err = WriteBulkUsers(format, bulk, w)
+	return
+}
+
+// startupCommand starts the enjin features without serving any requests and
+// returns a request with all site user permissions
+func (f *CFeature) startupCommand(ctx *cli.Context, siteUrl string) (r *http.Request, err error) {
+	if root, ok := f.Enjin.(feature.RootInternals); !ok {
+		err = fmt.Errorf("%T does not implement feature.RootInternals", f.Enjin)
+		return
+	} else if err = root.SetupRootEnjin(ctx); err != nil {
+		return
+	}
+	for _, ef := range f.Enjin.Features().List() {
+		if err = ef.Startup(ctx); err != nil {
+			err = fmt.Errorf("error starting up %q feature: %v", ef.Tag(), err)
+			return
+		}
+	}
+	for _, ef := range feature.FilterTyped[feature.PostStartupFeature](f.Enjin.Features().List()) {
+		if err = ef.PostStartup(ctx); err != nil {
+			err = fmt.Errorf("error running post-startup for %q feature: %v", ef.Tag(), err)
+			return
+		}
+	}
+
+	if f.Site() == nil || f.Site().SiteUsers() == nil {
+		err = fmt.Errorf("%q feature requires a site with .SiteUsers configured", f.Tag())
+		return
+	}
+
+	if siteUrl == "" {
+		siteUrl = "http://localhost"
+	}
+	if r, err = http.NewRequest(http.MethodGet, siteUrl, nil); err != nil {
+		return
+	} else if r.URL.Scheme == "https" {
+		// request.ParseDomainUrl depends on r.TLS
+		r.TLS = &tls.ConnectionState{}
+	}
+
+	permissions := f.UserActions()
+	if uap, ok := f.Site().SiteUsers().This().(feature.UserActionsProvider); ok {
+		permissions = permissions.Append(uap.UserActions()...)
+		permissions = permissions.Append(uap.Action("create", "user"))
+	}
+	r = userbase.SetCurrentPermissions(r, permissions...)
+	return
+}
+
+func bulkFormat(format, path, fallback string) (detected string, err error) {
+	if format = strings.ToLower(format); format != "" {
+		detected = format
+	} else if ext := strings.ToLower(strings.TrimPrefix(filepath.Ext(path), ".")); ext != "" {
+		detected = ext
+	} else {
+		detected = fallback
+	}
+	switch detected {
+	case BulkFormatCSV, BulkFormatJSON:
+	default:
+		err = fmt.Errorf("unable to determine format, use --format csv or json")
+	}
+	return
+}
@@ -0,0 +1,256 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user_manager
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"github.com/Shopify/gomail"
+	"github.com/mrz1836/go-sanitize"
+
+	"github.com/go-corelibs/x-text/message"
+	beContext "github.com/go-enjin/be/pkg/context"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/maps"
+	"github.com/go-enjin/be/pkg/request"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+const (
+	BulkStatusValid   = "valid"
+	BulkStatusCreated = "created"
+	BulkStatusExists  = "exists"
+	BulkStatusError   = "error"
+)
+
+// WelcomeEmailTemplate is the EmailProvider template used for welcome emails
+var WelcomeEmailTemplate = "user-manager--welcome"
+
+// ImportUsers creates new users from the bulk data given and returns the
+// outcome of each row, rows are numbered from one and existing users are not
+// modified. When dryRun is true, rows are validated without making any
+// changes and when welcome is true, each new user is sent a welcome email
+// in the background, after all rows are processed
+func (f *CFeature) ImportUsers(r *http.Request, bulk *BulkUsers, dryRun, welcome bool) (results []*BulkResult, err error) {
+	if !userbase.CurrentUserCan(r, f.Action("create", "user")) {
+		err = berrs.ErrPermissionDenied
+		return
+	} else if welcome && f.emailSender == nil {
+		err = fmt.Errorf("welcome emails are not configured, see .SetWelcomeEmail")
+		return
+	}
+
+	su := f.Site().SiteUsers()
+
+	origin := f.Tag().Kebab()
+	if saf := f.Site().SiteAuth(); saf != nil {
+		origin = saf.Tag().Kebab()
+	}
+
+	for _, group := range maps.SortedKeys(bulk.Groups) {
+		if dryRun || su.GroupPresent(group) {
+			continue
+		} else if err = su.CreateGroup(r, group, bulk.Groups[group]...); err != nil {
+			err = fmt.Errorf("error creating group %q: %w", group, err)
+			return
+		}
+	}
+
+	var welcomes []*gomail.Message
+	defer func() {
+		if len(welcomes) > 0 {
+			f.sendWelcomeEmails(r, welcomes)
+		}
+	}()
+
+	seen := make(map[string]struct{})
+	for idx, u := range bulk.Users {
+		result := &BulkResult{Row: idx + 1, Email: u.Email}
+		results = append(results, result)
+
+		email := sanitize.Email(u.Email, false)
+		if email == "" || !strings.Contains(email, "@") {
+			result.Status, result.Error = BulkStatusError, "invalid email address"
+			continue
+		} else if _, duplicate := seen[email]; duplicate {
+			result.Status, result.Error = BulkStatusError, "duplicate email address"
+			continue
+		}
+		seen[email] = struct{}{}
+		result.Email = email
+
+		rid := su.MakeRealID(email)
+		eid := su.MakeEnjinID(rid)
+		if su.UserPresent(eid) {
+			result.Status = BulkStatusExists
+			continue
+		}
+
+		var missing []string
+		for _, group := range u.Groups {
+			if group == userbase.PublicGroup || group == userbase.UsersGroup {
+				continue
+			} else if _, defined := bulk.Groups[group]; !defined && !su.GroupPresent(group) {
+				missing = append(missing, group.String())
+			}
+		}
+		if len(missing) > 0 {
+			result.Status, result.Error = BulkStatusError, "groups not found: "+strings.Join(missing, ", ")
+			continue
+		}
+
+		if dryRun {
+			result.Status = BulkStatusValid
+			continue
+		}
+
+		if ee := su.CreateUser(r, origin, rid, eid, email); ee != nil {
+			result.Status, result.Error = BulkStatusError, ee.Error()
+			continue
+		}
+		result.Status = BulkStatusCreated
+
+		var problems []string
+		if u.Name != "" {
+			if ee := su.UpdateUserName(r, eid, u.Name); ee != nil {
+				problems = append(problems, "name: "+ee.Error())
+			}
+		}
+		if len(u.Groups) > 0 {
+			if ee := su.UpdateUserGroups(r, eid, u.Groups...); ee != nil {
+				problems = append(problems, "groups: "+ee.Error())
+			}
+		}
+		if len(u.Actions) > 0 {
+			if ee := su.UpdateUserPermissions(r, eid, u.Actions...); ee != nil {
+				problems = append(problems, "actions: "+ee.Error())
+			}
+		}
+		if !u.Active {
+			if ee := su.UpdateUserActive(r, eid, false); ee != nil {
+				problems = append(problems, "active: "+ee.Error())
+			}
+		}
+		if u.Locked {
+			if ee := su.UpdateUserAdminLocked(r, eid, true); ee != nil {
+				problems = append(problems, "locked: "+ee.Error())
+			}
+		}
+		if welcome && u.Active && !u.Locked {
+			name := u.Name
+			if name == "" {
+				name = email
+			}
+			if msg, ee := f.makeWelcomeEmail(r, email, name); ee != nil {
+				problems = append(problems, "welcome email: "+ee.Error())
+			} else {
+				welcomes = append(welcomes, msg)
+			}
+		}
+		result.Error = strings.Join(problems, "; ")
+	}
+
+	return
+}
+
+// ExportUsers returns all users and their groups and actions, along with the
+// actions of each group
+func (f *CFeature) ExportUsers(r *http.Request) (bulk *BulkUsers, err error) {
+	if !userbase.CurrentUserCan(r, f.Action("update", "user")) {
+		err = berrs.ErrPermissionDenied
+		return
+	}
+
+	su := f.Site().SiteUsers()
+	bulk = &BulkUsers{Groups: make(map[feature.Group]feature.Actions)}
+
+	var groups feature.Groups
+	list, _ := su.ListUsers(r, 0, -1, false)
+	for _, u := range list {
+		userGroups := u.GetGroups().Remove(userbase.PublicGroup, userbase.UsersGroup)
+		groups = groups.Append(userGroups...)
+		bulk.Users = append(bulk.Users, &BulkUser{
+			Email:   u.GetEmail(),
+			Name:    u.GetName(),
+			Groups:  userGroups,
+			Actions: u.GetActions(),
+			Active:  u.GetActive(),
+			Locked:  u.GetAdminLocked(),
+		})
+	}
+
+	if gp, ok := su.This().(feature.SiteUsersGroupsProvider); ok {
+		var present feature.Groups
+		if present, err = gp.ListGroups(r); err != nil {
+			return
+		}
+		groups = groups.Append(present...)
+	}
+
+	for _, group := range groups {
+		if group == userbase.PublicGroup {
+			continue
+		} else if actions, ee := su.RetrieveGroup(r, group); ee == nil {
+			bulk.Groups[group] = actions
+		} else if !errors.Is(ee, berrs.ErrGroupNotFound) {
+			err = fmt.Errorf("error retrieving group %q: %w", group, ee)
+			return
+		}
+	}
+
+	return
+}
+
+func (f *CFeature) makeWelcomeEmail(r *http.Request, email, name string) (msg *gomail.Message, err error) {
+	printer := message.GetPrinter(r)
+
+	var signInUrl string
+	if saf := f.Site().SiteAuth(); saf != nil {
+		signInUrl = request.ParseDomainUrl(r) + saf.SiteAuthSignInPath()
+	}
+
+	if msg, err = f.emailProvider.NewEmail(WelcomeEmailTemplate, beContext.Context{
+		"Name":      name,
+		"Email":     email,
+		"SignInUrl": signInUrl,
+		"SiteName":  f.Enjin.SiteName(),
+	}); err != nil {
+		return
+	}
+	msg.SetHeader("To", email)
+	msg.SetHeader("Subject", printer.Sprintf("Welcome to %[1]s", f.Enjin.SiteName()))
+	return
+}
+
+// sendWelcomeEmails sends the messages in the background so that large
+// imports are not waiting on the email sender before responding
+func (f *CFeature) sendWelcomeEmails(r *http.Request, messages []*gomail.Message) {
+	r = r.WithContext(context.WithoutCancel(r.Context()))
+	f.welcoming.Add(1)
+	go func() {
+		defer f.welcoming.Done()
+		for _, msg := range messages {
+			if err := f.emailSender.SendEmail(r, f.emailAccount, msg); err != nil {
+				log.ErrorRF(r, "error sending welcome email to %v: %v", msg.GetHeader("To"), err)
+			}
+		}
+	}()
+}
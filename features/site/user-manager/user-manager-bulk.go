@@ -0,0 +1,199 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user_manager
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+
+	"github.com/go-enjin/be/pkg/feature"
+)
+
+const (
+	BulkFormatCSV  = "csv"
+	BulkFormatJSON = "json"
+)
+
+var (
+	// BulkCsvHeader is the list of column names used when exporting CSV,
+	// imported CSV must have a header row with at least the "email" column
+	BulkCsvHeader = []string{"email", "name", "groups", "actions", "active", "locked"}
+)
+
+// BulkUser is a single user of a bulk import or export, groups and actions
+// are space-separated within CSV columns
+type BulkUser struct {
+	Email   string          `json:"email"`
+	Name    string          `json:"name,omitempty"`
+	Groups  feature.Groups  `json:"groups,omitempty"`
+	Actions feature.Actions `json:"actions,omitempty"`
+	Active  bool            `json:"active"`
+	Locked  bool            `json:"locked"`
+}
+
+// UnmarshalJSON defaults Active to true when not present
+func (u *BulkUser) UnmarshalJSON(data []byte) (err error) {
+	type bulkUser BulkUser
+	v := bulkUser{Active: true}
+	if err = json.Unmarshal(data, &v); err == nil {
+		*u = BulkUser(v)
+	}
+	return
+}
+
+// BulkUsers is the JSON document of a bulk import or export, JSON imports
+// may also be just the list of users
+type BulkUsers struct {
+	Groups map[feature.Group]feature.Actions `json:"groups,omitempty"`
+	Users  []*BulkUser                       `json:"users"`
+}
+
+// BulkResult is the outcome of importing a single row
+type BulkResult struct {
+	Row    int    `json:"row"`
+	Email  string `json:"email"`
+	Status string `json:"status"`
+	Error  string `json:"error,omitempty"`
+}
+
+func (b *BulkResult) String() (s string) {
+	s = fmt.Sprintf("row %d: %s - %s", b.Row, b.Email, b.Status)
+	if b.Error != "" {
+		s += ": " + b.Error
+	}
+	return
+}
+
+// ParseBulkUsers decodes the CSV or JSON data from the reader given
+func ParseBulkUsers(format string, reader io.Reader) (bulk *BulkUsers, err error) {
+	switch strings.ToLower(format) {
+	case BulkFormatCSV:
+		bulk, err = parseBulkUsersCSV(reader)
+	case BulkFormatJSON:
+		bulk, err = parseBulkUsersJSON(reader)
+	default:
+		err = fmt.Errorf("unsupported format: %q", format)
+	}
+	return
+}
+
+func parseBulkUsersJSON(reader io.Reader) (bulk *BulkUsers, err error) {
+	var data []byte
+	if data, err = io.ReadAll(reader); err != nil {
+		return
+	}
+	bulk = &BulkUsers{}
+	if trimmed := strings.TrimSpace(string(data)); strings.HasPrefix(trimmed, "[") {
+		err = json.Unmarshal(data, &bulk.Users)
+		return
+	}
+	err = json.Unmarshal(data, bulk)
+	return
+}
+
+func parseBulkUsersCSV(reader io.Reader) (bulk *BulkUsers, err error) {
+	r := csv.NewReader(reader)
+	r.FieldsPerRecord = -1
+	r.TrimLeadingSpace = true
+
+	var header []string
+	if header, err = r.Read(); err != nil {
+		err = fmt.Errorf("error reading csv header: %w", err)
+		return
+	}
+	columns := make(map[string]int)
+	for idx, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = idx
+	}
+	if _, ok := columns["email"]; !ok {
+		err = fmt.Errorf("csv header is missing the email column")
+		return
+	}
+
+	bulk = &BulkUsers{}
+	for row := 2; ; row++ {
+		var record []string
+		if record, err = r.Read(); err == io.EOF {
+			err = nil
+			return
+		} else if err != nil {
+			err = fmt.Errorf("error reading csv row %d: %w", row, err)
+			return
+		}
+		get := func(name string) (value string) {
+			if idx, ok := columns[name]; ok && idx < len(record) {
+				value = strings.TrimSpace(record[idx])
+			}
+			return
+		}
+		u := &BulkUser{
+			Email:   get("email"),
+			Name:    get("name"),
+			Groups:  feature.NewGroupsFromStrings(strings.Fields(get("groups"))...),
+			Actions: feature.ParseActions(get("actions")),
+			Active:  true,
+		}
+		if v := get("active"); v != "" {
+			if u.Active, err = strconv.ParseBool(v); err != nil {
+				err = fmt.Errorf("error parsing csv row %d active column: %q", row, v)
+				return
+			}
+		}
+		if v := get("locked"); v != "" {
+			if u.Locked, err = strconv.ParseBool(v); err != nil {
+				err = fmt.Errorf("error parsing csv row %d locked column: %q", row, v)
+				return
+			}
+		}
+		bulk.Users = append(bulk.Users, u)
+	}
+}
+
+// WriteBulkUsers encodes the users given in the format requested, CSV output
+// does not include the group definitions
+func WriteBulkUsers(format string, bulk *BulkUsers, w io.Writer) (err error) {
+	switch strings.ToLower(format) {
+	case BulkFormatCSV:
+		cw := csv.NewWriter(w)
+		if err = cw.Write(BulkCsvHeader); err != nil {
+			return
+		}
+		for _, u := range bulk.Users {
+			if err = cw.Write([]string{
+				u.Email,
+				u.Name,
+				u.Groups.String(),
+				u.Actions.String(),
+				strconv.FormatBool(u.Active),
+				strconv.FormatBool(u.Locked),
+			}); err != nil {
+				return
+			}
+		}
+		cw.Flush()
+		err = cw.Error()
+	case BulkFormatJSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "\t")
+		err = encoder.Encode(bulk)
+	default:
+		err = fmt.Errorf("unsupported format: %q", format)
+	}
+	return
+}
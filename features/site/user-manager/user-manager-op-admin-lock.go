@@ -44,18 +44,29 @@ func (f *CFeature) opAdminLockUser(form context.Context, r *http.Request) {
 
 	su := f.Site().SiteUsers()
 	su.LockUser(r, userEID)
-	defer su.UnlockUser(r, userEID)
 
 	if !su.UserPresent(userEID) {
+		su.UnlockUser(r, userEID)
 		log.WarnRF(r, "user %q attempting to admin-lock a user that does not exist!", eid)
 		return
 	}
 
 	if err := su.SetUserAdminLocked(r, userEID, true); err != nil {
+		su.UnlockUser(r, userEID)
 		f.Site().PushErrorNotice(eid, true, errors.UnexpectedError(printer))
 		return
 	}
 
+	su.UnlockUser(r, userEID)
+
+	sa := f.Site().SiteAuth()
+	if userbase.CurrentUserCan(r, sa.Action("revoke-other", "sessions")) {
+		// a locked user is signed out of every session at once
+		if err := sa.RevokeUserSessions(r, userEID); err != nil {
+			log.ErrorRF(r, "error revoking admin-locked user sessions: %q - %v", userEID, err)
+		}
+	}
+
 	f.Site().PushInfoNotice(eid, true, printer.Sprintf(`The user has been blocked from accessing the site.`))
 	return
 }
@@ -0,0 +1,143 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user_manager
+
+import (
+	"net/http"
+	"path/filepath"
+	"strings"
+
+	"github.com/go-corelibs/x-text/message"
+	beContext "github.com/go-enjin/be/pkg/context"
+	"github.com/go-enjin/be/pkg/editor"
+	berrs "github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+var (
+	// MaxImportUsersSize is the maximum number of bytes accepted for
+	// multipart requests, which includes the import-users uploads
+	MaxImportUsersSize int64 = 1024 * 1024 * 8
+	// MaxImportErrorNotices is the maximum number of import-users row errors
+	// reported as individual user notices, the remainder are summarized
+	MaxImportErrorNotices = 10
+)
+
+func (f *CFeature) opImportUsers(form beContext.Context, r *http.Request) {
+	eid := userbase.GetCurrentEID(r)
+	printer := message.GetPrinter(r)
+
+	if !userbase.CurrentUserCan(r, f.Action("create", "user")) {
+		log.WarnRF(r, "user %q attempted to import users without permission!", eid)
+		f.Site().PushErrorNotice(eid, true, berrs.PermissionDeniedError(printer))
+		return
+	}
+
+	if r.MultipartForm == nil || len(r.MultipartForm.File[editor.ImportUsersActionKey+"~file"]) == 0 {
+		f.Site().PushErrorNotice(eid, true, printer.Sprintf(`A CSV or JSON file is required to import users.`))
+		return
+	}
+	header := r.MultipartForm.File[editor.ImportUsersActionKey+"~file"][0]
+
+	format := strings.ToLower(form.String(editor.ImportUsersActionKey+"~format", ""))
+	if format == "" {
+		format = strings.ToLower(strings.TrimPrefix(filepath.Ext(header.Filename), "."))
+	}
+	dryRun := form.String(editor.ImportUsersActionKey+"~dry-run", "false") == "true"
+	welcome := form.String(editor.ImportUsersActionKey+"~welcome", "false") == "true"
+
+	fh, err := header.Open()
+	if err != nil {
+		log.ErrorRF(r, "error opening uploaded import-users file: %v", err)
+		f.Site().PushErrorNotice(eid, true, berrs.UnexpectedError(printer))
+		return
+	}
+	defer func() { _ = fh.Close() }()
+
+	var bulk *BulkUsers
+	if bulk, err = ParseBulkUsers(format, fh); err != nil {
+		f.Site().PushErrorNotice(eid, true, printer.Sprintf(`Error reading the import file: %[1]s`, err.Error()))
+		return
+	}
+
+	var results []*BulkResult
+	if results, err = f.ImportUsers(r, bulk, dryRun, welcome); err != nil {
+		log.ErrorRF(r, "error importing users: %v", err)
+		f.Site().PushErrorNotice(eid, true, printer.Sprintf(`Error importing users: %[1]s`, err.Error()))
+		return
+	}
+
+	var reported int
+	counts := make(map[string]int)
+	for _, result := range results {
+		counts[result.Status] += 1
+		if result.Error != "" {
+			if reported < MaxImportErrorNotices {
+				f.Site().PushErrorNotice(eid, true, printer.Sprintf(`Row %[1]d (%[2]s): %[3]s`, result.Row, result.Email, result.Error))
+			}
+			reported += 1
+		}
+	}
+	if remaining := reported - MaxImportErrorNotices; remaining > 0 {
+		f.Site().PushErrorNotice(eid, true, printer.Sprintf(`%[1]d more rows have errors which are not listed, please correct the import file and try again.`, remaining))
+	}
+
+	if dryRun {
+		f.Site().PushInfoNotice(eid, true, printer.Sprintf(`Dry run: %[1]d users can be created, %[2]d exist already and %[3]d rows have errors.`, counts[BulkStatusValid], counts[BulkStatusExists], counts[BulkStatusError]))
+		return
+	}
+	f.Site().PushInfoNotice(eid, true, printer.Sprintf(`Imported %[1]d new users, %[2]d exist already and %[3]d rows have errors.`, counts[BulkStatusCreated], counts[BulkStatusExists], counts[BulkStatusError]))
+	return
+}
+
+func (f *CFeature) opExportUsers(form beContext.Context, w http.ResponseWriter, r *http.Request) (handled bool) {
+	eid := userbase.GetCurrentEID(r)
+	printer := message.GetPrinter(r)
+
+	if !userbase.CurrentUserCan(r, f.Action("update", "user")) {
+		log.WarnRF(r, "user %q attempted to export users without permission!", eid)
+		f.Site().PushErrorNotice(eid, true, berrs.PermissionDeniedError(printer))
+		return
+	}
+
+	format := strings.ToLower(form.String(editor.ExportUsersActionKey+"~format", BulkFormatJSON))
+	var contentType string
+	switch format {
+	case BulkFormatCSV:
+		contentType = "text/csv; charset=utf-8"
+	case BulkFormatJSON:
+		contentType = "application/json; charset=utf-8"
+	default:
+		f.Site().PushErrorNotice(eid, true, printer.Sprintf(`Unsupported export format: %[1]s`, format))
+		return
+	}
+
+	bulk, err := f.ExportUsers(r)
+	if err != nil {
+		log.ErrorRF(r, "error exporting users: %v", err)
+		f.Site().PushErrorNotice(eid, true, berrs.UnexpectedError(printer))
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Disposition", `attachment; filename="users.`+format+`"`)
+	w.WriteHeader(http.StatusOK)
+	if err = WriteBulkUsers(format, bulk, w); err != nil {
+		log.ErrorRF(r, "error writing users export: %v", err)
+	}
+	handled = true
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user_manager
+
+import (
+	"net/http"
+
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/pkg/context"
+	"github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) opRevokeApiKeys(form context.Context, r *http.Request) {
+	eid := userbase.GetCurrentEID(r)
+	printer := message.GetPrinter(r)
+	sa := f.Site().SiteAuth()
+
+	if !userbase.CurrentUserCan(r, sa.Action("revoke-other", "api-keys")) {
+		log.WarnRF(r, "user %q attempted to revoke a user's api keys without permission!", eid)
+		f.Site().PushErrorNotice(eid, true, errors.PermissionDeniedError(printer))
+		return
+	}
+
+	var userEID, confirmed string
+	if userEID = form.String("target", ""); userEID == "" {
+		return
+	} else if confirmed = form.String(editor.RevokeApiKeysActionKey+"-confirmed", "false"); confirmed != "true" {
+		return
+	}
+
+	if !f.Site().SiteUsers().UserPresent(userEID) {
+		log.WarnRF(r, "user %q attempting to revoke api keys of a user that does not exist!", eid)
+		return
+	}
+
+	// an empty key id revokes all of the user's keys
+	if id := form.String(editor.RevokeApiKeysActionKey+"~id", ""); id != "" {
+		if err := sa.RevokeUserApiKey(r, userEID, id); err != nil {
+			log.ErrorRF(r, "error revoking user api key: %q - %v", userEID, err)
+			f.Site().PushErrorNotice(eid, true, errors.UnexpectedError(printer))
+			return
+		}
+		f.Site().PushInfoNotice(eid, true, printer.Sprintf(`The user's API key has been revoked.`))
+		return
+	}
+
+	if err := sa.RevokeUserApiKeys(r, userEID); err != nil {
+		log.ErrorRF(r, "error revoking user api keys: %q - %v", userEID, err)
+		f.Site().PushErrorNotice(eid, true, errors.UnexpectedError(printer))
+		return
+	}
+
+	f.Site().PushInfoNotice(eid, true, printer.Sprintf(`All of the user's API keys have been revoked.`))
+	return
+}
@@ -0,0 +1,70 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package user_manager
+
+import (
+	"net/http"
+
+	"github.com/go-corelibs/x-text/message"
+	"github.com/go-enjin/be/pkg/context"
+	"github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/errors"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+func (f *CFeature) opRevokeSessions(form context.Context, r *http.Request) {
+	eid := userbase.GetCurrentEID(r)
+	printer := message.GetPrinter(r)
+	sa := f.Site().SiteAuth()
+
+	if !userbase.CurrentUserCan(r, sa.Action("revoke-other", "sessions")) {
+		log.WarnRF(r, "user %q attempted to revoke a user's sessions without permission!", eid)
+		f.Site().PushErrorNotice(eid, true, errors.PermissionDeniedError(printer))
+		return
+	}
+
+	var userEID, confirmed string
+	if userEID = form.String("target", ""); userEID == "" {
+		return
+	} else if confirmed = form.String(editor.RevokeSessionsActionKey+"-confirmed", "false"); confirmed != "true" {
+		return
+	}
+
+	if !f.Site().SiteUsers().UserPresent(userEID) {
+		log.WarnRF(r, "user %q attempting to revoke sessions of a user that does not exist!", eid)
+		return
+	}
+
+	// an empty session id revokes all of the user's sessions
+	if id := form.String(editor.RevokeSessionsActionKey+"~id", ""); id != "" {
+		if err := sa.RevokeUserSession(r, userEID, id); err != nil {
+			log.ErrorRF(r, "error revoking user session: %q - %v", userEID, err)
+			f.Site().PushErrorNotice(eid, true, errors.UnexpectedError(printer))
+			return
+		}
+		f.Site().PushInfoNotice(eid, true, printer.Sprintf(`The user's session has been signed out.`))
+		return
+	}
+
+	if err := sa.RevokeUserSessions(r, userEID); err != nil {
+		log.ErrorRF(r, "error revoking user sessions: %q - %v", userEID, err)
+		f.Site().PushErrorNotice(eid, true, errors.UnexpectedError(printer))
+		return
+	}
+
+	f.Site().PushInfoNotice(eid, true, printer.Sprintf(`All of the user's sessions have been signed out.`))
+	return
+}
@@ -16,8 +16,10 @@ package user_manager
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/log"
 	"github.com/go-enjin/be/pkg/request"
 )
 
@@ -27,6 +29,15 @@ func (f *CFeature) HandleUserManager(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		r.Body = http.MaxBytesReader(w, r.Body, MaxImportUsersSize)
+		if err := r.ParseMultipartForm(MaxImportUsersSize); err != nil {
+			log.WarnRF(r, "error parsing user-manager multipart form: %v", err)
+			f.Enjin.Serve400(w, r)
+			return
+		}
+	}
+
 	form := request.SafeParseForm(r)
 
 	switch request.SafeQueryFormValue(r, "submit") {
@@ -50,6 +61,20 @@ func (f *CFeature) HandleUserManager(w http.ResponseWriter, r *http.Request) {
 
 	case editor.ResetUserOtpActionKey:
 		f.opResetUserOtp(form, r)
+
+	case editor.RevokeApiKeysActionKey:
+		f.opRevokeApiKeys(form, r)
+
+	case editor.RevokeSessionsActionKey:
+		f.opRevokeSessions(form, r)
+
+	case editor.ImportUsersActionKey:
+		f.opImportUsers(form, r)
+
+	case editor.ExportUsersActionKey:
+		if handled := f.opExportUsers(form, w, r); handled {
+			return
+		}
 	}
 
 	f.Enjin.ServeRedirect(f.SiteFeaturePath(), w, r)
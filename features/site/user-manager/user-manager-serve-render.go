@@ -22,6 +22,7 @@ import (
 	"github.com/go-enjin/be/pkg/editor"
 	"github.com/go-enjin/be/pkg/feature"
 	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
 	"github.com/go-enjin/be/pkg/userbase"
 )
 
@@ -38,7 +39,23 @@ func (f *CFeature) RenderUserManager(w http.ResponseWriter, r *http.Request) {
 	}
 
 	su := f.Site().SiteUsers()
-	list, total := su.ListUsers(r, 0, -1, false)
+	sa := f.Site().SiteAuth()
+
+	var list []feature.User
+	var total int
+	if sp, ok := su.This().(feature.SiteUsersSearchProvider); ok {
+		query := request.SafeQueryFormValue(r, "q")
+		sortBy := request.SafeQueryFormValue(r, "sort")
+		sortDesc := request.SafeQueryFormValue(r, "order") == "desc"
+		list, total = sp.SearchUsers(r, query, sortBy, 0, -1, sortDesc)
+		ctx.SetSpecific("UserSearch", context.Context{
+			"Query":    query,
+			"SortBy":   sortBy,
+			"SortDesc": sortDesc,
+		})
+	} else {
+		list, total = su.ListUsers(r, 0, -1, false)
+	}
 	ctx.SetSpecific("TotalUsers", total)
 	var userList []context.Context
 	for _, u := range list {
@@ -65,6 +82,22 @@ func (f *CFeature) RenderUserManager(w http.ResponseWriter, r *http.Request) {
 				}
 			}
 		}
+		if notSelf && sa.ApiKeysAllowed() && userbase.CurrentUserCan(r, sa.Action("revoke-other", "api-keys")) {
+			if keys, err := sa.ListUserApiKeys(r, u.GetEID()); err != nil {
+				log.ErrorRF(r, "error listing user api keys: %q - %v", u.GetEID(), err)
+			} else if keys.Len() > 0 {
+				uCtx.SetSpecific("ApiKeys", keys)
+				actions = append(actions, editor.MakeRevokeApiKeys(printer, email))
+			}
+		}
+		if notSelf && userbase.CurrentUserCan(r, sa.Action("revoke-other", "sessions")) {
+			if sessions, err := sa.ListUserSessions(r, u.GetEID()); err != nil {
+				log.ErrorRF(r, "error listing user sessions: %q - %v", u.GetEID(), err)
+			} else if sessions.Len() > 0 {
+				uCtx.SetSpecific("Sessions", sessions)
+				actions = append(actions, editor.MakeRevokeSessions(printer, email))
+			}
+		}
 		if notSelf {
 			if userbase.CurrentUserCan(r, f.Action("delete", "user")) {
 				actions = append(actions, editor.MakeDeleteUser(printer, email))
@@ -86,6 +119,11 @@ func (f *CFeature) RenderUserManager(w http.ResponseWriter, r *http.Request) {
 
 	if userbase.CurrentUserCan(r, f.Action("create", "user")) {
 		ctx.SetSpecific("CreateUserAction", editor.MakeCreateUser(printer))
+		ctx.SetSpecific("ImportUsersAction", editor.MakeImportUsers(printer))
+	}
+
+	if userbase.CurrentUserCan(r, f.Action("update", "user")) {
+		ctx.SetSpecific("ExportUsersAction", editor.MakeExportUsers(printer))
 	}
 
 	t := f.SiteFeatureTheme()
@@ -17,6 +17,7 @@ package user_manager
 import (
 	"fmt"
 	"net/http"
+	"sync"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/urfave/cli/v2"
@@ -46,11 +47,23 @@ type Feature interface {
 type MakeFeature interface {
 	feature.SiteMakeFeature[MakeFeature]
 
+	// SetWelcomeEmail specifies the email account and feature.EmailProvider
+	// to use when sending welcome emails to newly imported users
+	SetWelcomeEmail(account string, provider feature.Tag) MakeFeature
+
 	Make() Feature
 }
 
 type CFeature struct {
 	site.CSiteFeature[MakeFeature]
+
+	emailAccount     string
+	emailProviderTag feature.Tag
+
+	emailSender   feature.EmailSender
+	emailProvider feature.EmailProvider
+
+	welcoming sync.WaitGroup
 }
 
 func New() MakeFeature {
@@ -77,6 +90,12 @@ func (f *CFeature) Init(this interface{}) {
 	return
 }
 
+func (f *CFeature) SetWelcomeEmail(account string, provider feature.Tag) MakeFeature {
+	f.emailAccount = account
+	f.emailProviderTag = provider
+	return f
+}
+
 func (f *CFeature) Make() (feat Feature) {
 	return f
 }
@@ -85,6 +104,7 @@ func (f *CFeature) Build(b feature.Buildable) (err error) {
 	if err = f.CSiteFeature.Build(b); err != nil {
 		return
 	}
+	f.buildBulkCommands(b)
 	return
 }
 
@@ -92,6 +112,25 @@ func (f *CFeature) Startup(ctx *cli.Context) (err error) {
 	if err = f.CSiteFeature.Startup(ctx); err != nil {
 		return
 	}
+
+	if f.emailAccount == "" {
+		// welcome emails are optional
+		return
+	} else if f.emailSender = f.Enjin.FindEmailAccount(f.emailAccount); f.emailSender == nil {
+		err = fmt.Errorf("%v email sender not found", f.emailAccount)
+		return
+	} else if f.emailProviderTag.IsNil() {
+		err = fmt.Errorf(".SetWelcomeEmail requires an email provider")
+		return
+	} else if epf, ok := f.Enjin.Features().Get(f.emailProviderTag); !ok {
+		err = fmt.Errorf("%v email provider feature not found", f.emailProviderTag)
+		return
+	} else if ep, ok := epf.This().(feature.EmailProvider); !ok {
+		err = fmt.Errorf("%v feature is not a feature.EmailProvider", f.emailProviderTag)
+		return
+	} else {
+		f.emailProvider = ep
+	}
 	return
 }
 
@@ -0,0 +1,197 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"net/http"
+	"net/http/pprof"
+	"strings"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	uses_actions "github.com/go-enjin/be/pkg/feature/uses-actions"
+	"github.com/go-enjin/be/pkg/log"
+)
+
+const Tag feature.Tag = "srv-diagnostics"
+
+const (
+	DefaultPath = "/debug"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+type Feature interface {
+	feature.Feature
+	feature.UseMiddleware
+	feature.UserActionsProvider
+}
+
+type MakeFeature interface {
+	Make() Feature
+
+	// SetPath changes the base path of all diagnostic endpoints, default is
+	// "/debug"
+	SetPath(path string) MakeFeature
+}
+
+// CFeature mounts net/http/pprof and other runtime diagnostics under an admin
+// path, all endpoints require the "view" "diagnostics" user action and any
+// unauthorized requests are served a 404 page:
+//
+//	{path}/pprof/     - the net/http/pprof index and named profiles
+//	{path}/goroutines - full goroutine stack dump (text)
+//	{path}/memstats   - runtime.MemStats and other runtime details (JSON)
+//	{path}/features   - enjin feature inventory (JSON)
+//	{path}/routes     - enjin route and middleware inventory (JSON)
+type CFeature struct {
+	feature.CFeature
+	uses_actions.CUsesActions
+
+	path    string
+	started time.Time
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	f.CUsesActions.ConstructUsesActions(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.path = DefaultPath
+}
+
+func (f *CFeature) SetPath(path string) MakeFeature {
+	f.path = path
+	return f
+}
+
+func (f *CFeature) Make() Feature {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	b.AddFlags(
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-path",
+			Usage:    "specify the base path of the diagnostic endpoints",
+			Value:    f.path,
+			EnvVars:  b.MakeEnvKeys(f.Tag().ScreamingSnake(), "PATH"),
+			Category: f.KebabTag,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+
+	if key := f.KebabTag + "-path"; ctx.IsSet(key) {
+		if v := strings.TrimSpace(ctx.String(key)); v != "" {
+			f.path = v
+		}
+	}
+	f.path = "/" + strings.Trim(f.path, "/")
+
+	f.started = time.Now()
+	log.DebugF("%v - serving diagnostics from: %v", f.Tag(), f.path)
+	return
+}
+
+func (f *CFeature) UserActions() (list feature.Actions) {
+	list = feature.Actions{
+		f.Action("view", "diagnostics"),
+	}
+	return
+}
+
+func (f *CFeature) Use(s feature.System) feature.MiddlewareFn {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path != f.path && !strings.HasPrefix(r.URL.Path, f.path+"/") {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			if !f.Enjin.ValidateUserRequest(f.Action("view", "diagnostics"), w, r) {
+				return
+			}
+
+			w.Header().Set("Cache-Control", "no-store")
+			f.serveDiagnostics(strings.Trim(strings.TrimPrefix(r.URL.Path, f.path), "/"), w, r)
+		})
+	}
+}
+
+func (f *CFeature) serveDiagnostics(name string, w http.ResponseWriter, r *http.Request) {
+	switch name {
+	case "":
+		http.Redirect(w, r, f.path+"/pprof/", http.StatusSeeOther)
+	case "goroutines":
+		f.serveGoroutines(w, r)
+	case "memstats":
+		f.serveJSON(f.makeRuntimeReport(), w, r)
+	case "features":
+		f.serveJSON(f.makeFeaturesReport(), w, r)
+	case "routes":
+		f.serveJSON(f.makeRoutesReport(), w, r)
+	case "pprof":
+		if !strings.HasSuffix(r.URL.Path, "/") {
+			// the pprof index uses relative links
+			http.Redirect(w, r, f.path+"/pprof/", http.StatusSeeOther)
+			return
+		}
+		pprof.Index(w, r)
+	case "pprof/cmdline":
+		pprof.Cmdline(w, r)
+	case "pprof/profile":
+		pprof.Profile(w, r)
+	case "pprof/symbol":
+		pprof.Symbol(w, r)
+	case "pprof/trace":
+		pprof.Trace(w, r)
+	default:
+		if profile, ok := strings.CutPrefix(name, "pprof/"); ok {
+			// pprof.Index only serves named profiles from /debug/pprof/
+			pprof.Handler(profile).ServeHTTP(w, r)
+			return
+		}
+		f.Enjin.ServeNotFound(w, r)
+	}
+}
+
+func (f *CFeature) serveJSON(v interface{}, w http.ResponseWriter, r *http.Request) {
+	if err := f.Enjin.ServeJSON(v, w, r); err != nil {
+		log.ErrorRF(r, "error serving %v diagnostics: %v", f.Tag(), err)
+		f.Enjin.Serve500(w, r)
+	}
+}
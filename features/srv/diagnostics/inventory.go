@@ -0,0 +1,121 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package diagnostics
+
+import (
+	"fmt"
+	"net/http"
+	"runtime"
+	"runtime/pprof"
+	"sort"
+	"time"
+
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/maps"
+)
+
+// RuntimeReport is the JSON served from the memstats endpoint
+type RuntimeReport struct {
+	Version    string            `json:"version"`
+	GoVersion  string            `json:"go-version"`
+	GOOS       string            `json:"goos"`
+	GOARCH     string            `json:"goarch"`
+	NumCPU     int               `json:"num-cpu"`
+	GOMAXPROCS int               `json:"gomaxprocs"`
+	Goroutines int               `json:"goroutines"`
+	Uptime     string            `json:"uptime"`
+	MemStats   *runtime.MemStats `json:"memstats"`
+}
+
+// FeatureInventory describes a single enjin feature
+type FeatureInventory struct {
+	Tag     string   `json:"tag"`
+	BaseTag string   `json:"base-tag"`
+	Type    string   `json:"type"`
+	State   string   `json:"state"`
+	Depends []string `json:"depends,omitempty"`
+}
+
+// RoutesReport is the JSON served from the routes endpoint
+type RoutesReport struct {
+	Processors       []string `json:"processors"`
+	UseMiddlewares   []string `json:"use-middlewares"`
+	FeatureProcs     []string `json:"feature-processors"`
+	ServePaths       []string `json:"serve-paths"`
+	ApplyMiddlewares []string `json:"apply-middlewares"`
+	RoutePages       string   `json:"route-pages,omitempty"`
+	Pages            []string `json:"pages"`
+}
+
+func (f *CFeature) serveGoroutines(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	if err := pprof.Lookup("goroutine").WriteTo(w, 2); err != nil {
+		_, _ = fmt.Fprintf(w, "error writing goroutine dump: %v\n", err)
+	}
+}
+
+func (f *CFeature) makeRuntimeReport() (report RuntimeReport) {
+	ms := &runtime.MemStats{}
+	runtime.ReadMemStats(ms)
+	report = RuntimeReport{
+		Version:    globals.BuildVersion(),
+		GoVersion:  runtime.Version(),
+		GOOS:       runtime.GOOS,
+		GOARCH:     runtime.GOARCH,
+		NumCPU:     runtime.NumCPU(),
+		GOMAXPROCS: runtime.GOMAXPROCS(0),
+		Goroutines: runtime.NumGoroutine(),
+		Uptime:     time.Since(f.started).Round(time.Second).String(),
+		MemStats:   ms,
+	}
+	return
+}
+
+func (f *CFeature) makeFeaturesReport() (list []FeatureInventory) {
+	for _, ef := range f.Enjin.Features().List() {
+		list = append(list, FeatureInventory{
+			Tag:     ef.Tag().String(),
+			BaseTag: ef.BaseTag().String(),
+			Type:    fmt.Sprintf("%T", ef.This()),
+			State:   ef.State().String(),
+			Depends: ef.Depends().Strings(),
+		})
+	}
+	return
+}
+
+func (f *CFeature) makeRoutesReport() (report RoutesReport) {
+	report.Processors = maps.SortedKeys(f.Enjin.Processors())
+	for _, um := range f.Enjin.GetUseMiddlewares() {
+		report.UseMiddlewares = append(report.UseMiddlewares, um.Tag().String())
+	}
+	for _, proc := range f.Enjin.GetProcessors() {
+		report.FeatureProcs = append(report.FeatureProcs, proc.Tag().String())
+	}
+	for _, sp := range f.Enjin.GetServePathFeatures() {
+		report.ServePaths = append(report.ServePaths, sp.Tag().String())
+	}
+	for _, am := range f.Enjin.GetApplyMiddlewares() {
+		report.ApplyMiddlewares = append(report.ApplyMiddlewares, am.Tag().String())
+	}
+	if rph := f.Enjin.GetRoutePagesHandler(); rph != nil {
+		report.RoutePages = rph.Tag().String()
+	}
+	for url := range f.Enjin.Pages() {
+		report.Pages = append(report.Pages, url)
+	}
+	sort.Strings(report.Pages)
+	return
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-enjin/be/pkg/feature"
+)
+
+const (
+	StatusOk          = "ok"
+	StatusUnavailable = "unavailable"
+)
+
+// Report is the JSON detail served to authorized health and readiness
+// requests
+type Report struct {
+	Status       string          `json:"status"`
+	ShuttingDown bool            `json:"shutting-down,omitempty"`
+	Features     []FeatureReport `json:"features"`
+}
+
+// FeatureReport describes the lifecycle state and the optional health check
+// result of a single feature
+type FeatureReport struct {
+	Tag      string `json:"tag"`
+	State    string `json:"state"`
+	Ready    bool   `json:"ready"`
+	Checked  bool   `json:"checked,omitempty"`
+	Healthy  bool   `json:"healthy"`
+	Duration string `json:"duration,omitempty"`
+	Error    string `json:"error,omitempty"`
+}
+
+// checkResult is the outcome of a single feature.HealthChecker check
+type checkResult struct {
+	duration time.Duration
+	err      error
+}
+
+// makeReport inspects the lifecycle state of all enjin features; when
+// readiness is true, the enjin shutdown state and the results of any
+// feature.HealthChecker checks are also considered. Liveness reports never
+// run the health checks
+func (f *CFeature) makeReport(readiness bool) (report Report) {
+	features := f.Enjin.Features().List()
+	report.Features = make([]FeatureReport, len(features))
+	report.ShuttingDown = f.Enjin.ShuttingDown()
+
+	var checks map[feature.Tag]checkResult
+	if readiness {
+		checks = f.runChecks(features)
+	}
+
+	for idx, feat := range features {
+		state := feat.State()
+		fr := FeatureReport{
+			Tag:     feat.Tag().String(),
+			State:   state.String(),
+			Ready:   state.Ready(),
+			Healthy: true,
+		}
+		if result, checked := checks[feat.Tag()]; checked {
+			fr.Checked = true
+			fr.Duration = result.duration.String()
+			if fr.Healthy = result.err == nil; !fr.Healthy {
+				fr.Error = result.err.Error()
+			}
+		}
+		report.Features[idx] = fr
+	}
+
+	healthy := !readiness || !report.ShuttingDown
+	for _, fr := range report.Features {
+		if !fr.Healthy || (readiness && !fr.Ready) {
+			healthy = false
+			break
+		}
+	}
+
+	if healthy {
+		report.Status = StatusOk
+	} else {
+		report.Status = StatusUnavailable
+	}
+	return
+}
+
+// runChecks returns the results of all feature.HealthChecker checks, running
+// the checks concurrently and bounded by the configured check timeout only
+// when the previous results are older than the configured check interval;
+// concurrent requests wait for and share the same results
+func (f *CFeature) runChecks(features feature.Features) (checks map[feature.Tag]checkResult) {
+	f.checksMutex.Lock()
+	defer f.checksMutex.Unlock()
+
+	if !f.checked.IsZero() && time.Since(f.checked) < f.checkInterval {
+		checks = f.checks
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), f.checkTimeout)
+	defer cancel()
+
+	var mutex sync.Mutex
+	var wg sync.WaitGroup
+	checks = make(map[feature.Tag]checkResult)
+	for _, feat := range features {
+		if hc, ok := feat.This().(feature.HealthChecker); ok {
+			wg.Add(1)
+			go func(tag feature.Tag, hc feature.HealthChecker) {
+				defer wg.Done()
+				start := time.Now()
+				err := runCheck(ctx, hc)
+				mutex.Lock()
+				defer mutex.Unlock()
+				checks[tag] = checkResult{duration: time.Since(start), err: err}
+			}(feat.Tag(), hc)
+		}
+	}
+	wg.Wait()
+
+	f.checks = checks
+	f.checked = time.Now()
+	return
+}
+
+// runCheck calls hc.HealthCheck and returns the context error if the check
+// does not complete before the context is done
+func runCheck(ctx context.Context, hc feature.HealthChecker) (err error) {
+	result := make(chan error, 1)
+	go func() {
+		result <- hc.HealthCheck(ctx)
+	}()
+	select {
+	case err = <-result:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+	return
+}
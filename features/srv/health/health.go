@@ -0,0 +1,277 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package health
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	uses_actions "github.com/go-enjin/be/pkg/feature/uses-actions"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net/serve"
+	"github.com/go-enjin/be/pkg/userbase"
+)
+
+const Tag feature.Tag = "srv-health"
+
+const (
+	DefaultHealthPath    = "/healthz"
+	DefaultReadyPath     = "/readyz"
+	DefaultCheckTimeout  = 5 * time.Second
+	DefaultCheckInterval = 10 * time.Second
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+type Feature interface {
+	feature.Feature
+	feature.UseMiddleware
+	feature.UserActionsProvider
+}
+
+type MakeFeature interface {
+	Make() Feature
+
+	// SetHealthPath changes the liveness endpoint path, default is "/healthz"
+	SetHealthPath(path string) MakeFeature
+	// SetReadyPath changes the readiness endpoint path, default is "/readyz"
+	SetReadyPath(path string) MakeFeature
+	// SetCheckTimeout limits how long all feature.HealthChecker checks may
+	// take in total for a single request
+	SetCheckTimeout(timeout time.Duration) MakeFeature
+	// SetCheckInterval specifies how long the feature.HealthChecker results
+	// are reused for before the checks are run again, default is 10s
+	SetCheckInterval(interval time.Duration) MakeFeature
+	// SetDetailToken configures a bearer token which, when presented in an
+	// Authorization header, is granted the JSON report detail
+	SetDetailToken(token string) MakeFeature
+}
+
+type CFeature struct {
+	feature.CFeature
+	uses_actions.CUsesActions
+
+	healthPath    string
+	readyPath     string
+	checkTimeout  time.Duration
+	checkInterval time.Duration
+	detailToken   string
+
+	checked     time.Time
+	checks      map[feature.Tag]checkResult
+	checksMutex sync.Mutex
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	f.CUsesActions.ConstructUsesActions(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.healthPath = DefaultHealthPath
+	f.readyPath = DefaultReadyPath
+	f.checkTimeout = DefaultCheckTimeout
+	f.checkInterval = DefaultCheckInterval
+	f.checks = make(map[feature.Tag]checkResult)
+}
+
+func (f *CFeature) SetHealthPath(path string) MakeFeature {
+	f.healthPath = path
+	return f
+}
+
+func (f *CFeature) SetReadyPath(path string) MakeFeature {
+	f.readyPath = path
+	return f
+}
+
+func (f *CFeature) SetCheckTimeout(timeout time.Duration) MakeFeature {
+	f.checkTimeout = timeout
+	return f
+}
+
+func (f *CFeature) SetCheckInterval(interval time.Duration) MakeFeature {
+	f.checkInterval = interval
+	return f
+}
+
+func (f *CFeature) SetDetailToken(token string) MakeFeature {
+	f.detailToken = token
+	return f
+}
+
+func (f *CFeature) Make() Feature {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	envPrefix := f.Tag().ScreamingSnake()
+	b.AddFlags(
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-health-path",
+			Usage:    "specify the liveness endpoint path",
+			Value:    f.healthPath,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "HEALTH_PATH"),
+			Category: f.KebabTag,
+		},
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-ready-path",
+			Usage:    "specify the readiness endpoint path",
+			Value:    f.readyPath,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "READY_PATH"),
+			Category: f.KebabTag,
+		},
+		&cli.DurationFlag{
+			Name:     f.KebabTag + "-check-timeout",
+			Usage:    "maximum duration of all feature health checks for a single request",
+			Value:    f.checkTimeout,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "CHECK_TIMEOUT"),
+			Category: f.KebabTag,
+		},
+		&cli.DurationFlag{
+			Name:     f.KebabTag + "-check-interval",
+			Usage:    "duration the feature health check results are reused for",
+			Value:    f.checkInterval,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "CHECK_INTERVAL"),
+			Category: f.KebabTag,
+		},
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-detail-token",
+			Usage:    "bearer token granting access to the JSON health report",
+			EnvVars:  b.MakeEnvKeys(envPrefix, "DETAIL_TOKEN"),
+			Category: f.KebabTag,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+
+	if key := f.KebabTag + "-health-path"; ctx.IsSet(key) {
+		if v := strings.TrimSpace(ctx.String(key)); v != "" {
+			f.healthPath = v
+		}
+	}
+
+	if key := f.KebabTag + "-ready-path"; ctx.IsSet(key) {
+		if v := strings.TrimSpace(ctx.String(key)); v != "" {
+			f.readyPath = v
+		}
+	}
+
+	if key := f.KebabTag + "-check-timeout"; ctx.IsSet(key) {
+		if v := ctx.Duration(key); v > 0 {
+			f.checkTimeout = v
+		}
+	}
+
+	if key := f.KebabTag + "-check-interval"; ctx.IsSet(key) {
+		f.checkInterval = ctx.Duration(key)
+	}
+
+	if key := f.KebabTag + "-detail-token"; ctx.IsSet(key) {
+		f.detailToken = strings.TrimSpace(ctx.String(key))
+	}
+
+	log.DebugF("%v - health: %v, ready: %v", f.Tag(), f.healthPath, f.readyPath)
+	return
+}
+
+func (f *CFeature) UserActions() (list feature.Actions) {
+	list = feature.Actions{
+		f.Action("view", "health"),
+	}
+	return
+}
+
+func (f *CFeature) Use(s feature.System) feature.MiddlewareFn {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.URL.Path {
+			case f.healthPath:
+				f.serveReport(false, w, r)
+			case f.readyPath:
+				f.serveReport(true, w, r)
+			default:
+				next.ServeHTTP(w, r)
+			}
+		})
+	}
+}
+
+// serveReport responds with the JSON report detail for authorized requests
+// and a plain text status line for everyone else, using a 503 status when
+// the report is not ok
+func (f *CFeature) serveReport(readiness bool, w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		f.Enjin.Serve405(w, r)
+		return
+	}
+
+	report := f.makeReport(readiness)
+
+	status := http.StatusOK
+	if report.Status != StatusOk {
+		status = http.StatusServiceUnavailable
+	}
+
+	w.Header().Set("Cache-Control", "no-store")
+
+	if f.isAuthorized(r) {
+		if err := f.Enjin.ServeStatusJSON(status, report, w, r); err != nil {
+			log.ErrorRF(r, "error serving health report: %v", err)
+			f.Enjin.Serve500(w, r)
+		}
+		return
+	}
+
+	f.Enjin.ServeData([]byte(report.Status+"\n"), "text/plain; charset=utf-8", w, serve.SetServeStatus(status, r))
+}
+
+// isAuthorized returns true if the request presents the configured detail
+// token or the current user is permitted to view the health report
+func (f *CFeature) isAuthorized(r *http.Request) (allowed bool) {
+	if f.detailToken != "" {
+		if scheme, token, ok := strings.Cut(r.Header.Get("Authorization"), " "); ok && strings.EqualFold(scheme, "Bearer") {
+			if subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(f.detailToken)) == 1 {
+				return true
+			}
+		}
+	}
+	allowed = userbase.CurrentUserCan(r, f.Action("view", "health"))
+	return
+}
@@ -20,7 +20,10 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"net"
 	"net/http"
+	"os"
+	"time"
 
 	"github.com/go-chi/chi/v5"
 	"github.com/urfave/cli/v2"
@@ -28,6 +31,7 @@ import (
 	"github.com/go-enjin/be/pkg/feature"
 	"github.com/go-enjin/be/pkg/globals"
 	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net/handoff"
 )
 
 var (
@@ -39,7 +43,7 @@ const Tag feature.Tag = "srv-listener-httpd"
 
 type Feature interface {
 	feature.Feature
-	feature.ServiceListener
+	feature.GracefulServiceListener
 }
 
 type MakeFeature interface {
@@ -52,7 +56,8 @@ type CFeature struct {
 	port   int
 	listen string
 
-	srv *http.Server
+	srv      *http.Server
+	listener net.Listener
 }
 
 func New() MakeFeature {
@@ -123,6 +128,16 @@ func (f *CFeature) StopListening() (err error) {
 	return
 }
 
+func (f *CFeature) DrainListening(timeout time.Duration) (err error) {
+	err = handoff.Drain(f.srv, timeout)
+	return
+}
+
+func (f *CFeature) ListenerFiles() (files []*os.File, err error) {
+	files, err = handoff.Files(f.listener)
+	return
+}
+
 func (f *CFeature) StartListening(router *chi.Mux, e feature.EnjinRunner) (err error) {
 	e.Notify("http listener starting")
 	log.DebugF("http listener info:\n%v", e.StartupString())
@@ -132,7 +147,16 @@ func (f *CFeature) StartListening(router *chi.Mux, e feature.EnjinRunner) (err e
 		Handler: router,
 	}
 
-	if err = f.srv.ListenAndServe(); !errors.Is(err, http.ErrServerClosed) {
+	var inherited bool
+	if f.listener, inherited, err = handoff.Listen("tcp", f.srv.Addr); err != nil {
+		log.ErrorF("error listening on %v: %v", f.srv.Addr, err)
+		return
+	} else if inherited {
+		log.InfoF("http listener inherited: %v", f.listener.Addr())
+	}
+	e.NotifyReady()
+
+	if err = f.srv.Serve(f.listener); !errors.Is(err, http.ErrServerClosed) {
 		log.ErrorF("unexpected error during http listener startup/shutdown: %v", err)
 		return
 	}
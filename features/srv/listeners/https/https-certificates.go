@@ -0,0 +1,179 @@
+//go:build srv_listener_https || srv_listeners || srv || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package https
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// certificates manages the TLS certificates of the https listener, loaded
+// from either a single cert/key file pair or from a directory of pairs where
+// each certificate file (.crt or .pem) has a matching .key file
+type certificates struct {
+	certFile string
+	keyFile  string
+	certDir  string
+
+	fallback *tls.Certificate
+	byName   map[string]*tls.Certificate
+	stamps   map[string]time.Time
+
+	sync.RWMutex
+}
+
+func newCertificates(certFile, keyFile, certDir string) (c *certificates) {
+	c = &certificates{
+		certFile: certFile,
+		keyFile:  keyFile,
+		certDir:  certDir,
+		byName:   make(map[string]*tls.Certificate),
+		stamps:   make(map[string]time.Time),
+	}
+	return
+}
+
+// pairs returns the list of cert/key file pairs to be loaded
+func (c *certificates) pairs() (pairs [][2]string, err error) {
+	if c.certFile != "" {
+		pairs = append(pairs, [2]string{c.certFile, c.keyFile})
+	}
+	if c.certDir != "" {
+		var entries []os.DirEntry
+		if entries, err = os.ReadDir(c.certDir); err != nil {
+			return
+		}
+		for _, entry := range entries {
+			name := entry.Name()
+			if entry.IsDir() {
+				continue
+			}
+			ext := filepath.Ext(name)
+			if ext != ".crt" && ext != ".pem" {
+				continue
+			}
+			keyFile := filepath.Join(c.certDir, strings.TrimSuffix(name, ext)+".key")
+			if _, ee := os.Stat(keyFile); ee != nil {
+				continue
+			}
+			pairs = append(pairs, [2]string{filepath.Join(c.certDir, name), keyFile})
+		}
+	}
+	return
+}
+
+// modified returns true if any of the certificate files changed since last
+// loaded, or if the list of files changed
+func (c *certificates) modified() (changed bool) {
+	pairs, err := c.pairs()
+	if err != nil {
+		return false
+	}
+	c.RLock()
+	defer c.RUnlock()
+	var count int
+	for _, pair := range pairs {
+		for _, file := range pair {
+			count += 1
+			if info, ee := os.Stat(file); ee != nil {
+				return true
+			} else if stamp, present := c.stamps[file]; !present || !stamp.Equal(info.ModTime()) {
+				return true
+			}
+		}
+	}
+	return count != len(c.stamps)
+}
+
+// load reads all certificates, replacing the current ones only if all are
+// loaded successfully
+func (c *certificates) load() (err error) {
+	var pairs [][2]string
+	if pairs, err = c.pairs(); err != nil {
+		return
+	} else if len(pairs) == 0 {
+		err = fmt.Errorf("no certificates found")
+		return
+	}
+
+	var fallback *tls.Certificate
+	byName := make(map[string]*tls.Certificate)
+	stamps := make(map[string]time.Time)
+
+	for _, pair := range pairs {
+		var cert tls.Certificate
+		if cert, err = tls.LoadX509KeyPair(pair[0], pair[1]); err != nil {
+			err = fmt.Errorf("error loading %v: %w", pair[0], err)
+			return
+		} else if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			err = fmt.Errorf("error parsing %v: %w", pair[0], err)
+			return
+		}
+		if fallback == nil {
+			fallback = &cert
+		}
+		names := cert.Leaf.DNSNames
+		if len(names) == 0 && cert.Leaf.Subject.CommonName != "" {
+			names = append(names, cert.Leaf.Subject.CommonName)
+		}
+		for _, name := range names {
+			byName[strings.ToLower(name)] = &cert
+		}
+		for _, file := range pair {
+			if info, ee := os.Stat(file); ee == nil {
+				stamps[file] = info.ModTime()
+			}
+		}
+	}
+
+	c.Lock()
+	defer c.Unlock()
+	c.fallback = fallback
+	c.byName = byName
+	c.stamps = stamps
+	return
+}
+
+// GetCertificate is the tls.Config.GetCertificate function, selecting the
+// certificate by SNI server name, then by wildcard name and otherwise the
+// first certificate loaded
+func (c *certificates) GetCertificate(hello *tls.ClientHelloInfo) (cert *tls.Certificate, err error) {
+	c.RLock()
+	defer c.RUnlock()
+
+	name := strings.ToLower(strings.TrimSuffix(hello.ServerName, "."))
+	if found, ok := c.byName[name]; ok {
+		cert = found
+		return
+	}
+	if _, parent, ok := strings.Cut(name, "."); ok {
+		if found, ok := c.byName["*."+parent]; ok {
+			cert = found
+			return
+		}
+	}
+	if cert = c.fallback; cert == nil {
+		err = fmt.Errorf("no certificate available for %q", name)
+	}
+	return
+}
@@ -0,0 +1,456 @@
+//go:build srv_listener_https || srv_listeners || srv || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package https
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net/handoff"
+)
+
+var (
+	_ Feature                   = (*CFeature)(nil)
+	_ MakeFeature               = (*CFeature)(nil)
+	_ feature.ReloadableFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "srv-listener-https"
+
+var (
+	DefaultPort           = 443
+	DefaultReloadInterval = time.Minute
+)
+
+type Feature interface {
+	feature.Feature
+	feature.GracefulServiceListener
+}
+
+type MakeFeature interface {
+	Make() Feature
+
+	// SetCertificate configures the certificate and key files to use
+	SetCertificate(certFile, keyFile string) MakeFeature
+	// SetCertificatesPath configures a directory of certificate (.crt or .pem)
+	// and key (.key) file pairs, selected by the SNI server name
+	SetCertificatesPath(path string) MakeFeature
+	// SetReloadInterval configures how often certificate files are checked
+	// for changes, certificates are also reloaded on SIGHUP
+	SetReloadInterval(interval time.Duration) MakeFeature
+	// SetRedirectPort starts a companion plain-HTTP listener which redirects
+	// all requests to this https listener
+	SetRedirectPort(port int) MakeFeature
+	// SetHSTS configures the Strict-Transport-Security response header, a
+	// maxAge of zero disables the header
+	SetHSTS(maxAge time.Duration, includeSubdomains, preload bool) MakeFeature
+}
+
+type CFeature struct {
+	feature.CFeature
+
+	port   int
+	listen string
+
+	certFile string
+	keyFile  string
+	certDir  string
+	interval time.Duration
+
+	redirectPort int
+
+	hstsMaxAge            time.Duration
+	hstsIncludeSubdomains bool
+	hstsPreload           bool
+
+	certs            *certificates
+	srv              *http.Server
+	listener         net.Listener
+	redirect         *http.Server
+	redirectListener net.Listener
+	done             chan struct{}
+	doneOnce         sync.Once
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.port = DefaultPort
+	f.interval = DefaultReloadInterval
+	return
+}
+
+func (f *CFeature) SetCertificate(certFile, keyFile string) MakeFeature {
+	f.certFile = certFile
+	f.keyFile = keyFile
+	return f
+}
+
+func (f *CFeature) SetCertificatesPath(path string) MakeFeature {
+	f.certDir = path
+	return f
+}
+
+func (f *CFeature) SetReloadInterval(interval time.Duration) MakeFeature {
+	f.interval = interval
+	return f
+}
+
+func (f *CFeature) SetRedirectPort(port int) MakeFeature {
+	f.redirectPort = port
+	return f
+}
+
+func (f *CFeature) SetHSTS(maxAge time.Duration, includeSubdomains, preload bool) MakeFeature {
+	f.hstsMaxAge = maxAge
+	f.hstsIncludeSubdomains = includeSubdomains
+	f.hstsPreload = preload
+	return f
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	category := f.Tag().String()
+	b.AddFlags(
+		&cli.StringFlag{
+			Name:     "listen",
+			Usage:    "the address to listen on",
+			Value:    globals.DefaultListen,
+			Aliases:  []string{"L"},
+			EnvVars:  b.MakeEnvKeys("LISTEN"),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     "port",
+			Usage:    "the port to listen on",
+			Value:    f.port,
+			Aliases:  []string{"p"},
+			EnvVars:  append(b.MakeEnvKeys("PORT"), "PORT"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     "tls-cert-file",
+			Usage:    "the TLS certificate file",
+			Value:    f.certFile,
+			EnvVars:  b.MakeEnvKeys("TLS_CERT_FILE"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     "tls-key-file",
+			Usage:    "the TLS private key file",
+			Value:    f.keyFile,
+			EnvVars:  b.MakeEnvKeys("TLS_KEY_FILE"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     "tls-cert-path",
+			Usage:    "a directory of TLS certificate (.crt or .pem) and key (.key) pairs, selected by SNI",
+			Value:    f.certDir,
+			EnvVars:  b.MakeEnvKeys("TLS_CERT_PATH"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     "tls-reload-interval",
+			Usage:    "how often to check TLS certificates for changes, certificates are also reloaded on SIGHUP",
+			Value:    f.interval,
+			EnvVars:  b.MakeEnvKeys("TLS_RELOAD_INTERVAL"),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     "redirect-port",
+			Usage:    "start a plain-HTTP listener on this port, redirecting all requests to https (0 disables)",
+			Value:    f.redirectPort,
+			EnvVars:  b.MakeEnvKeys("REDIRECT_PORT"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     "hsts-max-age",
+			Usage:    "the Strict-Transport-Security max-age (0 disables)",
+			Value:    f.hstsMaxAge,
+			EnvVars:  b.MakeEnvKeys("HSTS_MAX_AGE"),
+			Category: category,
+		},
+		&cli.BoolFlag{
+			Name:     "hsts-include-subdomains",
+			Usage:    "include the Strict-Transport-Security includeSubDomains directive",
+			Value:    f.hstsIncludeSubdomains,
+			EnvVars:  b.MakeEnvKeys("HSTS_INCLUDE_SUBDOMAINS"),
+			Category: category,
+		},
+		&cli.BoolFlag{
+			Name:     "hsts-preload",
+			Usage:    "include the Strict-Transport-Security preload directive",
+			Value:    f.hstsPreload,
+			EnvVars:  b.MakeEnvKeys("HSTS_PRELOAD"),
+			Category: category,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+	f.port = ctx.Int("port")
+	f.listen = ctx.String("listen")
+	f.certFile = ctx.String("tls-cert-file")
+	f.keyFile = ctx.String("tls-key-file")
+	f.certDir = ctx.String("tls-cert-path")
+	f.interval = ctx.Duration("tls-reload-interval")
+	f.redirectPort = ctx.Int("redirect-port")
+	f.hstsMaxAge = ctx.Duration("hsts-max-age")
+	f.hstsIncludeSubdomains = ctx.Bool("hsts-include-subdomains")
+	f.hstsPreload = ctx.Bool("hsts-preload")
+
+	if f.certFile == "" && f.certDir == "" {
+		err = fmt.Errorf("%v requires --tls-cert-file and --tls-key-file, or --tls-cert-path", f.Tag())
+		return
+	} else if f.certFile != "" && f.keyFile == "" {
+		err = fmt.Errorf("%v --tls-cert-file requires --tls-key-file", f.Tag())
+		return
+	}
+
+	f.certs = newCertificates(f.certFile, f.keyFile, f.certDir)
+	if err = f.certs.load(); err != nil {
+		err = fmt.Errorf("%v error loading certificates: %w", f.Tag(), err)
+		return
+	}
+	f.done = make(chan struct{})
+	return
+}
+
+func (f *CFeature) ServiceInfo() (scheme, listen string, port int) {
+	port = f.port
+	listen = f.listen
+	scheme = "https"
+	return
+}
+
+func (f *CFeature) StopListening() (err error) {
+	f.stopWatching()
+	if f.redirect != nil {
+		if ee := f.redirect.Shutdown(context.Background()); ee != nil {
+			log.ErrorF("error shutting down http redirect listener: %v", ee)
+		}
+	}
+	if f.srv != nil {
+		err = f.srv.Shutdown(context.Background())
+	}
+	return
+}
+
+func (f *CFeature) DrainListening(timeout time.Duration) (err error) {
+	f.stopWatching()
+	if f.redirect != nil {
+		if ee := handoff.Drain(f.redirect, timeout); ee != nil {
+			log.ErrorF("error draining http redirect listener: %v", ee)
+		}
+	}
+	err = handoff.Drain(f.srv, timeout)
+	return
+}
+
+func (f *CFeature) ListenerFiles() (files []*os.File, err error) {
+	listeners := []net.Listener{f.listener}
+	if f.redirectListener != nil {
+		listeners = append(listeners, f.redirectListener)
+	}
+	files, err = handoff.Files(listeners...)
+	return
+}
+
+func (f *CFeature) StartListening(router *chi.Mux, e feature.EnjinRunner) (err error) {
+	e.Notify("https listener starting")
+	log.DebugF("https listener info:\n%v", e.StartupString())
+
+	go f.watchCertificates(f.done)
+
+	if f.redirectPort > 0 {
+		f.redirect = &http.Server{
+			Addr:              fmt.Sprintf("%s:%d", f.listen, f.redirectPort),
+			Handler:           http.HandlerFunc(f.serveRedirect),
+			ReadHeaderTimeout: 10 * time.Second,
+		}
+		if f.redirectListener, _, err = handoff.Listen("tcp", f.redirect.Addr); err != nil {
+			log.ErrorF("error listening on %v: %v", f.redirect.Addr, err)
+			return
+		}
+		go func() {
+			if ee := f.redirect.Serve(f.redirectListener); !errors.Is(ee, http.ErrServerClosed) {
+				log.ErrorF("unexpected error during http redirect listener startup/shutdown: %v", ee)
+			}
+		}()
+	}
+
+	f.srv = &http.Server{
+		Addr:      fmt.Sprintf("%s:%d", f.listen, f.port),
+		Handler:   f.hstsHandler(router),
+		TLSConfig: f.makeTLSConfig(),
+	}
+
+	var inherited bool
+	if f.listener, inherited, err = handoff.Listen("tcp", f.srv.Addr); err != nil {
+		log.ErrorF("error listening on %v: %v", f.srv.Addr, err)
+		return
+	} else if inherited {
+		log.InfoF("https listener inherited: %v", f.listener.Addr())
+	}
+	e.NotifyReady()
+
+	if err = f.srv.ServeTLS(f.listener, "", ""); !errors.Is(err, http.ErrServerClosed) {
+		log.ErrorF("unexpected error during https listener startup/shutdown: %v", err)
+		return
+	}
+	return
+}
+
+// makeTLSConfig returns the TLS configuration with modern defaults and HTTP/2
+// enabled
+func (f *CFeature) makeTLSConfig() (config *tls.Config) {
+	config = &tls.Config{
+		MinVersion:       tls.VersionTLS12,
+		GetCertificate:   f.certs.GetCertificate,
+		NextProtos:       []string{"h2", "http/1.1"},
+		CurvePreferences: []tls.CurveID{tls.X25519, tls.CurveP256},
+		CipherSuites: []uint16{
+			// TLS 1.3 cipher suites are not configurable and always enabled
+			tls.TLS_ECDHE_ECDSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_AES_128_GCM_SHA256,
+			tls.TLS_ECDHE_ECDSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_RSA_WITH_AES_256_GCM_SHA384,
+			tls.TLS_ECDHE_ECDSA_WITH_CHACHA20_POLY1305_SHA256,
+			tls.TLS_ECDHE_RSA_WITH_CHACHA20_POLY1305_SHA256,
+		},
+	}
+	return
+}
+
+// stopWatching ends the watchCertificates goroutine, safe to call more than
+// once and from any goroutine
+func (f *CFeature) stopWatching() {
+	f.doneOnce.Do(func() {
+		if f.done != nil {
+			close(f.done)
+		}
+	})
+}
+
+// Reload is called when the enjin receives a SIGHUP, new certificates are used
+// for new connections without dropping existing ones
+func (f *CFeature) Reload() {
+	if f.certs != nil {
+		f.reloadCertificates("SIGHUP")
+	}
+}
+
+func (f *CFeature) reloadCertificates(reason string) {
+	if err := f.certs.load(); err != nil {
+		log.ErrorF("%v error reloading certificates (%v), keeping current certificates: %v", f.Tag(), reason, err)
+		return
+	}
+	log.InfoF("%v certificates reloaded (%v)", f.Tag(), reason)
+}
+
+// watchCertificates reloads the certificates when changed, new certificates
+// are used for new connections without dropping existing ones
+func (f *CFeature) watchCertificates(done chan struct{}) {
+	var tick <-chan time.Time
+	if f.interval > 0 {
+		ticker := time.NewTicker(f.interval)
+		defer ticker.Stop()
+		tick = ticker.C
+	}
+
+	for {
+		select {
+		case <-done:
+			return
+		case <-tick:
+			if f.certs.modified() {
+				f.reloadCertificates("modified")
+			}
+		}
+	}
+}
+
+func (f *CFeature) hstsHandler(next http.Handler) (handler http.Handler) {
+	if f.hstsMaxAge <= 0 {
+		return next
+	}
+	value := "max-age=" + strconv.FormatInt(int64(f.hstsMaxAge.Seconds()), 10)
+	if f.hstsIncludeSubdomains {
+		value += "; includeSubDomains"
+	}
+	if f.hstsPreload {
+		value += "; preload"
+	}
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Strict-Transport-Security", value)
+		next.ServeHTTP(w, r)
+	})
+}
+
+func (f *CFeature) serveRedirect(w http.ResponseWriter, r *http.Request) {
+	host := r.Host
+	if host == "" {
+		http.Error(w, http.StatusText(http.StatusBadRequest), http.StatusBadRequest)
+		return
+	}
+	if h, _, err := net.SplitHostPort(host); err == nil {
+		host = h
+	}
+	if f.port != 443 {
+		host = net.JoinHostPort(host, strconv.Itoa(f.port))
+	}
+	target := "https://" + host + r.URL.RequestURI()
+
+	status := http.StatusPermanentRedirect
+	if r.Method == http.MethodGet || r.Method == http.MethodHead {
+		status = http.StatusMovedPermanently
+	}
+	http.Redirect(w, r, target, status)
+}
@@ -257,6 +257,7 @@ func (f *CFeature) StartListening(router *chi.Mux, e feature.EnjinRunner) (err e
 		return
 	}
 	log.DebugF("ngrok listener info:\n%v", e.StartupString())
+	e.NotifyReady()
 
 	if err = http.Serve(f.tunnel, router); err != nil {
 		if !strings.Contains(err.Error(), "Listener closed") {
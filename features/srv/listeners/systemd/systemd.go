@@ -0,0 +1,233 @@
+//go:build srv_listener_systemd || srv_listeners || srv || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package systemd
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net/handoff"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "srv-listener-systemd"
+
+const (
+	// gListenFdsStart is the first file descriptor passed by systemd
+	gListenFdsStart = 3
+)
+
+type Feature interface {
+	feature.Feature
+	feature.GracefulServiceListener
+}
+
+type MakeFeature interface {
+	Make() Feature
+}
+
+type CFeature struct {
+	feature.CFeature
+
+	listeners []net.Listener
+
+	srv *http.Server
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	return
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+	if inherited, ee := handoff.Inherited(); ee == nil && len(inherited) > 0 {
+		// restarted by a previous process which was socket-activated
+		f.listeners = inherited
+	} else if f.listeners, err = activationListeners(); err != nil {
+		err = fmt.Errorf("%v error: %w", f.Tag(), err)
+		return
+	}
+	for _, l := range f.listeners {
+		log.DebugF("%v received listener: %v://%v", f.Tag(), l.Addr().Network(), l.Addr().String())
+	}
+	return
+}
+
+// ServiceInfo reports the first listener received, tcp listeners use the
+// "http" scheme with the listen address and port while unix socket listeners
+// use the "unix" scheme with the socket path and a port of zero
+func (f *CFeature) ServiceInfo() (scheme, listen string, port int) {
+	scheme, port = "systemd", -1
+	if len(f.listeners) == 0 {
+		return
+	}
+	addr := f.listeners[0].Addr()
+	switch addr.Network() {
+	case "unix":
+		scheme, listen, port = "unix", addr.String(), 0
+	default:
+		scheme = "http"
+		if host, p, err := net.SplitHostPort(addr.String()); err == nil {
+			listen = host
+			port, _ = strconv.Atoi(p)
+		} else {
+			listen = addr.String()
+		}
+	}
+	return
+}
+
+func (f *CFeature) StopListening() (err error) {
+	if f.srv != nil {
+		err = f.srv.Shutdown(context.Background())
+	}
+	return
+}
+
+func (f *CFeature) DrainListening(timeout time.Duration) (err error) {
+	err = handoff.Drain(f.srv, timeout)
+	return
+}
+
+func (f *CFeature) ListenerFiles() (files []*os.File, err error) {
+	for _, l := range f.listeners {
+		if ul, ok := l.(*net.UnixListener); ok {
+			ul.SetUnlinkOnClose(false)
+		}
+	}
+	files, err = handoff.Files(f.listeners...)
+	return
+}
+
+func (f *CFeature) StartListening(router *chi.Mux, e feature.EnjinRunner) (err error) {
+	e.Notify("systemd socket-activation listener starting")
+	log.DebugF("systemd listener info:\n%v", e.StartupString())
+
+	f.srv = &http.Server{
+		Handler: router,
+	}
+
+	var wg sync.WaitGroup
+	errs := make([]error, len(f.listeners))
+	for idx, l := range f.listeners {
+		wg.Add(1)
+		go func(idx int, l net.Listener) {
+			defer wg.Done()
+			if ee := f.srv.Serve(l); !errors.Is(ee, http.ErrServerClosed) {
+				log.ErrorF("unexpected error during systemd listener %v startup/shutdown: %v", l.Addr(), ee)
+				errs[idx] = ee
+				// one failed listener stops them all
+				_ = f.srv.Close()
+			}
+		}(idx, l)
+	}
+	// the activation listeners were received during startup
+	e.NotifyReady()
+	wg.Wait()
+
+	if err = errors.Join(errs...); err == nil {
+		err = http.ErrServerClosed
+	}
+	return
+}
+
+// activationListeners returns the listeners passed by systemd socket
+// activation, as described by sd_listen_fds(3)
+func activationListeners() (listeners []net.Listener, err error) {
+	defer func() {
+		// prevent child processes from inheriting the activation
+		_ = os.Unsetenv("LISTEN_PID")
+		_ = os.Unsetenv("LISTEN_FDS")
+		_ = os.Unsetenv("LISTEN_FDNAMES")
+	}()
+
+	if pid, ee := strconv.Atoi(os.Getenv("LISTEN_PID")); ee != nil || pid != os.Getpid() {
+		err = fmt.Errorf("LISTEN_PID is not set for this process, not started with systemd socket activation")
+		return
+	}
+
+	var count int
+	if count, err = strconv.Atoi(os.Getenv("LISTEN_FDS")); err != nil || count <= 0 {
+		err = fmt.Errorf("LISTEN_FDS is not a positive number: %q", os.Getenv("LISTEN_FDS"))
+		return
+	}
+
+	names := strings.Split(os.Getenv("LISTEN_FDNAMES"), ":")
+	for idx := 0; idx < count; idx++ {
+		fd := gListenFdsStart + idx
+		name := "LISTEN_FD_" + strconv.Itoa(fd)
+		if idx < len(names) && names[idx] != "" {
+			name = names[idx]
+		}
+		file := os.NewFile(uintptr(fd), name)
+		var l net.Listener
+		l, err = net.FileListener(file)
+		// FileListener duplicates the descriptor
+		_ = file.Close()
+		if err != nil {
+			err = fmt.Errorf("error using %v file descriptor %d: %w", name, fd, err)
+			for _, previous := range listeners {
+				_ = previous.Close()
+			}
+			listeners = nil
+			return
+		}
+		listeners = append(listeners, l)
+	}
+	return
+}
@@ -0,0 +1,289 @@
+//go:build srv_listener_unix || srv_listeners || srv || all
+
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package unix
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io/fs"
+	"net"
+	"net/http"
+	"os"
+	"os/user"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/net/handoff"
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+const Tag feature.Tag = "srv-listener-unix"
+
+var (
+	DefaultSocketPath = "enjin.sock"
+	DefaultSocketMode = "0660"
+)
+
+type Feature interface {
+	feature.Feature
+	feature.GracefulServiceListener
+}
+
+type MakeFeature interface {
+	Make() Feature
+
+	// SetSocket configures the socket path, file mode and owner, the owner is
+	// in the form of "user", "user:group" or ":group" and may be empty
+	SetSocket(path string, mode fs.FileMode, owner string) MakeFeature
+}
+
+type CFeature struct {
+	feature.CFeature
+
+	path  string
+	mode  fs.FileMode
+	owner string
+
+	srv      *http.Server
+	listener net.Listener
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.path = DefaultSocketPath
+	f.mode, _ = parseFileMode(DefaultSocketMode)
+	return
+}
+
+func (f *CFeature) SetSocket(path string, mode fs.FileMode, owner string) MakeFeature {
+	f.path = path
+	f.mode = mode
+	f.owner = owner
+	return f
+}
+
+func (f *CFeature) Make() (feat Feature) {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	category := f.Tag().String()
+	b.AddFlags(
+		&cli.StringFlag{
+			Name:     "unix-socket",
+			Usage:    "the unix domain socket path to listen on",
+			Value:    f.path,
+			EnvVars:  b.MakeEnvKeys("UNIX_SOCKET"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     "unix-socket-mode",
+			Usage:    "the octal file mode of the unix domain socket",
+			Value:    fmt.Sprintf("%04o", f.mode.Perm()),
+			EnvVars:  b.MakeEnvKeys("UNIX_SOCKET_MODE"),
+			Category: category,
+		},
+		&cli.StringFlag{
+			Name:     "unix-socket-owner",
+			Usage:    "the owner of the unix domain socket: user, user:group or :group",
+			Value:    f.owner,
+			EnvVars:  b.MakeEnvKeys("UNIX_SOCKET_OWNER"),
+			Category: category,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+	if f.path = ctx.String("unix-socket"); f.path == "" {
+		err = fmt.Errorf("%v requires a --unix-socket path", f.Tag())
+		return
+	}
+	if f.mode, err = parseFileMode(ctx.String("unix-socket-mode")); err != nil {
+		err = fmt.Errorf("%v invalid --unix-socket-mode: %w", f.Tag(), err)
+		return
+	}
+	f.owner = ctx.String("unix-socket-owner")
+	if _, _, err = lookupOwner(f.owner); err != nil {
+		err = fmt.Errorf("%v invalid --unix-socket-owner: %w", f.Tag(), err)
+		return
+	}
+	return
+}
+
+func (f *CFeature) ServiceInfo() (scheme, listen string, port int) {
+	scheme = "unix"
+	listen = f.path
+	port = 0
+	return
+}
+
+func (f *CFeature) StopListening() (err error) {
+	if f.srv != nil {
+		err = f.srv.Shutdown(context.Background())
+	}
+	return
+}
+
+func (f *CFeature) DrainListening(timeout time.Duration) (err error) {
+	err = handoff.Drain(f.srv, timeout)
+	return
+}
+
+func (f *CFeature) ListenerFiles() (files []*os.File, err error) {
+	if ul, ok := f.listener.(*net.UnixListener); ok {
+		// the new process is using the socket path now
+		ul.SetUnlinkOnClose(false)
+	}
+	files, err = handoff.Files(f.listener)
+	return
+}
+
+func (f *CFeature) StartListening(router *chi.Mux, e feature.EnjinRunner) (err error) {
+	e.Notify("unix socket listener starting")
+	log.DebugF("unix socket listener info:\n%v", e.StartupString())
+
+	if f.listener, err = handoff.Inherit("unix", f.path); err != nil {
+		return
+	} else if f.listener != nil {
+		log.InfoF("unix socket listener inherited: %v", f.path)
+	} else if err = removeStaleSocket(f.path); err != nil {
+		return
+	} else if f.listener, err = net.Listen("unix", f.path); err != nil {
+		err = fmt.Errorf("error listening on unix socket %v: %w", f.path, err)
+		return
+	} else if err = f.prepareSocket(); err != nil {
+		_ = f.listener.Close()
+		return
+	}
+
+	e.NotifyReady()
+
+	f.srv = &http.Server{
+		Handler: router,
+	}
+
+	if err = f.srv.Serve(f.listener); !errors.Is(err, http.ErrServerClosed) {
+		log.ErrorF("unexpected error during unix socket listener startup/shutdown: %v", err)
+		return
+	}
+	return
+}
+
+func (f *CFeature) prepareSocket() (err error) {
+	if err = os.Chmod(f.path, f.mode); err != nil {
+		err = fmt.Errorf("error setting unix socket mode: %w", err)
+		return
+	}
+	if f.owner != "" {
+		uid, gid, _ := lookupOwner(f.owner)
+		if err = os.Chown(f.path, uid, gid); err != nil {
+			err = fmt.Errorf("error setting unix socket owner: %w", err)
+			return
+		}
+	}
+	return
+}
+
+func parseFileMode(value string) (mode fs.FileMode, err error) {
+	var parsed uint64
+	if parsed, err = strconv.ParseUint(strings.TrimSpace(value), 8, 32); err != nil {
+		return
+	}
+	mode = fs.FileMode(parsed).Perm()
+	return
+}
+
+// lookupOwner parses "user", "user:group" or ":group" values into numeric
+// ids, using -1 for ids not given
+func lookupOwner(owner string) (uid, gid int, err error) {
+	uid, gid = -1, -1
+	if owner = strings.TrimSpace(owner); owner == "" {
+		return
+	}
+
+	userName, groupName, _ := strings.Cut(owner, ":")
+
+	if userName != "" {
+		var u *user.User
+		if u, err = user.Lookup(userName); err != nil {
+			if u, err = user.LookupId(userName); err != nil {
+				return
+			}
+		}
+		if uid, err = strconv.Atoi(u.Uid); err != nil {
+			return
+		}
+	}
+
+	if groupName != "" {
+		var g *user.Group
+		if g, err = user.LookupGroup(groupName); err != nil {
+			if g, err = user.LookupGroupId(groupName); err != nil {
+				return
+			}
+		}
+		if gid, err = strconv.Atoi(g.Gid); err != nil {
+			return
+		}
+	}
+	return
+}
+
+// removeStaleSocket removes a socket file left behind by a previous process,
+// refusing to remove anything which is not a socket
+func removeStaleSocket(path string) (err error) {
+	var info os.FileInfo
+	if info, err = os.Lstat(path); err != nil {
+		if os.IsNotExist(err) {
+			err = nil
+		}
+		return
+	} else if info.Mode()&fs.ModeSocket == 0 {
+		err = fmt.Errorf("%v exists and is not a unix socket", path)
+		return
+	}
+	err = os.Remove(path)
+	return
+}
@@ -1,18 +1,23 @@
 package logger
 
 import (
+	"fmt"
+	"io"
+
 	"github.com/urfave/cli/v2"
 
 	"github.com/go-enjin/be/pkg/feature"
 	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/log/filelogwriter"
 )
 
 // TODO: rewrite gorilla-handlers functions into more formal implementation
 // TODO: consider using something like https://github.com/lestrrat-go/apache-logformat
 
 var (
-	_ Feature     = (*CFeature)(nil)
-	_ MakeFeature = (*CFeature)(nil)
+	_ Feature                   = (*CFeature)(nil)
+	_ MakeFeature               = (*CFeature)(nil)
+	_ feature.ReloadableFeature = (*CFeature)(nil)
 )
 
 const Tag feature.Tag = "srv-logging-logger"
@@ -32,6 +37,8 @@ type CFeature struct {
 	feature.CFeature
 
 	combined bool
+
+	writer *filelogwriter.FileLogWriter
 }
 
 func New() MakeFeature {
@@ -65,6 +72,45 @@ func (f *CFeature) Build(b feature.Buildable) (err error) {
 	if err = f.CFeature.Build(b); err != nil {
 		return
 	}
+	category := f.KebabTag
+	b.AddFlags(
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-file",
+			Usage:    "write access log entries to the given file instead of the main log",
+			EnvVars:  b.MakeEnvKeys(f.Tag().ScreamingSnake(), "FILE"),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     f.KebabTag + "-file-max-size",
+			Usage:    "rotate the access log file when larger than this many megabytes",
+			EnvVars:  b.MakeEnvKeys(f.Tag().ScreamingSnake(), "FILE_MAX_SIZE"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     f.KebabTag + "-file-rotate",
+			Usage:    "rotate the access log file at this interval (ie: 24h)",
+			EnvVars:  b.MakeEnvKeys(f.Tag().ScreamingSnake(), "FILE_ROTATE"),
+			Category: category,
+		},
+		&cli.IntFlag{
+			Name:     f.KebabTag + "-file-max-backups",
+			Usage:    "maximum number of rotated access log files to keep",
+			EnvVars:  b.MakeEnvKeys(f.Tag().ScreamingSnake(), "FILE_MAX_BACKUPS"),
+			Category: category,
+		},
+		&cli.DurationFlag{
+			Name:     f.KebabTag + "-file-max-age",
+			Usage:    "maximum age of rotated access log files to keep",
+			EnvVars:  b.MakeEnvKeys(f.Tag().ScreamingSnake(), "FILE_MAX_AGE"),
+			Category: category,
+		},
+		&cli.BoolFlag{
+			Name:     f.KebabTag + "-file-compress",
+			Usage:    "gzip compress rotated access log files",
+			EnvVars:  b.MakeEnvKeys(f.Tag().ScreamingSnake(), "FILE_COMPRESS"),
+			Category: category,
+		},
+	)
 	return
 }
 
@@ -72,14 +118,46 @@ func (f *CFeature) Startup(ctx *cli.Context) (err error) {
 	if err = f.CFeature.Startup(ctx); err != nil {
 		return
 	}
+
+	if file := ctx.String(f.KebabTag + "-file"); file != "" {
+		if f.writer, err = filelogwriter.NewFileLogWriter(file); err != nil {
+			err = fmt.Errorf("%v error setting up access log file: %v", f.Tag(), err)
+			return
+		}
+		f.writer.SetMaxSize(int64(ctx.Int(f.KebabTag+"-file-max-size")) * 1024 * 1024).
+			SetRotateInterval(ctx.Duration(f.KebabTag + "-file-rotate")).
+			SetMaxBackups(ctx.Int(f.KebabTag + "-file-max-backups")).
+			SetMaxAge(ctx.Duration(f.KebabTag + "-file-max-age")).
+			SetCompress(ctx.Bool(f.KebabTag + "-file-compress"))
+		log.DebugF("%v writing access log to: %v", f.Tag(), file)
+	}
 	return
 }
 
+// Reload is called when the enjin receives a SIGHUP, allowing external tools
+// (like logrotate) to manage the access log file
+func (f *CFeature) Reload() {
+	if f.writer != nil {
+		f.writer.Reopen()
+	}
+}
+
 func (f *CFeature) RequestLogger(ctx feature.LoggerContext) (err error) {
+	var writer io.Writer // avoid a typed nil *FileLogWriter
+	if f.writer != nil {
+		writer = f.writer
+	}
+	if log.Structured() {
+		writeStructuredLog(writer, ctx, f.combined)
+		return
+	}
+	if writer == nil {
+		writer = log.InfoWriter()
+	}
 	if f.combined {
-		writeCombinedLog(log.InfoWriter(), ctx)
+		writeCombinedLog(writer, ctx)
 	} else {
-		writeLog(log.InfoWriter(), ctx)
+		writeLog(writer, ctx)
 	}
 	return
 }
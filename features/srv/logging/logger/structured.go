@@ -0,0 +1,87 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package logger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net"
+	"time"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/request"
+)
+
+// writeStructuredLog emits the access log entry with the standard structured
+// request fields, used when the JSON logging format is configured. When writer
+// is not nil, the entry is written there as a JSON line instead of the main log
+func writeStructuredLog(writer io.Writer, params feature.LoggerContext, combined bool) {
+	req := params.Request()
+	url := params.URL()
+
+	uri := req.RequestURI
+	if req.ProtoMajor == 2 && req.Method == "CONNECT" {
+		uri = req.Host
+	}
+	if uri == "" {
+		uri = url.RequestURI()
+	}
+
+	entry := log.With(
+		"log", "access",
+		"enjin", request.GetEnjinID(req),
+		"host", req.Host,
+		"method", req.Method,
+		"uri", uri,
+		"proto", req.Proto,
+		"status", params.StatusCode(),
+		"size", params.Size(),
+		"start", params.TimeStamp().Format(time.RFC3339Nano),
+		"duration_ms", float64(params.Duration().Microseconds())/1000.0,
+	)
+
+	if host, _, err := net.SplitHostPort(req.RemoteAddr); err == nil {
+		entry = entry.With("peer_ip", host)
+	}
+	if url.User != nil {
+		if name := url.User.Username(); name != "" {
+			entry = entry.With("username", name)
+		}
+	}
+	if combined {
+		entry = entry.With(
+			"referer", req.Referer(),
+			"user_agent", req.UserAgent(),
+		)
+	}
+
+	if writer == nil {
+		entry.InfoRF(req, "%s %s %s %d", req.Method, uri, req.Proto, params.StatusCode())
+		return
+	}
+
+	fields := entry.Fields()
+	for k, v := range log.RequestFields(req) {
+		fields[k] = v
+	}
+	fields["level"] = "info"
+	fields["time"] = time.Now().Format(time.RFC3339Nano)
+	fields["msg"] = fmt.Sprintf("%s %s %s %d", req.Method, uri, req.Proto, params.StatusCode())
+	if data, err := json.Marshal(fields); err == nil {
+		_, _ = writer.Write(append(data, '\n'))
+	}
+}
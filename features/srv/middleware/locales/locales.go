@@ -26,6 +26,7 @@ import (
 
 	"github.com/go-enjin/be/pkg/feature"
 	"github.com/go-enjin/be/pkg/forms"
+	"github.com/go-enjin/be/pkg/lang"
 	"github.com/go-enjin/be/pkg/log"
 	"github.com/go-enjin/be/pkg/request/argv"
 )
@@ -96,7 +97,28 @@ func (f *CFeature) LocaleHandler(next http.Handler) (this http.Handler) {
 		var requested language.Tag
 
 		var reqOk bool
-		if requested, reqPath, reqOk = langMode.FromRequest(defaultTag, r); !reqOk {
+		if negotiating, ok := langMode.(lang.NegotiatingMode); ok {
+			// the response varies with the visitor's language preferences
+			w.Header().Add("Vary", "Accept-Language")
+			w.Header().Add("Vary", "Cookie")
+			negotiated := negotiating.Negotiate(defaultTag, f.Enjin.SiteLocales(), r)
+			if negotiated.Explicit {
+				// only set the cookie when the choice changed, not on every
+				// request for a language prefixed path
+				cookie := negotiating.MakeCookie(negotiated.Tag, r)
+				if existing, err := r.Cookie(cookie.Name); err != nil || existing.Value != cookie.Value {
+					http.SetCookie(w, cookie)
+				}
+			}
+			if negotiated.Redirect != "" {
+				log.DebugRF(r, "redirecting to negotiated %v language: %v", negotiated.Tag, negotiated.Redirect)
+				f.Enjin.ServeRedirect(negotiated.Redirect, w, r)
+				return
+			}
+			requested, reqPath = negotiated.Tag, negotiated.Path
+			// site features persist explicit choices, see lang.GetNegotiation
+			r = lang.SetNegotiation(r, negotiated)
+		} else if requested, reqPath, reqOk = langMode.FromRequest(defaultTag, r); !reqOk {
 			log.WarnRF(r, "language mode rejecting request: %#v", r)
 			f.Enjin.Serve404(w, r) // specifically not ServeNotFound()
 			return
@@ -106,17 +128,6 @@ func (f *CFeature) LocaleHandler(next http.Handler) (this http.Handler) {
 			reqPath = urlPath
 		}
 
-		// TODO: determine what to do with Accept-Language request headers
-		// if acceptLanguage := r.Header.Get("Accept-Language"); acceptLanguage != "" {
-		// 	if parsed, err := language.Parse(acceptLanguage); err == nil {
-		// 		if e.SiteSupportsLanguage(parsed) && !language.Compare(requested, parsed) {
-		// 			// requested = parsed
-		// 			// e.ServeRedirect(langMode.ToUrl(requested, parsed, reqPath), w, r)
-		// 			// return
-		// 		}
-		// 	}
-		// }
-
 		if v, ok := message.GetLanguageTag(r); ok {
 			// a request modifier feature is specifying the user's language
 			requested = v
@@ -126,6 +137,7 @@ func (f *CFeature) LocaleHandler(next http.Handler) (this http.Handler) {
 		r = message.SetTag(r, tag)
 		r = message.SetDefaultTag(r, f.Enjin.SiteDefaultLanguage())
 		r = message.SetPrinter(r, printer)
+		log.RecordRequestFields(r, log.FieldLanguage, tag.String())
 
 		if reqPath == "" {
 			reqPath = "/"
@@ -22,6 +22,7 @@ import (
 
 	"github.com/urfave/cli/v2"
 
+	"github.com/go-corelibs/x-text/language"
 	"github.com/go-corelibs/x-text/message"
 	beContext "github.com/go-enjin/be/pkg/context"
 	"github.com/go-enjin/be/pkg/feature"
@@ -31,6 +32,7 @@ import (
 	"github.com/go-enjin/be/pkg/net/serve"
 	"github.com/go-enjin/be/pkg/request"
 	"github.com/go-enjin/be/pkg/request/argv"
+	"github.com/go-enjin/be/pkg/trace"
 )
 
 var (
@@ -81,6 +83,7 @@ func (f *CFeature) Build(b feature.Buildable) (err error) {
 }
 
 func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	err = f.CFeature.Startup(ctx)
 	return
 }
 
@@ -88,15 +91,19 @@ func (f *CFeature) RoutePage(w http.ResponseWriter, r *http.Request) {
 	path := r.URL.Path
 	tag := message.GetTag(r)
 
-	// look for any page provider providing the requested page
-	for _, pp := range feature.FilterTyped[feature.PageProvider](f.Enjin.Features().List()) {
-		if pg := pp.FindPage(r, tag, path); pg != nil {
-			if err := f.Enjin.ServePage(pg, w, r); err == nil {
-				log.DebugRF(r, "enjin router served provided page: %v", pg.Url())
-				f.Enjin.Emit(signaling.SignalServePage, pp.Tag().String(), pg)
-				return
-			} else {
-				log.ErrorRF(r, "error serving provided page: %v - %v", pg.Url(), err)
+	// look for any page provider providing the requested page, in the
+	// requested language first and then in any of its fallback languages
+	chain := append([]language.Tag{tag}, f.Enjin.SiteLanguageFallbacks(tag)...)
+	for _, chained := range chain {
+		for _, pp := range feature.FilterTyped[feature.PageProvider](f.Enjin.Features().List()) {
+			if pg := pp.FindPage(r, chained, path); pg != nil {
+				if err := f.Enjin.ServePage(pg, w, r); err == nil {
+					log.DebugRF(r, "enjin router served provided page: [%v] %v", chained, pg.Url())
+					f.Enjin.Emit(signaling.SignalServePage, pp.Tag().String(), pg)
+					return
+				} else {
+					log.ErrorRF(r, "error serving provided page: %v - %v", pg.Url(), err)
+				}
 			}
 		}
 	}
@@ -155,7 +162,10 @@ func (f *CFeature) ServePage(p feature.Page, t feature.Theme, ctx beContext.Cont
 	ctx.SetSpecific(argv.RequestKey.String(), argv.Get(r))
 
 	for _, pspf := range f.Enjin.GetPrepareServePagesFeatures() {
-		if out, modified, handled := pspf.PrepareServePage(ctx, t, p, w, r); handled {
+		_, span := trace.StartRequestChild(r, "prepare-serve-page "+pspf.Tag().String())
+		out, modified, handled := pspf.PrepareServePage(ctx, t, p, w, r)
+		span.End()
+		if handled {
 			log.DebugF("%v feature handled serve page early", pspf.Tag())
 			return
 		} else {
@@ -173,7 +183,12 @@ func (f *CFeature) ServePage(p feature.Page, t feature.Theme, ctx beContext.Cont
 
 	renderer := f.Enjin.GetThemeRenderer(ctx)
 
-	if data, redirect, err = renderer.RenderPage(t, ctx, p); err != nil {
+	_, span := trace.StartRequestChild(r, "render-page", "page.url", pUrl, "page.format", p.Format())
+	data, redirect, err = renderer.RenderPage(t, ctx, p)
+	span.SetError(err)
+	span.End()
+
+	if err != nil {
 		log.ErrorRF(r, "error rendering page: %v - %v", pUrl, err)
 		return
 	} else if redirect != "" {
@@ -0,0 +1,265 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package tracing
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/urfave/cli/v2"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/globals"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/trace"
+)
+
+const Tag feature.Tag = "srv-tracing"
+
+const (
+	ExporterOtlp = "otlp"
+	ExporterFile = "file"
+)
+
+const (
+	DefaultExporter    = ExporterOtlp
+	DefaultSampleRatio = 1.0
+)
+
+var (
+	_ Feature     = (*CFeature)(nil)
+	_ MakeFeature = (*CFeature)(nil)
+)
+
+type Feature interface {
+	feature.Feature
+}
+
+type MakeFeature interface {
+	Make() Feature
+
+	// SetOtlpExporter configures exporting spans to the given OTLP/HTTP
+	// collector traces endpoint, the default is trace.DefaultOtlpEndpoint
+	SetOtlpExporter(endpoint string) MakeFeature
+	// SetFileExporter configures exporting spans as OTLP/JSON lines appended
+	// to the given file path
+	SetFileExporter(path string) MakeFeature
+	// SetServiceName overrides the OTLP service.name resource attribute,
+	// the default is the enjin site tag
+	SetServiceName(name string) MakeFeature
+	// SetSampleRatio sets the fraction of new traces recorded
+	SetSampleRatio(ratio float64) MakeFeature
+	// SetDetached enables spans for driver calls and signals which happen
+	// outside of a request context
+	SetDetached(enabled bool) MakeFeature
+}
+
+type CFeature struct {
+	feature.CFeature
+
+	exporter     string
+	otlpEndpoint string
+	otlpHeaders  map[string]string
+	filePath     string
+	serviceName  string
+	sampleRatio  float64
+	detached     bool
+}
+
+func New() MakeFeature {
+	return NewTagged(Tag)
+}
+
+func NewTagged(tag feature.Tag) MakeFeature {
+	f := new(CFeature)
+	f.Init(f)
+	f.PackageTag = Tag
+	f.FeatureTag = tag
+	f.CFeature.Construct(f)
+	return f
+}
+
+func (f *CFeature) Init(this interface{}) {
+	f.CFeature.Init(this)
+	f.exporter = DefaultExporter
+	f.otlpEndpoint = trace.DefaultOtlpEndpoint
+	f.otlpHeaders = make(map[string]string)
+	f.sampleRatio = DefaultSampleRatio
+}
+
+func (f *CFeature) SetOtlpExporter(endpoint string) MakeFeature {
+	f.exporter = ExporterOtlp
+	f.otlpEndpoint = endpoint
+	return f
+}
+
+func (f *CFeature) SetFileExporter(path string) MakeFeature {
+	f.exporter = ExporterFile
+	f.filePath = path
+	return f
+}
+
+func (f *CFeature) SetServiceName(name string) MakeFeature {
+	f.serviceName = name
+	return f
+}
+
+func (f *CFeature) SetSampleRatio(ratio float64) MakeFeature {
+	f.sampleRatio = ratio
+	return f
+}
+
+func (f *CFeature) SetDetached(enabled bool) MakeFeature {
+	f.detached = enabled
+	return f
+}
+
+func (f *CFeature) Make() Feature {
+	return f
+}
+
+func (f *CFeature) Build(b feature.Buildable) (err error) {
+	envPrefix := f.Tag().ScreamingSnake()
+	b.AddFlags(
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-exporter",
+			Usage:    "span exporter to use: " + ExporterOtlp + " or " + ExporterFile,
+			Value:    f.exporter,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "EXPORTER"),
+			Category: f.KebabTag,
+		},
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-otlp-endpoint",
+			Usage:    "OTLP/HTTP collector traces endpoint",
+			Value:    f.otlpEndpoint,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "OTLP_ENDPOINT"),
+			Category: f.KebabTag,
+		},
+		&cli.StringSliceFlag{
+			Name:     f.KebabTag + "-otlp-header",
+			Usage:    "additional OTLP/HTTP request header, in key=value form",
+			EnvVars:  b.MakeEnvKeys(envPrefix, "OTLP_HEADER"),
+			Category: f.KebabTag,
+		},
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-file-path",
+			Usage:    "file to append OTLP/JSON lines to when using the file exporter",
+			Value:    f.filePath,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "FILE_PATH"),
+			Category: f.KebabTag,
+		},
+		&cli.StringFlag{
+			Name:     f.KebabTag + "-service-name",
+			Usage:    "OTLP service.name resource attribute, defaults to the site tag",
+			Value:    f.serviceName,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "SERVICE_NAME"),
+			Category: f.KebabTag,
+		},
+		&cli.Float64Flag{
+			Name:     f.KebabTag + "-sample-ratio",
+			Usage:    "fraction of new traces to record, from 0.0 to 1.0",
+			Value:    f.sampleRatio,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "SAMPLE_RATIO"),
+			Category: f.KebabTag,
+		},
+		&cli.BoolFlag{
+			Name:     f.KebabTag + "-detached",
+			Usage:    "record spans for signals and driver calls outside of requests",
+			Value:    f.detached,
+			EnvVars:  b.MakeEnvKeys(envPrefix, "DETACHED"),
+			Category: f.KebabTag,
+		},
+	)
+	return
+}
+
+func (f *CFeature) Startup(ctx *cli.Context) (err error) {
+	if err = f.CFeature.Startup(ctx); err != nil {
+		return
+	}
+
+	if key := f.KebabTag + "-exporter"; ctx.IsSet(key) {
+		f.exporter = strings.ToLower(strings.TrimSpace(ctx.String(key)))
+	}
+	if key := f.KebabTag + "-otlp-endpoint"; ctx.IsSet(key) {
+		f.otlpEndpoint = strings.TrimSpace(ctx.String(key))
+	}
+	if key := f.KebabTag + "-otlp-header"; ctx.IsSet(key) {
+		for _, value := range ctx.StringSlice(key) {
+			if k, v, ok := strings.Cut(value, "="); ok && strings.TrimSpace(k) != "" {
+				f.otlpHeaders[strings.TrimSpace(k)] = strings.TrimSpace(v)
+			} else {
+				err = fmt.Errorf("invalid --%v value: %q", key, value)
+				return
+			}
+		}
+	}
+	if key := f.KebabTag + "-file-path"; ctx.IsSet(key) {
+		f.filePath = strings.TrimSpace(ctx.String(key))
+	}
+	if key := f.KebabTag + "-service-name"; ctx.IsSet(key) {
+		f.serviceName = strings.TrimSpace(ctx.String(key))
+	}
+	if key := f.KebabTag + "-sample-ratio"; ctx.IsSet(key) {
+		f.sampleRatio = ctx.Float64(key)
+	}
+	if key := f.KebabTag + "-detached"; ctx.IsSet(key) {
+		f.detached = ctx.Bool(key)
+	}
+
+	if f.sampleRatio < 0.0 || f.sampleRatio > 1.0 {
+		err = fmt.Errorf("--%v-sample-ratio must be between 0.0 and 1.0", f.KebabTag)
+		return
+	}
+
+	var exporter trace.Exporter
+	switch f.exporter {
+	case ExporterOtlp:
+		exporter = trace.NewOtlpExporter(f.otlpEndpoint, f.otlpHeaders)
+	case ExporterFile:
+		if f.filePath == "" {
+			err = fmt.Errorf("--%v-file-path is required with the file exporter", f.KebabTag)
+			return
+		} else if exporter, err = trace.NewFileExporter(f.filePath); err != nil {
+			err = fmt.Errorf("error opening trace file: %v - %v", f.filePath, err)
+			return
+		}
+	default:
+		err = fmt.Errorf("unsupported --%v-exporter: %q", f.KebabTag, f.exporter)
+		return
+	}
+
+	serviceName := f.serviceName
+	if serviceName == "" {
+		serviceName = f.Enjin.SiteTag()
+	}
+
+	trace.Configure(exporter, trace.Config{
+		Resource: trace.Resource{
+			ServiceName:    serviceName,
+			ServiceVersion: globals.Version,
+		},
+		SampleRatio: f.sampleRatio,
+		Detached:    f.detached,
+	})
+
+	log.InfoF("%v - exporting %v traces for %q (sample ratio: %v)", f.Tag(), f.exporter, serviceName, f.sampleRatio)
+	return
+}
+
+func (f *CFeature) Shutdown() {
+	trace.Shutdown()
+	f.CFeature.Shutdown()
+}
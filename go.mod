@@ -71,6 +71,7 @@ require (
 	github.com/yosssi/gohtml v0.0.0-20201013000340-ee4748c638f4
 	golang.ngrok.com/ngrok v1.8.1
 	golang.ngrok.com/ngrok/log/logrus v0.0.0-20240212161800-4d959c47e21f
+	golang.org/x/crypto v0.19.0
 	golang.org/x/net v0.21.0
 	gopkg.in/yaml.v3 v3.0.1
 	gorm.io/datatypes v1.2.0
@@ -172,7 +173,6 @@ require (
 	go.etcd.io/bbolt v1.3.8 // indirect
 	go.uber.org/multierr v1.11.0 // indirect
 	golang.ngrok.com/muxado/v2 v2.0.0 // indirect
-	golang.org/x/crypto v0.19.0 // indirect
 	golang.org/x/exp v0.0.0-20240112132812-db7319d0e0e3 // indirect
 	golang.org/x/image v0.14.0 // indirect
 	golang.org/x/sync v0.6.0 // indirect
@@ -48,6 +48,10 @@ const (
 	AdminLockUserActionKey   = "admin-lock-user"
 	AdminUnlockUserActionKey = "admin-unlock-user"
 	ResetUserOtpActionKey    = "reset-user-otp"
+	RevokeApiKeysActionKey   = "revoke-api-keys"
+	RevokeSessionsActionKey  = "revoke-sessions"
+	ImportUsersActionKey     = "import-users"
+	ExportUsersActionKey     = "export-users"
 )
 
 func MakeViewErrorAction(printer *message.Printer) (action *Action) {
@@ -391,3 +395,58 @@ func MakeResetUserOtp(printer *message.Printer, eid string) (action *Action) {
 		Order:  100,
 	}
 }
+
+func MakeRevokeApiKeys(printer *message.Printer, eid string) (action *Action) {
+	return &Action{
+		Key:    RevokeApiKeysActionKey,
+		Name:   printer.Sprintf("Revoke User API Keys"),
+		Icon:   "fa-solid fa-key",
+		Class:  "danger",
+		Active: true,
+		Method: PostFormMethod,
+		Prompt: printer.Sprintf(`Revoke API keys for "%[1]s"?`, eid),
+		Dialog: "revoke-api-keys",
+		Order:  100,
+	}
+}
+
+func MakeRevokeSessions(printer *message.Printer, eid string) (action *Action) {
+	return &Action{
+		Key:    RevokeSessionsActionKey,
+		Name:   printer.Sprintf("Revoke User Sessions"),
+		Icon:   "fa-solid fa-right-from-bracket",
+		Class:  "danger",
+		Active: true,
+		Method: PostFormMethod,
+		Prompt: printer.Sprintf(`Sign out all sessions of "%[1]s"?`, eid),
+		Dialog: "revoke-sessions",
+		Order:  101,
+	}
+}
+
+func MakeImportUsers(printer *message.Printer) (action *Action) {
+	return &Action{
+		Key:    ImportUsersActionKey,
+		Name:   printer.Sprintf("Import Users"),
+		Icon:   "fa-solid fa-file-import",
+		Class:  "important",
+		Active: true,
+		Method: PostFormMethod,
+		Prompt: printer.Sprintf(`Import users from a CSV or JSON file?`),
+		Dialog: "import-users",
+		Order:  2,
+	}
+}
+
+func MakeExportUsers(printer *message.Printer) (action *Action) {
+	return &Action{
+		Key:    ExportUsersActionKey,
+		Name:   printer.Sprintf("Export Users"),
+		Icon:   "fa-solid fa-file-export",
+		Active: true,
+		Method: PostFormMethod,
+		Prompt: printer.Sprintf(`Export all users to a CSV or JSON file?`),
+		Dialog: "export-users",
+		Order:  3,
+	}
+}
@@ -33,6 +33,9 @@ var (
 	ErrUserNotFound          = errors.New("user not found")
 	ErrGroupNotFound         = errors.New("group not found")
 	ErrTokenNotFound         = errors.New("token not found")
+	ErrTokenExpired          = errors.New("token expired")
+	ErrSessionRevoked        = errors.New("session revoked")
+	ErrTooManyAttempts       = errors.New("too many attempts")
 	ErrSecretNotFound        = errors.New("secret not found")
 	ErrProviderNotFound      = errors.New("provider not found")
 	ErrAudienceNotFound      = errors.New("audience not found")
@@ -76,6 +79,11 @@ func OtpChallengeFailed(printer *message.Printer) (msg string) {
 	return
 }
 
+func TooManyAttemptsError(printer *message.Printer) (msg string) {
+	msg = printer.Sprintf("Too many failed attempts, please try again later")
+	return
+}
+
 func UserNotFound(printer *message.Printer) (msg string) {
 	msg = printer.Sprintf("User not found")
 	return
@@ -47,6 +47,11 @@ type Builder interface {
 	SiteLanguageMode(mode lang.Mode) Builder
 	SiteDefaultLanguage(tag language.Tag) Builder
 	SiteSupportedLanguages(tags ...language.Tag) Builder
+
+	// SiteLanguageFallbacks configures the ordered list of other languages to use when a message, page or menu is not
+	// available in the given language, for example: pt-BR falls back to pt, then es and finally en
+	SiteLanguageFallbacks(tag language.Tag, fallbacks ...language.Tag) Builder
+
 	SiteLanguageDisplayNames(names map[language.Tag]string) Builder
 
 	// Set a custom context key with value
@@ -40,8 +40,10 @@ type EditorFeature interface {
 	EditorMenu(r *http.Request) (m menu.Menu)
 
 	GetEditorKey() (name string)
+	GetEditorType() (editorType string)
 	GetEditorPath() (path string)
 	GetEditorMenu() (m menu.Menu)
+	GetFileOperations() (ops map[string]*EditorOperation)
 
 	PrepareEditPage(pageType, editorType string, r *http.Request) (pg Page, ctx beContext.Context, err error)
 	ParseEditorUrlParams(r *http.Request) (fsid, code, file string, locale *language.Tag)
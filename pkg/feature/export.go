@@ -0,0 +1,25 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+// ExportPathsProvider is implemented by features which serve URL paths that
+// are not pages or static files, such as feeds, for inclusion in static site
+// exports
+type ExportPathsProvider interface {
+	Feature
+
+	// ExportPaths returns the URL paths to include in static site exports
+	ExportPaths() (paths []string)
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"context"
+)
+
+// HealthChecker is implemented by features which depend upon external
+// resources and can verify those resources are currently usable
+type HealthChecker interface {
+	Feature
+
+	// HealthCheck returns a non-nil error when the feature is not healthy,
+	// implementations must honour the given context deadline
+	HealthCheck(ctx context.Context) (err error)
+}
+
+// Ready returns true when the state is one that a fully started feature is
+// expected to be in
+func (l LifeCycleState) Ready() (ready bool) {
+	ready = l == StateStarted || l == StatePostStarted
+	return
+}
@@ -16,6 +16,7 @@ package feature
 
 import (
 	"context"
+	"net/http"
 
 	"github.com/urfave/cli/v2"
 )
@@ -53,6 +54,15 @@ type KeyValueStore interface {
 	Delete(key string) (err error)
 }
 
+// RequestKeyValueStore is a KeyValueStore which can be scoped to a request,
+// for the store operations to be traced within the request's trace
+type RequestKeyValueStore interface {
+	KeyValueStore
+
+	// ForRequest returns a copy of the store scoped to the given request
+	ForRequest(r *http.Request) (kvs KeyValueStore)
+}
+
 type KeyValueStoreRangeFn func(key string, value []byte) (stop bool)
 
 type ExtendedKeyValueStore interface {
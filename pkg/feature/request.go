@@ -54,3 +54,10 @@ type PermissionsPolicyModifier interface {
 	Feature
 	ModifyPermissionsPolicy(policy permissions.Policy, r *http.Request) (modified permissions.Policy)
 }
+
+// RequestDenier is a Feature which can deny future requests from a given
+// IP address, such as when an address is found to be abusive
+type RequestDenier interface {
+	Feature
+	DenyAddress(address string)
+}
@@ -28,4 +28,9 @@ type EnjinRunner interface {
 
 	Notify(tag string)
 	NotifyF(tag, format string, argv ...interface{})
+
+	// NotifyReady is called by ServiceListener implementations once they are
+	// listening, informing the parent process of a graceful restart that
+	// this process is ready to take over
+	NotifyReady()
 }
@@ -17,6 +17,7 @@ package feature
 import (
 	"net/http"
 	"net/url"
+	"os"
 	"time"
 
 	"github.com/go-chi/chi/v5"
@@ -29,6 +30,18 @@ type ServiceListener interface {
 	StartListening(router *chi.Mux, e EnjinRunner) (err error)
 }
 
+// GracefulServiceListener is a ServiceListener supporting graceful shutdowns
+// and handing off listening sockets to a new process
+type GracefulServiceListener interface {
+	ServiceListener
+	// DrainListening stops accepting new connections and waits up to the
+	// timeout given for active requests to complete before closing
+	DrainListening(timeout time.Duration) (err error)
+	// ListenerFiles returns duplicates of the listening sockets, for
+	// inheritance by a new process
+	ListenerFiles() (files []*os.File, err error)
+}
+
 // LoggerContext is a per-request context used with ServiceLogger implementations
 type LoggerContext interface {
 	// URL is the parsed request URL
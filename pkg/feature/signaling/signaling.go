@@ -14,7 +14,11 @@
 
 package signaling
 
-import "sync"
+import (
+	"sync"
+
+	"github.com/go-enjin/be/pkg/trace"
+)
 
 const (
 	SignalServePage Signal = "serve-page"
@@ -59,6 +63,11 @@ func (c *CSignaling) Emit(signal Signal, tag string, argv ...interface{}) (stopp
 	c.signalingLock.RLock()
 	defer c.signalingLock.RUnlock()
 	if num := len(c.signaling[signal]); num > 0 {
+		_, span := trace.Start(trace.ContextFromArgv(argv...), "signal "+string(signal),
+			"signal.tag", tag,
+			"signal.listeners", num,
+		)
+		defer span.End()
 		for i := num - 1; i >= 0; i-- {
 			if stopped = c.signaling[signal][i].L(signal, tag, c.signaling[signal][i].D, argv); stopped {
 				return
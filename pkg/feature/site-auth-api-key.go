@@ -0,0 +1,106 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// SiteAuthApiKey describes a personal access token belonging to a site user,
+// only the hash of the secret token is ever stored
+type SiteAuthApiKey struct {
+	ID      string  `json:"id"`
+	Name    string  `json:"name"`
+	Hash    string  `json:"hash"`
+	Actions Actions `json:"actions"`
+	Created int64   `json:"created"`
+	Expires int64   `json:"expires"`
+	Used    int64   `json:"used"`
+}
+
+// IsExpired returns true if the key has an expiration time that has passed
+func (k *SiteAuthApiKey) IsExpired() (expired bool) {
+	expired = k.Expires > 0 && time.Now().After(time.Unix(k.Expires, 0))
+	return
+}
+
+// Redacted returns a clone of the key without the hash value
+func (k *SiteAuthApiKey) Redacted() (clone *SiteAuthApiKey) {
+	clone = &SiteAuthApiKey{
+		ID:      k.ID,
+		Name:    k.Name,
+		Actions: append(Actions{}, k.Actions...),
+		Created: k.Created,
+		Expires: k.Expires,
+		Used:    k.Used,
+	}
+	return
+}
+
+type SiteAuthApiKeys []*SiteAuthApiKey
+
+// ParseSiteAuthApiKeys decodes the given value (as found within a user
+// context) into a list of SiteAuthApiKeys, sorted by creation time
+func ParseSiteAuthApiKeys(v interface{}) (keys SiteAuthApiKeys) {
+	switch t := v.(type) {
+	case nil:
+		return
+	case SiteAuthApiKeys:
+		keys = t
+	default:
+		if data, err := json.Marshal(t); err == nil {
+			_ = json.Unmarshal(data, &keys)
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool {
+		return keys[i].Created < keys[j].Created
+	})
+	return
+}
+
+func (k SiteAuthApiKeys) Len() int {
+	return len(k)
+}
+
+// Get returns the key with the given ID, or nil if not found
+func (k SiteAuthApiKeys) Get(id string) (key *SiteAuthApiKey) {
+	for _, kk := range k {
+		if kk.ID == id {
+			key = kk
+			return
+		}
+	}
+	return
+}
+
+// Prune returns a new list without the key with the given ID
+func (k SiteAuthApiKeys) Prune(id string) (pruned SiteAuthApiKeys) {
+	for _, kk := range k {
+		if kk.ID != id {
+			pruned = append(pruned, kk)
+		}
+	}
+	return
+}
+
+// Redacted returns a list of redacted clones of all keys
+func (k SiteAuthApiKeys) Redacted() (redacted SiteAuthApiKeys) {
+	for _, kk := range k {
+		redacted = append(redacted, kk.Redacted())
+	}
+	return
+}
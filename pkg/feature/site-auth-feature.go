@@ -47,6 +47,31 @@ type SiteAuthFeature interface {
 	ResetUserFactors(r *http.Request, eid string) (err error)
 	SetUserFactor(r *http.Request, claim *CSiteAuthClaimsFactor)
 
+	ApiKeysAllowed() (allowed bool)
+	CreateUserApiKey(r *http.Request, eid, name string, expires time.Time, actions ...Action) (token string, err error)
+	ListUserApiKeys(r *http.Request, eid string) (keys SiteAuthApiKeys, err error)
+	RevokeUserApiKey(r *http.Request, eid, id string) (err error)
+	RevokeUserApiKeys(r *http.Request, eid string) (err error)
+
+	ListUserSessions(r *http.Request, eid string) (sessions SiteAuthSessions, err error)
+	RevokeUserSession(r *http.Request, eid, id string) (err error)
+	RevokeUserSessions(r *http.Request, eid string, except ...string) (err error)
+
+	// CheckAuthAttempt returns errors.ErrTooManyAttempts when the account or
+	// the request's IP address is temporarily locked out
+	CheckAuthAttempt(r *http.Request, eid string) (err error)
+	// RecordAuthFailure counts a failed sign-in or challenge attempt against
+	// the account and the request's IP address
+	RecordAuthFailure(r *http.Request, eid string)
+	// RecordAuthSuccess resets the failed attempts of the account, the failed
+	// attempts of the request's IP address expire with the lockout duration
+	RecordAuthSuccess(r *http.Request, eid string)
+	// ThrottleAuthRequest counts requests which do not present credentials,
+	// such as sending sign-in emails, against the account and the request's
+	// IP address and returns errors.ErrTooManyAttempts when either is over
+	// the limit; throttled requests never lock out accounts or addresses
+	ThrottleAuthRequest(r *http.Request, eid string) (err error)
+
 	AuthorizeUserSignIn(w http.ResponseWriter, r *http.Request, claims *CSiteAuthClaims) (handled bool, modified *http.Request)
 
 	SiteAuthRequestHandler
@@ -0,0 +1,113 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package feature
+
+import (
+	"encoding/json"
+	"sort"
+	"time"
+)
+
+// SiteAuthSession describes a signed-in browser session of a site user, the
+// ID is the JWT ID (jti) of the session's claims
+type SiteAuthSession struct {
+	ID        string `json:"id"`
+	Device    string `json:"device"`
+	IP        string `json:"ip"`
+	UserAgent string `json:"user-agent"`
+	Created   int64  `json:"created"`
+	LastSeen  int64  `json:"last-seen"`
+	Revoked   int64  `json:"revoked,omitempty"`
+}
+
+// IsExpired returns true if the session has not been seen within the given
+// duration
+func (s *SiteAuthSession) IsExpired(duration time.Duration) (expired bool) {
+	expired = time.Now().After(time.Unix(s.LastSeen, 0).Add(duration))
+	return
+}
+
+type SiteAuthSessions []*SiteAuthSession
+
+// ParseSiteAuthSessions decodes the given value (as found within a user
+// context) into a list of SiteAuthSessions, sorted by last-seen time with the
+// most recent first
+func ParseSiteAuthSessions(v interface{}) (sessions SiteAuthSessions) {
+	switch t := v.(type) {
+	case nil:
+		return
+	case SiteAuthSessions:
+		sessions = t
+	default:
+		if data, err := json.Marshal(t); err == nil {
+			_ = json.Unmarshal(data, &sessions)
+		}
+	}
+	sort.Slice(sessions, func(i, j int) bool {
+		return sessions[i].LastSeen > sessions[j].LastSeen
+	})
+	return
+}
+
+func (s SiteAuthSessions) Len() int {
+	return len(s)
+}
+
+// Get returns the session with the given ID, or nil if not found
+func (s SiteAuthSessions) Get(id string) (session *SiteAuthSession) {
+	for _, ss := range s {
+		if ss.ID == id {
+			session = ss
+			return
+		}
+	}
+	return
+}
+
+// Prune returns a new list without the sessions with the given IDs
+func (s SiteAuthSessions) Prune(ids ...string) (pruned SiteAuthSessions) {
+	lookup := make(map[string]struct{})
+	for _, id := range ids {
+		lookup[id] = struct{}{}
+	}
+	for _, ss := range s {
+		if _, present := lookup[ss.ID]; !present {
+			pruned = append(pruned, ss)
+		}
+	}
+	return
+}
+
+// Active returns a new list without any revoked sessions
+func (s SiteAuthSessions) Active() (active SiteAuthSessions) {
+	for _, ss := range s {
+		if ss.Revoked == 0 {
+			active = append(active, ss)
+		}
+	}
+	return
+}
+
+// PruneExpired returns a new list without the sessions not seen within the
+// given duration, revoked sessions are kept until they expire so that their
+// tokens continue to be rejected
+func (s SiteAuthSessions) PruneExpired(duration time.Duration) (pruned SiteAuthSessions) {
+	for _, ss := range s {
+		if !ss.IsExpired(duration) {
+			pruned = append(pruned, ss)
+		}
+	}
+	return
+}
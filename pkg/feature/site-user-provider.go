@@ -69,3 +69,15 @@ type SiteUsersProvider interface {
 	GetUserActive(r *http.Request, eid string) (active bool, err error)
 	GetUserAdminLocked(r *http.Request, eid string) (locked bool, err error)
 }
+
+// SiteUsersSearchProvider is an optional SiteUsersProvider interface for
+// features capable of searching and sorting users
+type SiteUsersSearchProvider interface {
+	SearchUsers(r *http.Request, query, sortBy string, pg, numPerPage int, sortDesc bool) (list []User, total int)
+}
+
+// SiteUsersGroupsProvider is an optional SiteUsersProvider interface for
+// features capable of listing all groups present
+type SiteUsersGroupsProvider interface {
+	ListGroups(r *http.Request) (groups Groups, err error)
+}
@@ -46,6 +46,8 @@ type EnjinBase interface {
 	SiteLocales() (locales cllang.Tags)
 	SiteLanguageMode() (mode lang.Mode)
 	SiteLanguageCatalog() (c catalog.Catalog)
+	SiteLanguageMessageKeys(tag language.Tag) (keys []string)
+	SiteLanguageFallbacks(tag language.Tag) (fallbacks []language.Tag)
 	SiteDefaultLanguage() (tag language.Tag)
 	SiteSupportsLanguage(tag language.Tag) (supported bool)
 	SiteLanguageDisplayName(tag language.Tag) (name string, ok bool)
@@ -165,6 +167,7 @@ type Internals interface {
 
 	ReloadLocales()
 	HotReloading() (enabled bool)
+	ShuttingDown() (shuttingDown bool)
 
 	DB(tag string) (db interface{}, err error)
 	MustDB(tag string) (db interface{})
@@ -19,6 +19,7 @@ import (
 	"os"
 	"path/filepath"
 	"regexp"
+	"time"
 
 	times "github.com/go-enjin/github-com-djherbis-times"
 )
@@ -40,6 +41,12 @@ var (
 	DefaultPort = 3334
 	// DefaultListen is the fallback address to listen on
 	DefaultListen = ""
+	// DefaultShutdownTimeout is how long to wait for active requests to
+	// complete when shutting down
+	DefaultShutdownTimeout = 30 * time.Second
+	// DefaultRestartTimeout is how long to wait for a gracefully restarted
+	// process to become ready
+	DefaultRestartTimeout = time.Minute
 	// SlugIntegrity is the expected hash of a Shasums file, without BinName
 	// present (set by enjenv)
 	SlugIntegrity = ""
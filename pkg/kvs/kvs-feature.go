@@ -16,10 +16,22 @@ package kvs
 
 import (
 	"fmt"
+	"net/http"
 
 	"github.com/go-enjin/be/pkg/feature"
 )
 
+// ForRequest returns the store scoped to the given request, if the store is a
+// feature.RequestKeyValueStore, otherwise the store is returned as-is
+func ForRequest(store feature.KeyValueStore, r *http.Request) (scoped feature.KeyValueStore) {
+	if rkvs, ok := store.(feature.RequestKeyValueStore); ok && r != nil {
+		scoped = rkvs.ForRequest(r)
+		return
+	}
+	scoped = store
+	return
+}
+
 func AsExtended(store feature.KeyValueStore) (extended feature.ExtendedKeyValueStore, err error) {
 	var ok bool
 	if extended, ok = interface{}(store).(feature.ExtendedKeyValueStore); !ok {
@@ -15,6 +15,7 @@
 package catalog
 
 import (
+	"fmt"
 	"io/fs"
 	"sort"
 
@@ -26,6 +27,8 @@ import (
 	"github.com/go-corelibs/x-text/message/catalog"
 
 	beFs "github.com/go-enjin/be/pkg/fs"
+	beLang "github.com/go-enjin/be/pkg/lang"
+	"github.com/go-enjin/be/pkg/lang/icu"
 	"github.com/go-enjin/be/pkg/log"
 )
 
@@ -34,16 +37,21 @@ var (
 )
 
 var (
-	DefaultFileName = "out.gotext.json"
+	DefaultFileName      = "out.gotext.json"
+	DefaultXliffFileName = "messages.xlf"
 )
 
 type Catalog interface {
 	AddCatalog(others ...catalog.Catalog)
 	AddLocalesFromFS(defaultTag language.Tag, efs beFs.FileSystem)
 	AddLocalesFromJsonBytes(tag language.Tag, src string, contents []byte)
+	AddLocalesFromXliffBytes(tag language.Tag, src string, contents []byte)
 
 	LocaleTags() (tags []language.Tag)
 	LocaleTagsWithDefault(d language.Tag) (tags []language.Tag)
+	LocaleMessageKeys(tag language.Tag) (keys []string)
+
+	SetFallbacks(tag language.Tag, fallbacks ...language.Tag)
 
 	MakeGoTextCatalog() (gtc catalog.Catalog, err error)
 }
@@ -51,12 +59,25 @@ type Catalog interface {
 type CCatalog struct {
 	catalog  *catalog.Builder
 	catalogs []catalog.Catalog
+	// keys tracks the translated message keys loaded from gotext.json and xliff
+	keys map[language.Tag]map[string]struct{}
+	// messages tracks the messages loaded from gotext.json and xliff, used to fill in
+	// the keys missing from languages with fallbacks
+	messages  map[language.Tag]map[string][]catalog.Message
+	fallbacks beLang.FallbackChains
+	// placeholders tracks the gotext.json placeholder argument numbers for
+	// each message key, used to resolve ICU MessageFormat argument names
+	placeholders map[string]map[string]int
 }
 
 func New() (c Catalog) {
 	c = &CCatalog{
-		catalog:  catalog.NewBuilder(),
-		catalogs: make([]catalog.Catalog, 0),
+		catalog:      catalog.NewBuilder(),
+		catalogs:     make([]catalog.Catalog, 0),
+		keys:         make(map[language.Tag]map[string]struct{}),
+		messages:     make(map[language.Tag]map[string][]catalog.Message),
+		fallbacks:    make(beLang.FallbackChains),
+		placeholders: make(map[string]map[string]int),
 	}
 	return
 }
@@ -80,6 +101,22 @@ func (c *CCatalog) AddLocalesFromJsonBytes(tag language.Tag, src string, content
 	}
 
 	for _, msg := range gt.Messages {
+		if len(msg.Placeholders) > 0 {
+			if _, present := c.placeholders[msg.Key]; !present {
+				c.placeholders[msg.Key] = make(map[string]int)
+			}
+			for _, p := range msg.Placeholders {
+				c.placeholders[msg.Key][p.ID] = p.ArgNum
+			}
+		}
+	}
+
+	for _, msg := range gt.Messages {
+		if msg.Translation == nil {
+			continue
+		} else if !msg.Fuzzy && (msg.Translation.String != "" || msg.Translation.Select != nil) {
+			c.trackKey(parsed, msg.Key)
+		}
 		if msg.Translation.Select != nil {
 			var argNum int = -1
 			for _, p := range msg.Placeholders {
@@ -96,16 +133,68 @@ func (c *CCatalog) AddLocalesFromJsonBytes(tag language.Tag, src string, content
 			for k, v := range msg.Translation.Select.Cases {
 				cases = append(cases, k, v.Msg)
 			}
-			if err = c.catalog.Set(parsed, msg.Key, plural.Selectf(argNum, "", cases...)); err != nil {
+			if err = c.setMessage(parsed, msg.Key, plural.Selectf(argNum, "", cases...)); err != nil {
 				log.ErrorF("error setting gotext.json select: [%v] %v - %q - %v", tag, src, msg.Translation.Select, err)
 			}
-		} else if err = c.catalog.Set(parsed, msg.Key, catalog.String(msg.Translation.String)); err != nil {
+		} else if err = c.setTranslation(parsed, msg.Key, msg.Translation.String); err != nil {
 			log.ErrorF("error setting gotext.json string: [%v] %v - %q - %v", tag, src, msg.Translation.String, err)
 		}
 	}
 
 }
 
+// setTranslation sets the translated text for the given language and key,
+// compiling any ICU MessageFormat plural, select and selectordinal arguments
+func (c *CCatalog) setTranslation(tag language.Tag, key, text string) (err error) {
+	if !icu.IsMessageFormat(text) {
+		err = c.setMessage(tag, key, catalog.String(text))
+		return
+	}
+	var m *icu.Message
+	if m, err = icu.Parse(text); err != nil {
+		return
+	}
+	if names := m.WrappedArgs(); len(names) > 0 {
+		// unwrapped argument values select the wrong cases without any error
+		log.WarnF("[%v] %q select and selectordinal arguments %v must be given with icu.Choose and icu.Ordinal (or the _select and _ordinal template functions)", tag, key, names)
+	}
+	var messages []catalog.Message
+	if messages, err = m.Compile(tag, c.makeResolver(key)); err != nil {
+		return
+	}
+	err = c.setMessage(tag, key, messages...)
+	return
+}
+
+// makeResolver returns an icu.Resolver using the placeholders of the given
+// message key
+func (c *CCatalog) makeResolver(key string) icu.Resolver {
+	return func(name string) (argNum int, ok bool) {
+		if placeholders, present := c.placeholders[key]; present {
+			argNum, ok = placeholders[name]
+		}
+		return
+	}
+}
+
+func (c *CCatalog) setMessage(tag language.Tag, key string, messages ...catalog.Message) (err error) {
+	if err = c.catalog.Set(tag, key, messages...); err != nil {
+		return
+	}
+	if _, present := c.messages[tag]; !present {
+		c.messages[tag] = make(map[string][]catalog.Message)
+	}
+	c.messages[tag][key] = messages
+	return
+}
+
+func (c *CCatalog) trackKey(tag language.Tag, key string) {
+	if _, present := c.keys[tag]; !present {
+		c.keys[tag] = make(map[string]struct{})
+	}
+	c.keys[tag][key] = struct{}{}
+}
+
 func (c *CCatalog) AddLocalesFromFS(defaultTag language.Tag, efs beFs.FileSystem) {
 	var err error
 	var entries []fs.DirEntry
@@ -133,25 +222,37 @@ func (c *CCatalog) AddLocalesFromFS(defaultTag language.Tag, efs beFs.FileSystem
 			continue
 		}
 
-		var filename string
+		var filename, xliffFilename string
 		for _, te := range tagEntries {
 			switch te.Name() {
 			case DefaultFileName:
 				filename = DefaultFileName
-				break
+			case DefaultXliffFileName:
+				xliffFilename = DefaultXliffFileName
 			}
 		}
-		if filename == "" {
-			log.DebugF("locale (%v) not found, expected: %v", name, DefaultFileName)
+		if filename == "" && xliffFilename == "" {
+			log.DebugF("locale (%v) not found, expected: %v or %v", name, DefaultFileName, DefaultXliffFileName)
 			continue
 		}
 
-		src := name + "/" + filename
-		//log.DebugF("locale source found: %v", src)
-		if contents, eeee := efs.ReadFile(src); eeee != nil {
-			log.ErrorF("error reading: %v - %v", src, eeee)
-		} else {
-			c.AddLocalesFromJsonBytes(entryTag, src, contents)
+		if filename != "" {
+			src := name + "/" + filename
+			//log.DebugF("locale source found: %v", src)
+			if contents, eeee := efs.ReadFile(src); eeee != nil {
+				log.ErrorF("error reading: %v - %v", src, eeee)
+			} else {
+				c.AddLocalesFromJsonBytes(entryTag, src, contents)
+			}
+		}
+
+		if xliffFilename != "" {
+			src := name + "/" + xliffFilename
+			if contents, eeee := efs.ReadFile(src); eeee != nil {
+				log.ErrorF("error reading: %v - %v", src, eeee)
+			} else {
+				c.AddLocalesFromXliffBytes(entryTag, src, contents)
+			}
 		}
 
 	}
@@ -181,10 +282,93 @@ func (c *CCatalog) LocaleTagsWithDefault(d language.Tag) (tags []language.Tag) {
 	return
 }
 
+// LocaleMessageKeys returns the sorted list of message keys with non-fuzzy,
+// non-empty translations loaded from gotext.json sources for the given language, keys
+// provided by catalogs added with AddCatalog are not included
+func (c *CCatalog) LocaleMessageKeys(tag language.Tag) (keys []string) {
+	for known, found := range c.keys {
+		if language.Compare(known, tag) {
+			for key := range found {
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Strings(keys)
+	return
+}
+
+// SetFallbacks configures the ordered list of other languages to use for the
+// messages missing from the given language
+func (c *CCatalog) SetFallbacks(tag language.Tag, fallbacks ...language.Tag) {
+	c.fallbacks.Set(tag, fallbacks...)
+}
+
 func (c *CCatalog) MakeGoTextCatalog() (gtc catalog.Catalog, err error) {
 	b := catalog.NewBuilder()
 	b.Include(c.catalogs...)
+	if len(c.fallbacks) > 0 {
+		// the fallbacks only have messages without a translation of their
+		// own and take precedence over any fuzzy or empty translations
+		var fb *catalog.Builder
+		if fb, err = c.makeFallbackCatalog(); err != nil {
+			return
+		}
+		b.Include(fb)
+	}
 	b.Include(c.catalog)
 	gtc = b
 	return
 }
+
+// makeFallbackCatalog builds a catalog of the messages missing from each
+// language with fallbacks, using the first fallback language with the message;
+// fuzzy and empty translations are considered missing
+func (c *CCatalog) makeFallbackCatalog() (fb *catalog.Builder, err error) {
+	fb = catalog.NewBuilder()
+	for tag, fallbacks := range c.fallbacks {
+		own := c.findKeys(tag)
+		added := make(map[string]struct{})
+		for _, fallback := range fallbacks {
+			translated := c.findKeys(fallback)
+			for key, msg := range c.findMessages(fallback) {
+				if _, present := own[key]; present {
+					continue
+				} else if _, present = translated[key]; !present {
+					continue
+				} else if _, present = added[key]; present {
+					continue
+				}
+				if err = fb.Set(tag, key, msg...); err != nil {
+					err = fmt.Errorf("error setting %v fallback message from %v: %q - %v", tag, fallback, key, err)
+					return
+				}
+				added[key] = struct{}{}
+			}
+		}
+	}
+	return
+}
+
+func (c *CCatalog) findKeys(tag language.Tag) (keys map[string]struct{}) {
+	if found, ok := c.keys[tag]; ok {
+		return found
+	}
+	for known, found := range c.keys {
+		if language.Compare(known, tag) {
+			return found
+		}
+	}
+	return
+}
+
+func (c *CCatalog) findMessages(tag language.Tag) (messages map[string][]catalog.Message) {
+	if found, ok := c.messages[tag]; ok {
+		return found
+	}
+	for known, found := range c.messages {
+		if language.Compare(known, tag) {
+			return found
+		}
+	}
+	return
+}
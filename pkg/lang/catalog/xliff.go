@@ -0,0 +1,179 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package catalog
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"github.com/go-corelibs/x-text/language"
+
+	"github.com/go-enjin/be/pkg/log"
+)
+
+// XliffUnit is a translation unit parsed from an XLIFF 1.2 or 2.0 document
+type XliffUnit struct {
+	Key    string
+	Source string
+	Target string
+	Fuzzy  bool
+}
+
+// ParseXliff parses the translation units of the given XLIFF 1.2 or 2.0
+// document, the language returned is the target language of the first file
+// element, language.Und if not specified
+//
+// Unit keys are the 1.2 resname (or 2.0 name) attribute, falling back to the
+// unit id and then to the source text
+func ParseXliff(contents []byte) (units []*XliffUnit, tag language.Tag, err error) {
+	decoder := xml.NewDecoder(bytes.NewReader(contents))
+
+	var unit *XliffUnit
+	var text *strings.Builder
+	var source, target strings.Builder
+	var seenVersion bool
+
+	for {
+		var token xml.Token
+		if token, err = decoder.Token(); err != nil {
+			if errors.Is(err, io.EOF) {
+				err = nil
+				break
+			}
+			return
+		}
+
+		switch t := token.(type) {
+
+		case xml.StartElement:
+			attrs := make(map[string]string)
+			for _, attr := range t.Attr {
+				attrs[attr.Name.Local] = attr.Value
+			}
+
+			switch t.Name.Local {
+			case "xliff":
+				seenVersion = attrs["version"] != ""
+				if v := attrs["trgLang"]; v != "" {
+					if tag, err = language.Parse(v); err != nil {
+						err = fmt.Errorf("invalid xliff trgLang: %q - %v", v, err)
+						return
+					}
+				}
+			case "file":
+				if v := attrs["target-language"]; v != "" && tag == language.Und {
+					if tag, err = language.Parse(v); err != nil {
+						err = fmt.Errorf("invalid xliff target-language: %q - %v", v, err)
+						return
+					}
+				}
+			case "trans-unit", "unit":
+				unit = &XliffUnit{Key: attrs["resname"]}
+				if unit.Key == "" {
+					unit.Key = attrs["name"]
+				}
+				if unit.Key == "" {
+					unit.Key = attrs["id"]
+				}
+				source.Reset()
+				target.Reset()
+			case "segment":
+				if unit != nil {
+					unit.Fuzzy = unit.Fuzzy || xliffFuzzyState(attrs["state"])
+				}
+			case "source":
+				if unit != nil {
+					text = &source
+				}
+			case "target":
+				if unit != nil {
+					text = &target
+					unit.Fuzzy = unit.Fuzzy || xliffFuzzyState(attrs["state"])
+				}
+			}
+
+		case xml.CharData:
+			if text != nil {
+				text.Write(t)
+			}
+
+		case xml.EndElement:
+			switch t.Name.Local {
+			case "source", "target":
+				text = nil
+			case "trans-unit", "unit":
+				if unit != nil {
+					unit.Source = source.String()
+					unit.Target = target.String()
+					if unit.Key == "" {
+						unit.Key = unit.Source
+					}
+					if unit.Key != "" && unit.Target != "" {
+						units = append(units, unit)
+					}
+				}
+				unit = nil
+			}
+
+		}
+	}
+
+	if !seenVersion {
+		err = fmt.Errorf("xliff version not found")
+	}
+	return
+}
+
+func xliffFuzzyState(state string) (fuzzy bool) {
+	switch state {
+	case "new", "needs-translation", "needs-adaptation", "needs-l10n", "needs-review-translation", "initial":
+		fuzzy = true
+	}
+	return
+}
+
+// AddLocalesFromXliffBytes adds the translations of the given XLIFF 1.2 or
+// 2.0 document, target texts may be ICU MessageFormat strings with argument
+// names matching the gotext.json placeholders of the same message key or
+// zero-based argument indexes
+func (c *CCatalog) AddLocalesFromXliffBytes(tag language.Tag, src string, contents []byte) {
+	if len(contents) == 0 {
+		return
+	}
+
+	var err error
+	var parsed language.Tag
+	var units []*XliffUnit
+
+	if units, parsed, err = ParseXliff(contents); err != nil {
+		log.ErrorF("error parsing xliff: [%v] %v - %v", tag, src, err)
+		return
+	} else if parsed == language.Und {
+		parsed = tag
+	}
+
+	for _, unit := range units {
+		if !unit.Fuzzy {
+			c.trackKey(parsed, unit.Key)
+		}
+		if err = c.setTranslation(parsed, unit.Key, unit.Target); err != nil {
+			log.ErrorF("error setting xliff translation: [%v] %v - %q - %v", tag, src, unit.Target, err)
+		}
+	}
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package coverage compares the message keys used in templates and Go sources
+// against the translations loaded for each of the site locales, lists pages
+// which are missing translations and writes skeleton gotext.json messages for
+// the keys not yet translated.
+package coverage
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"time"
+
+	"github.com/go-corelibs/x-text/language"
+
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/linkcheck"
+)
+
+// ExtractEnjin adds all template message keys found within the enjin theme
+// (and its parent) and all mounted content filesystems
+func ExtractEnjin(enjin feature.Internals, keys Keys) {
+	if t, err := enjin.GetTheme(); err == nil {
+		for t != nil {
+			if tfs := t.ThemeFS(); tfs != nil {
+				ExtractFS(t.Name(), tfs, keys)
+			}
+			t = t.GetParent()
+		}
+	}
+	for _, pfs := range feature.FilterTyped[feature.PageFileSystemFeature](enjin.Features().List()) {
+		for _, mps := range pfs.GetMountedPoints() {
+			for _, mp := range mps {
+				ExtractFS(mp.Path, mp.ROFS, keys)
+			}
+		}
+	}
+}
+
+// Build constructs a new Report comparing the given keys against the message
+// keys loaded for each of the site locales
+func Build(enjin feature.Internals, keys Keys) (report *Report) {
+	report = &Report{
+		Started: time.Now(),
+		Keys:    keys,
+	}
+
+	list := keys.List()
+	for _, tag := range enjin.SiteLocales() {
+		translated := make(map[string]struct{})
+		for _, key := range enjin.SiteLanguageMessageKeys(tag) {
+			translated[key] = struct{}{}
+		}
+		lc := &LocaleCoverage{
+			Locale: tag.String(),
+			Total:  len(list),
+		}
+		for _, key := range list {
+			if _, present := translated[key]; present {
+				lc.Translated += 1
+			} else {
+				lc.Missing = append(lc.Missing, key)
+			}
+		}
+		report.Locales = append(report.Locales, lc)
+	}
+
+	report.Pages = MissingPages(enjin)
+	report.Duration = time.Now().Sub(report.Started).String()
+	return
+}
+
+// MissingPages returns the list of default language pages which do not have
+// a translated counterpart in each of the other site locales
+func MissingPages(enjin feature.Internals) (missing []*MissingPage) {
+	defaultTag := enjin.SiteDefaultLanguage()
+	r := httptest.NewRequest(http.MethodGet, "/", nil)
+
+	for _, pageUrl := range linkcheck.PageUrls(enjin) {
+		p := enjin.FindPage(r, defaultTag, pageUrl)
+		if p == nil || p.HasTranslation() || !language.Compare(p.LanguageTag(), defaultTag) {
+			// language neutral pages and translations are not checked
+			continue
+		}
+
+		// fallback language pages are not translations
+		translations := make(map[language.Tag]struct{})
+		for _, pg := range enjin.FindTranslations(p.Url()) {
+			if !pg.Context().Bool("LanguageFallback", false) {
+				translations[pg.LanguageTag()] = struct{}{}
+			}
+		}
+		mp := &MissingPage{
+			Url:      p.Url(),
+			Language: defaultTag.String(),
+		}
+		for _, tag := range enjin.SiteLocales() {
+			if language.Compare(tag, defaultTag) {
+				continue
+			} else if _, present := translations[tag]; present {
+				continue
+			} else if found := enjin.FindPage(r, tag, pageUrl); found != nil && language.Compare(found.LanguageTag(), tag) {
+				continue
+			}
+			mp.Missing = append(mp.Missing, tag.String())
+		}
+		if len(mp.Missing) > 0 {
+			missing = append(missing, mp)
+		}
+	}
+	return
+}
@@ -0,0 +1,198 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coverage
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	beFs "github.com/go-enjin/be/pkg/fs"
+)
+
+var (
+	// rxTemplateUnderscore matches the first argument of the template "_"
+	// (translate) function, as a double-quoted or backtick string
+	rxTemplateUnderscore = regexp.MustCompile(`(?:\{\{-?|\(|\|)\s*_\s+("(?:[^"\\\n]|\\.)*"|` + "`[^`]*`" + `)`)
+
+	// PrinterMethods are the message.Printer methods with a format argument,
+	// mapped to the index of the format argument
+	PrinterMethods = map[string]int{
+		"Sprintf": 0,
+		"Printf":  0,
+		"Fprintf": 1,
+	}
+)
+
+// Source is the location of a single message key usage
+type Source struct {
+	File string `json:"file"`
+	Line int    `json:"line"`
+}
+
+func (s Source) String() string {
+	return s.File + ":" + strconv.Itoa(s.Line)
+}
+
+// Keys is a mapping of message keys to all places they are used
+type Keys map[string][]Source
+
+// Add records a usage of the given key
+func (k Keys) Add(key string, source Source) {
+	if key != "" {
+		k[key] = append(k[key], source)
+	}
+}
+
+// List returns the sorted list of keys
+func (k Keys) List() (keys []string) {
+	for key := range k {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return
+}
+
+// Comment returns a translator comment listing up to the first three sources
+// of the given key
+func (k Keys) Comment(key string) (comment string) {
+	var list []string
+	for idx, src := range k[key] {
+		if idx >= 3 {
+			list = append(list, "...")
+			break
+		}
+		list = append(list, src.String())
+	}
+	if len(list) > 0 {
+		comment = "used in: " + strings.Join(list, ", ")
+	}
+	return
+}
+
+// ExtractTemplate adds all "_" (translate) function message keys found
+// within the template source
+func ExtractTemplate(name string, data []byte, keys Keys) {
+	text := string(data)
+	for _, m := range rxTemplateUnderscore.FindAllStringSubmatchIndex(text, -1) {
+		if key, err := strconv.Unquote(text[m[2]:m[3]]); err == nil {
+			keys.Add(key, Source{File: name, Line: strings.Count(text[:m[2]], "\n") + 1})
+		}
+	}
+}
+
+// ExtractGo adds all message keys used as the format argument to a
+// message.Printer method call within the Go source. Printers are recognized
+// by name: any variable, field or function result with "printer" in the name
+// (case-insensitive)
+func ExtractGo(name string, data []byte, keys Keys) (err error) {
+	fset := token.NewFileSet()
+	var file *ast.File
+	if file, err = parser.ParseFile(fset, name, data, parser.SkipObjectResolution); err != nil {
+		return
+	}
+
+	ast.Inspect(file, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpr)
+		if !ok {
+			return true
+		}
+		sel, ok := call.Fun.(*ast.SelectorExpr)
+		if !ok {
+			return true
+		}
+		argIdx, ok := PrinterMethods[sel.Sel.Name]
+		if !ok || len(call.Args) <= argIdx || !isPrinterExpr(sel.X) {
+			return true
+		}
+		if lit, ok := call.Args[argIdx].(*ast.BasicLit); ok && lit.Kind == token.STRING {
+			if key, ee := strconv.Unquote(lit.Value); ee == nil {
+				keys.Add(key, Source{File: name, Line: fset.Position(lit.Pos()).Line})
+			}
+		}
+		return true
+	})
+	return
+}
+
+func isPrinterExpr(expr ast.Expr) (ok bool) {
+	switch t := expr.(type) {
+	case *ast.Ident:
+		ok = strings.Contains(strings.ToLower(t.Name), "printer")
+	case *ast.SelectorExpr:
+		ok = strings.Contains(strings.ToLower(t.Sel.Name), "printer")
+	case *ast.CallExpr:
+		ok = isPrinterExpr(t.Fun)
+	case *ast.ParenExpr:
+		ok = isPrinterExpr(t.X)
+	}
+	return
+}
+
+// ExtractFS adds all template message keys found within all files of the
+// given filesystem, prefix is prepended to the source file names
+func ExtractFS(prefix string, bfs beFs.FileSystem, keys Keys) {
+	if files, err := bfs.ListAllFiles("."); err == nil {
+		for _, file := range files {
+			if data, ee := bfs.ReadFile(file); ee == nil {
+				ExtractTemplate(filepath.Join(prefix, file), data, keys)
+			}
+		}
+	}
+}
+
+// ExtractDir walks the local directory and adds all message keys found within
+// Go sources and templates, skipping hidden, underscore and vendor
+// directories
+func ExtractDir(root string, keys Keys) (err error) {
+	err = filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		name := d.Name()
+		if d.IsDir() {
+			if path != root && (name == "vendor" || strings.HasPrefix(name, ".") || strings.HasPrefix(name, "_")) {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		switch filepath.Ext(name) {
+		case ".go":
+			if strings.HasSuffix(name, "_test.go") {
+				return nil
+			}
+			if data, ee := os.ReadFile(path); ee != nil {
+				return ee
+			} else if ee = ExtractGo(path, data, keys); ee != nil {
+				return ee
+			}
+		case ".tmpl", ".html":
+			if data, ee := os.ReadFile(path); ee != nil {
+				return ee
+			} else {
+				ExtractTemplate(path, data, keys)
+			}
+		}
+		return nil
+	})
+	return
+}
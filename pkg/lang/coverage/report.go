@@ -0,0 +1,107 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coverage
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// LocaleCoverage describes the translation status of all message keys for a
+// single locale
+type LocaleCoverage struct {
+	Locale     string   `json:"locale"`
+	Total      int      `json:"total"`
+	Translated int      `json:"translated"`
+	Missing    []string `json:"missing,omitempty"`
+}
+
+// Percent returns the percentage of translated keys
+func (lc *LocaleCoverage) Percent() (percent float64) {
+	if lc.Total == 0 {
+		return 100
+	}
+	percent = float64(lc.Translated) / float64(lc.Total) * 100
+	return
+}
+
+// MissingPage describes a page which is not available in one or more of the
+// site locales
+type MissingPage struct {
+	Url      string   `json:"url"`
+	Language string   `json:"language"`
+	Missing  []string `json:"missing"`
+}
+
+// Report is the result of comparing all message keys found against the
+// translations of each supported locale
+type Report struct {
+	Started  time.Time         `json:"started"`
+	Duration string            `json:"duration"`
+	Keys     Keys              `json:"keys"`
+	Locales  []*LocaleCoverage `json:"locales"`
+	Pages    []*MissingPage    `json:"pages,omitempty"`
+}
+
+// Missing returns the missing message keys for the given locale
+func (r *Report) Missing(locale string) (keys []string) {
+	for _, lc := range r.Locales {
+		if lc.Locale == locale {
+			keys = lc.Missing
+			return
+		}
+	}
+	return
+}
+
+// Failed returns true if any locale is missing any message keys or there
+// are any pages missing translations
+func (r *Report) Failed() (failed bool) {
+	for _, lc := range r.Locales {
+		if len(lc.Missing) > 0 {
+			return true
+		}
+	}
+	return len(r.Pages) > 0
+}
+
+// WriteText writes a human-readable summary of the report, listing each
+// missing key with its first source location
+func (r *Report) WriteText(w io.Writer) (err error) {
+	for _, lc := range r.Locales {
+		if _, err = fmt.Fprintf(w, "[%v] %d/%d messages translated (%.1f%%)\n", lc.Locale, lc.Translated, lc.Total, lc.Percent()); err != nil {
+			return
+		}
+		for _, key := range lc.Missing {
+			var src string
+			if sources := r.Keys[key]; len(sources) > 0 {
+				src = " (" + sources[0].String() + ")"
+			}
+			if _, err = fmt.Fprintf(w, "\tmissing: %q%v\n", key, src); err != nil {
+				return
+			}
+		}
+	}
+	for _, mp := range r.Pages {
+		if _, err = fmt.Fprintf(w, "[%v] page %v is not translated to: %v\n", mp.Language, mp.Url, strings.Join(mp.Missing, ", ")); err != nil {
+			return
+		}
+	}
+	_, err = fmt.Fprintf(w, "checked %d messages in %d locales in %v: %d pages missing translations\n",
+		len(r.Keys), len(r.Locales), r.Duration, len(r.Pages))
+	return
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package coverage
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+
+	"github.com/go-corelibs/lang"
+	"github.com/go-corelibs/x-text/language"
+
+	beCatalog "github.com/go-enjin/be/pkg/lang/catalog"
+)
+
+// NewSkeletonMessage constructs a new fuzzy gotext message for the given key,
+// the translation is the untranslated message so that pages continue to
+// render the source text until the message is translated
+func NewSkeletonMessage(key, comment string) (msg *lang.Message) {
+	replaced, labelled, placeholders := lang.ParseMessagePlaceholders(key)
+	msg = &lang.Message{
+		ID:                labelled,
+		Key:               key,
+		Message:           replaced,
+		Translation:       &lang.Translation{String: replaced},
+		TranslatorComment: comment,
+		Placeholders:      placeholders,
+		Fuzzy:             true,
+	}
+	return
+}
+
+// AddSkeletonMessages appends skeleton messages to the gotext data for each
+// of the missing keys not already present
+func AddSkeletonMessages(gt *lang.GoText, keys Keys, missing []string) (added int) {
+	present := make(map[string]struct{})
+	for _, msg := range gt.Messages {
+		present[msg.Key] = struct{}{}
+	}
+	for _, key := range missing {
+		if _, found := present[key]; found {
+			continue
+		}
+		present[key] = struct{}{}
+		gt.Messages = append(gt.Messages, NewSkeletonMessage(key, keys.Comment(key)))
+		added += 1
+	}
+	return
+}
+
+// WriteSkeleton adds skeleton messages for the missing keys to the
+// <dir>/<tag>/out.gotext.json file, creating it if necessary
+func WriteSkeleton(dir string, tag language.Tag, keys Keys, missing []string) (path string, added int, err error) {
+	path = filepath.Join(dir, tag.String(), beCatalog.DefaultFileName)
+
+	gt := &lang.GoText{Language: tag.String()}
+	if data, ee := os.ReadFile(path); ee == nil {
+		if gt, _, err = lang.ParseGoText(data); err != nil {
+			return
+		}
+	} else if !os.IsNotExist(ee) {
+		err = ee
+		return
+	}
+
+	if added = AddSkeletonMessages(gt, keys, missing); added == 0 {
+		return
+	}
+
+	var data []byte
+	if data, err = json.MarshalIndent(gt, "", "    "); err != nil {
+		return
+	}
+	if err = os.MkdirAll(filepath.Dir(path), 0770); err != nil {
+		return
+	}
+	err = os.WriteFile(path, data, 0660)
+	return
+}
@@ -0,0 +1,96 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lang
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/go-corelibs/x-text/language"
+)
+
+// FallbackChains maps languages to the ordered list of other languages to use
+// when a message, page or menu is not available in the language itself
+type FallbackChains map[language.Tag][]language.Tag
+
+// ParseFallbackChain parses a chain specification of language tags separated
+// by ">", "," or whitespace, the first tag is the language the remaining tags
+// are fallbacks for. For example: "pt-BR > pt > es > en"
+func ParseFallbackChain(spec string) (tag language.Tag, fallbacks []language.Tag, err error) {
+	fields := strings.FieldsFunc(spec, func(r rune) bool {
+		return r == '>' || r == ',' || r == ' ' || r == '\t'
+	})
+	if len(fields) < 2 {
+		err = fmt.Errorf("a fallback chain requires at least two languages: %q", spec)
+		return
+	}
+	var tags []language.Tag
+	for _, field := range fields {
+		var parsed language.Tag
+		if parsed, err = language.Parse(field); err != nil {
+			err = fmt.Errorf("invalid fallback chain language: %q - %v", field, err)
+			return
+		}
+		tags = append(tags, parsed)
+	}
+	tag, fallbacks = tags[0], tags[1:]
+	return
+}
+
+// Set replaces the fallbacks for the given language, omitting the language
+// itself and any duplicates
+func (fc FallbackChains) Set(tag language.Tag, fallbacks ...language.Tag) {
+	var chain []language.Tag
+	unique := map[language.Tag]struct{}{tag: {}}
+	for _, fallback := range fallbacks {
+		if _, present := unique[fallback]; !present {
+			unique[fallback] = struct{}{}
+			chain = append(chain, fallback)
+		}
+	}
+	fc[tag] = chain
+}
+
+// Get returns the fallbacks for the given language, nil if none are
+// configured
+func (fc FallbackChains) Get(tag language.Tag) (fallbacks []language.Tag) {
+	if found, ok := fc[tag]; ok {
+		fallbacks = append(fallbacks, found...)
+		return
+	}
+	// the map order is random, check the configured languages in a stable
+	// order for the same equivalent language to always be found
+	known := make([]language.Tag, 0, len(fc))
+	for key := range fc {
+		known = append(known, key)
+	}
+	sort.Slice(known, func(i, j int) bool {
+		return known[i].String() < known[j].String()
+	})
+	for _, key := range known {
+		if language.Compare(key, tag) {
+			fallbacks = append(fallbacks, fc[key]...)
+			return
+		}
+	}
+	return
+}
+
+// Chain returns the given language followed by its fallbacks
+func (fc FallbackChains) Chain(tag language.Tag) (chain []language.Tag) {
+	chain = append([]language.Tag{tag}, fc.Get(tag)...)
+	return
+}
@@ -0,0 +1,124 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icu
+
+import (
+	"fmt"
+	"sync"
+
+	"github.com/go-corelibs/x-text/feature/plural"
+	"github.com/go-corelibs/x-text/language"
+)
+
+var (
+	_ plural.Interface = (*SelectValue)(nil)
+	_ plural.Interface = (*OrdinalValue)(nil)
+)
+
+// plural.Selectf validates category selectors against the cardinal categories
+// of the language and so the ordinal categories (such as "two" and "few" in
+// English) cannot be selected by name, both select keywords and ordinal
+// categories are instead encoded as explicit values at the very top of the
+// supported range where raw argument values practically never reach
+const (
+	// selectCodeBase is the first explicit value assigned to select keywords
+	selectCodeBase = 60000
+	// ordinalCodeBase is the first explicit value used to encode the CLDR
+	// ordinal categories, the categories are offset by their plural.Form
+	ordinalCodeBase = maxCode - int(plural.Many)
+	// maxCode is the largest explicit value supported by plural.Selectf
+	maxCode = 65535
+)
+
+var (
+	selectCodes = map[string]int{}
+	selectMutex = &sync.RWMutex{}
+)
+
+// SelectValue wraps a select argument value, compiled select arguments only
+// match their keywords when given a SelectValue
+type SelectValue struct {
+	Value string
+}
+
+// Choose wraps the given value for use as a select argument, for example:
+//
+//	printer.Sprintf("%[1]s replied", icu.Choose(user.Gender))
+func Choose(value interface{}) *SelectValue {
+	return &SelectValue{Value: fmt.Sprintf("%v", value)}
+}
+
+func (v *SelectValue) String() string {
+	return v.Value
+}
+
+// PluralForm implements plural.Interface, returning the explicit value
+// assigned to the keyword by the compiled select cases
+func (v *SelectValue) PluralForm(t language.Tag, scale int) (f plural.Form, n int) {
+	selectMutex.RLock()
+	defer selectMutex.RUnlock()
+	f, n = plural.Other, -1
+	if code, ok := selectCodes[v.Value]; ok {
+		n = code
+	}
+	return
+}
+
+// OrdinalValue wraps a selectordinal argument value, compiled selectordinal
+// arguments only match their categories when given an OrdinalValue, other
+// numbers only match the "other" category
+type OrdinalValue struct {
+	Value int
+}
+
+// Ordinal wraps the given number for use as a selectordinal argument, for
+// example:
+//
+//	printer.Sprintf("finished in %[1]d place", icu.Ordinal(position))
+func Ordinal(n int) *OrdinalValue {
+	return &OrdinalValue{Value: n}
+}
+
+func (v *OrdinalValue) Format(s fmt.State, verb rune) {
+	_, _ = fmt.Fprintf(s, fmt.FormatString(s, verb), v.Value)
+}
+
+// PluralForm implements plural.Interface, returning the explicit value
+// assigned to the ordinal category of the number for the given language
+func (v *OrdinalValue) PluralForm(t language.Tag, scale int) (f plural.Form, n int) {
+	f, n = plural.Other, ordinalCode(OrdinalForm(t, v.Value))
+	return
+}
+
+func ordinalCode(form plural.Form) (code int) {
+	return ordinalCodeBase + int(form)
+}
+
+// selectCode returns the explicit value assigned to the given select keyword,
+// assigning the next available value if the keyword is new
+func selectCode(keyword string) (code int, err error) {
+	selectMutex.Lock()
+	defer selectMutex.Unlock()
+	var ok bool
+	if code, ok = selectCodes[keyword]; ok {
+		return
+	}
+	if code = selectCodeBase + len(selectCodes); code >= ordinalCodeBase {
+		err = fmt.Errorf("icu: too many select keywords")
+		return
+	}
+	selectCodes[keyword] = code
+	return
+}
@@ -0,0 +1,205 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/go-corelibs/x-text/feature/plural"
+	"github.com/go-corelibs/x-text/language"
+	"github.com/go-corelibs/x-text/message/catalog"
+)
+
+// Resolver returns the one-based message.Printer argument number for the
+// given ICU argument name
+type Resolver func(name string) (argNum int, ok bool)
+
+// NumericResolver resolves ICU argument names which are zero-based argument
+// indexes, for example: {0} is the first argument
+func NumericResolver(name string) (argNum int, ok bool) {
+	if idx, err := strconv.Atoi(name); err == nil && idx >= 0 {
+		argNum, ok = idx+1, true
+	}
+	return
+}
+
+// Compile converts the message into the catalog messages to set for the given
+// language, plural cases are compiled into plural.Selectf messages while
+// select and selectordinal cases are matched using explicit values provided
+// by the Choose and Ordinal argument wrappers, see WrappedArgs
+//
+// Plural categories which are not used by the language are omitted and any
+// literal percent signs are escaped for message.Printer
+func (m *Message) Compile(tag language.Tag, resolve Resolver) (messages []catalog.Message, err error) {
+	c := &compiler{tag: tag, resolve: resolve}
+	var format string
+	if format, err = c.format(m.Nodes, 0); err != nil {
+		return
+	}
+	messages = append(c.vars, catalog.String(format))
+	return
+}
+
+type compiler struct {
+	tag     language.Tag
+	resolve Resolver
+	vars    []catalog.Message
+}
+
+func (c *compiler) argNum(name string) (argNum int, err error) {
+	var ok bool
+	if argNum, ok = c.resolve(name); !ok {
+		if argNum, ok = NumericResolver(name); !ok {
+			err = fmt.Errorf("icu: unknown argument: %q", name)
+		}
+	}
+	return
+}
+
+// format returns the message.Printer format string for the nodes given, any
+// choices are added to the compiler variables and substituted by name
+func (c *compiler) format(nodes []Node, pound int) (format string, err error) {
+	var buf strings.Builder
+	for _, node := range nodes {
+		switch t := node.(type) {
+
+		case Text:
+			text := strings.ReplaceAll(string(t), "%", "%%")
+			if strings.Contains(text, "${") {
+				err = fmt.Errorf("icu: literal \"${\" is not supported")
+				return
+			}
+			buf.WriteString(text)
+
+		case *Argument:
+			var argNum int
+			if argNum, err = c.argNum(t.Name); err != nil {
+				return
+			}
+			buf.WriteString("%[" + strconv.Itoa(argNum) + "]v")
+
+		case *Pound:
+			if pound <= 0 {
+				buf.WriteString("#")
+			} else {
+				buf.WriteString("%[" + strconv.Itoa(pound) + "]v")
+			}
+
+		case *Choice:
+			var msg catalog.Message
+			if msg, err = c.choice(t, pound); err != nil {
+				return
+			}
+			name := "icu" + strconv.Itoa(len(c.vars))
+			c.vars = append(c.vars, catalog.Var(name, msg))
+			buf.WriteString("${" + name + "}")
+
+		}
+	}
+	format = buf.String()
+	return
+}
+
+func (c *compiler) choice(choice *Choice, pound int) (msg catalog.Message, err error) {
+	var argNum int
+	if argNum, err = c.argNum(choice.Arg); err != nil {
+		return
+	}
+	if choice.Offset != 0 {
+		err = fmt.Errorf("icu: plural offset is not supported: %q", choice.Arg)
+		return
+	}
+
+	var forms []string
+	switch choice.Kind {
+	case Plural:
+		forms = CardinalForms(c.tag)
+		pound = argNum
+	case SelectOrdinal:
+		forms = OrdinalForms(c.tag)
+		pound = argNum
+	}
+
+	var cases, other []interface{}
+	for _, cc := range choice.Cases {
+		var selector string
+		switch {
+
+		case cc.Key == "other":
+			selector = "other"
+
+		case choice.Kind == Select:
+			var code int
+			if code, err = selectCode(cc.Key); err != nil {
+				return
+			}
+			selector = "=" + strconv.Itoa(code)
+
+		case strings.HasPrefix(cc.Key, "="):
+			if choice.Kind == SelectOrdinal {
+				err = fmt.Errorf("icu: explicit selectordinal values are not supported: %q", cc.Key)
+				return
+			} else if v, ee := strconv.Atoi(cc.Key[1:]); ee != nil || v > maxCode {
+				err = fmt.Errorf("icu: invalid explicit plural value: %q", cc.Key)
+				return
+			}
+			selector = cc.Key
+
+		case !IsFormName(cc.Key):
+			err = fmt.Errorf("icu: invalid %s category: %q", choice.Kind, cc.Key)
+			return
+
+		default:
+			var used bool
+			for _, form := range forms {
+				if used = form == cc.Key; used {
+					break
+				}
+			}
+			if !used {
+				// the language never selects this category
+				continue
+			}
+			if choice.Kind == SelectOrdinal && cc.Key != "other" {
+				// raw numbers are matched by the other case only
+				for form, name := range formLookup {
+					if name == cc.Key {
+						selector = "=" + strconv.Itoa(ordinalCode(form))
+						break
+					}
+				}
+			} else {
+				selector = cc.Key
+			}
+
+		}
+
+		var format string
+		if format, err = c.format(cc.Nodes, pound); err != nil {
+			return
+		}
+		if selector == "other" {
+			// other always matches and must be the last case
+			other = append(other, selector, format)
+		} else {
+			cases = append(cases, selector, format)
+		}
+	}
+
+	msg = plural.Selectf(argNum, "", append(cases, other...)...)
+	return
+}
@@ -0,0 +1,122 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icu
+
+import (
+	"sync"
+
+	"github.com/go-corelibs/x-text/feature/plural"
+	"github.com/go-corelibs/x-text/language"
+)
+
+var formNames = []string{"zero", "one", "two", "few", "many", "other"}
+
+var formLookup = map[plural.Form]string{
+	plural.Zero:  "zero",
+	plural.One:   "one",
+	plural.Two:   "two",
+	plural.Few:   "few",
+	plural.Many:  "many",
+	plural.Other: "other",
+}
+
+var (
+	formsCache = map[bool]map[language.Tag][]string{true: {}, false: {}}
+	formsMutex = &sync.RWMutex{}
+)
+
+// IsFormName returns true if the given key is one of the CLDR plural
+// categories: zero, one, two, few, many or other
+func IsFormName(key string) bool {
+	for _, name := range formNames {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
+
+// FormName returns the CLDR category name of the given plural form
+func FormName(form plural.Form) (name string) {
+	if name = formLookup[form]; name == "" {
+		name = "other"
+	}
+	return
+}
+
+// CardinalForms returns the CLDR plural categories used by the given
+// language for quantities, in CLDR order with "other" last
+func CardinalForms(tag language.Tag) (forms []string) {
+	return sampleForms(tag, false)
+}
+
+// OrdinalForms returns the CLDR plural categories used by the given language
+// for positions, in CLDR order with "other" last
+func OrdinalForms(tag language.Tag) (forms []string) {
+	return sampleForms(tag, true)
+}
+
+// OrdinalForm returns the CLDR ordinal plural form of n for the given language
+func OrdinalForm(tag language.Tag, n int) (form plural.Form) {
+	if n < 0 {
+		n = -n
+	}
+	form = plural.Ordinal.MatchPlural(tag, n%10000000, 0, 0, 0, 0)
+	return
+}
+
+// sampleForms derives the categories used by a language by matching a range
+// of integers (and decimals for cardinals) against its plural rules
+func sampleForms(tag language.Tag, ordinal bool) (forms []string) {
+	formsMutex.RLock()
+	cached, ok := formsCache[ordinal][tag]
+	formsMutex.RUnlock()
+	if ok {
+		return append(forms, cached...)
+	}
+
+	found := map[plural.Form]struct{}{plural.Other: {}}
+	rules := plural.Cardinal
+	if ordinal {
+		rules = plural.Ordinal
+	}
+	for i := 0; i <= 1000; i++ {
+		found[rules.MatchPlural(tag, i, 0, 0, 0, 0)] = struct{}{}
+	}
+	for _, i := range []int{10000, 100000, 1000000} {
+		found[rules.MatchPlural(tag, i, 0, 0, 0, 0)] = struct{}{}
+	}
+	if !ordinal {
+		for i := 0; i <= 10; i++ {
+			for f := 1; f <= 9; f++ {
+				found[rules.MatchPlural(tag, i, 1, 1, f, f)] = struct{}{}
+			}
+		}
+	}
+
+	for _, name := range formNames {
+		for form := range found {
+			if FormName(form) == name {
+				cached = append(cached, name)
+				break
+			}
+		}
+	}
+
+	formsMutex.Lock()
+	formsCache[ordinal][tag] = cached
+	formsMutex.Unlock()
+	return append(forms, cached...)
+}
@@ -0,0 +1,116 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package icu provides support for ICU MessageFormat strings with plural,
+// select and selectordinal arguments, compiled into catalog messages for
+// evaluation through message.Printer
+package icu
+
+import (
+	"regexp"
+
+	"github.com/go-corelibs/slices"
+)
+
+// Kind is the type of complex argument
+type Kind string
+
+const (
+	Plural        Kind = "plural"
+	Select        Kind = "select"
+	SelectOrdinal Kind = "selectordinal"
+)
+
+var rxMessageFormat = regexp.MustCompile(`\{\s*[\p{L}\p{N}_]+\s*,\s*(plural|select|selectordinal)\s*,`)
+
+// IsMessageFormat returns true if the given text contains at least one ICU
+// MessageFormat plural, select or selectordinal argument
+func IsMessageFormat(text string) bool {
+	return rxMessageFormat.MatchString(text)
+}
+
+// Node is one part of a parsed Message
+type Node interface {
+	isNode()
+}
+
+// Text is literal message text
+type Text string
+
+// Argument is a simple argument placeholder, for example: {name} or
+// {count, number}
+type Argument struct {
+	Name  string
+	Type  string
+	Style string
+}
+
+// Pound is the "#" placeholder within plural and selectordinal cases
+type Pound struct{}
+
+// Choice is a plural, select or selectordinal argument
+type Choice struct {
+	Kind   Kind
+	Arg    string
+	Offset int
+	Cases  []*Case
+}
+
+// Case is one of the selectable messages of a Choice
+type Case struct {
+	Key   string
+	Nodes []Node
+}
+
+// Message is a parsed ICU MessageFormat string
+type Message struct {
+	Source string
+	Nodes  []Node
+}
+
+func (Text) isNode()      {}
+func (*Argument) isNode() {}
+func (*Pound) isNode()    {}
+func (*Choice) isNode()   {}
+
+// Find returns the case with the given key, nil if not found
+func (c *Choice) Find(key string) (found *Case) {
+	for _, cc := range c.Cases {
+		if cc.Key == key {
+			return cc
+		}
+	}
+	return
+}
+
+// WrappedArgs returns the names of the select and selectordinal arguments,
+// which only select their cases when the argument values are given with the
+// Choose and Ordinal wrappers respectively
+func (m *Message) WrappedArgs() (names []string) {
+	var walk func(nodes []Node)
+	walk = func(nodes []Node) {
+		for _, node := range nodes {
+			if choice, ok := node.(*Choice); ok {
+				if choice.Kind != Plural && !slices.Present(choice.Arg, names...) {
+					names = append(names, choice.Arg)
+				}
+				for _, cc := range choice.Cases {
+					walk(cc.Nodes)
+				}
+			}
+		}
+	}
+	walk(m.Nodes)
+	return
+}
@@ -0,0 +1,283 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package icu
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// Parse parses the given ICU MessageFormat source text
+func Parse(source string) (m *Message, err error) {
+	p := &parser{src: []rune(source)}
+	var nodes []Node
+	if nodes, err = p.parseMessage(false, false); err != nil {
+		return
+	} else if p.pos < len(p.src) {
+		err = p.errorf("unexpected %q", p.src[p.pos])
+		return
+	}
+	m = &Message{Source: source, Nodes: nodes}
+	return
+}
+
+type parser struct {
+	src []rune
+	pos int
+}
+
+func (p *parser) errorf(format string, argv ...interface{}) error {
+	return fmt.Errorf("icu: offset %d: %s", p.pos, fmt.Sprintf(format, argv...))
+}
+
+func (p *parser) peek() (r rune, ok bool) {
+	if ok = p.pos < len(p.src); ok {
+		r = p.src[p.pos]
+	}
+	return
+}
+
+func (p *parser) skipSpace() {
+	for p.pos < len(p.src) && unicode.IsSpace(p.src[p.pos]) {
+		p.pos += 1
+	}
+}
+
+// parseMessage parses text and arguments until the end of the source or, when
+// nested, the closing brace of the enclosing case
+func (p *parser) parseMessage(nested, inPlural bool) (nodes []Node, err error) {
+	var buf strings.Builder
+	flush := func() {
+		if buf.Len() > 0 {
+			nodes = append(nodes, Text(buf.String()))
+			buf.Reset()
+		}
+	}
+
+	for p.pos < len(p.src) {
+		r := p.src[p.pos]
+		switch {
+
+		case r == '\'':
+			p.pos += 1
+			if next, ok := p.peek(); ok && next == '\'' {
+				// escaped apostrophe
+				buf.WriteRune('\'')
+				p.pos += 1
+			} else if ok && (next == '{' || next == '}' || next == '|' || (inPlural && next == '#')) {
+				// quoted literal text, until the next single apostrophe
+				for p.pos < len(p.src) {
+					if p.src[p.pos] == '\'' {
+						if p.pos+1 < len(p.src) && p.src[p.pos+1] == '\'' {
+							buf.WriteRune('\'')
+							p.pos += 2
+							continue
+						}
+						p.pos += 1
+						break
+					}
+					buf.WriteRune(p.src[p.pos])
+					p.pos += 1
+				}
+			} else {
+				buf.WriteRune('\'')
+			}
+
+		case r == '#' && inPlural:
+			flush()
+			nodes = append(nodes, &Pound{})
+			p.pos += 1
+
+		case r == '{':
+			flush()
+			p.pos += 1
+			var node Node
+			if node, err = p.parseArgument(inPlural); err != nil {
+				return
+			}
+			nodes = append(nodes, node)
+
+		case r == '}':
+			if !nested {
+				err = p.errorf("unexpected closing brace")
+				return
+			}
+			flush()
+			return
+
+		default:
+			buf.WriteRune(r)
+			p.pos += 1
+		}
+	}
+
+	if nested {
+		err = p.errorf("missing closing brace")
+		return
+	}
+	flush()
+	return
+}
+
+func (p *parser) parseIdentifier() (ident string) {
+	start := p.pos
+	for p.pos < len(p.src) {
+		if r := p.src[p.pos]; r == '_' || unicode.IsLetter(r) || unicode.IsDigit(r) {
+			p.pos += 1
+			continue
+		}
+		break
+	}
+	ident = string(p.src[start:p.pos])
+	return
+}
+
+// parseArgument parses the contents of an argument, after the opening brace
+// and including the closing brace
+func (p *parser) parseArgument(inPlural bool) (node Node, err error) {
+	p.skipSpace()
+	var name string
+	if name = p.parseIdentifier(); name == "" {
+		err = p.errorf("missing argument name")
+		return
+	}
+	p.skipSpace()
+
+	r, ok := p.peek()
+	if !ok {
+		err = p.errorf("missing closing brace")
+		return
+	} else if r == '}' {
+		p.pos += 1
+		node = &Argument{Name: name}
+		return
+	} else if r != ',' {
+		err = p.errorf("unexpected %q in argument", r)
+		return
+	}
+	p.pos += 1
+	p.skipSpace()
+
+	var kind string
+	if kind = p.parseIdentifier(); kind == "" {
+		err = p.errorf("missing argument type")
+		return
+	}
+	p.skipSpace()
+
+	switch Kind(kind) {
+	case Plural, Select, SelectOrdinal:
+		if r, ok = p.peek(); !ok || r != ',' {
+			err = p.errorf("missing %s cases", kind)
+			return
+		}
+		p.pos += 1
+		node, err = p.parseChoice(Kind(kind), name, inPlural)
+		return
+	}
+
+	argument := &Argument{Name: name, Type: kind}
+	if r, ok = p.peek(); ok && r == ',' {
+		p.pos += 1
+		start := p.pos
+		for p.pos < len(p.src) && p.src[p.pos] != '}' {
+			p.pos += 1
+		}
+		argument.Style = strings.TrimSpace(string(p.src[start:p.pos]))
+	}
+	if r, ok = p.peek(); !ok || r != '}' {
+		err = p.errorf("missing closing brace")
+		return
+	}
+	p.pos += 1
+	node = argument
+	return
+}
+
+func (p *parser) parseChoice(kind Kind, name string, inPlural bool) (node Node, err error) {
+	choice := &Choice{Kind: kind, Arg: name}
+	numeric := kind != Select
+	unique := make(map[string]struct{})
+
+	for {
+		p.skipSpace()
+		r, ok := p.peek()
+		if !ok {
+			err = p.errorf("missing closing brace")
+			return
+		} else if r == '}' {
+			p.pos += 1
+			break
+		}
+
+		var key string
+		if r == '=' && numeric {
+			p.pos += 1
+			var digits string
+			if digits = p.parseIdentifier(); digits == "" {
+				err = p.errorf("missing explicit value")
+				return
+			} else if _, ee := strconv.Atoi(digits); ee != nil {
+				err = p.errorf("invalid explicit value: %q", digits)
+				return
+			}
+			key = "=" + digits
+		} else if key = p.parseIdentifier(); key == "" {
+			err = p.errorf("unexpected %q in %s", r, kind)
+			return
+		}
+
+		if key == "offset" && kind == Plural && len(choice.Cases) == 0 {
+			if r, ok = p.peek(); ok && r == ':' {
+				p.pos += 1
+				p.skipSpace()
+				if choice.Offset, err = strconv.Atoi(p.parseIdentifier()); err != nil {
+					err = p.errorf("invalid plural offset")
+					return
+				}
+				continue
+			}
+		}
+
+		if _, present := unique[key]; present {
+			err = p.errorf("duplicate %s case: %q", kind, key)
+			return
+		}
+		unique[key] = struct{}{}
+
+		p.skipSpace()
+		if r, ok = p.peek(); !ok || r != '{' {
+			err = p.errorf("missing %s case message: %q", kind, key)
+			return
+		}
+		p.pos += 1
+
+		c := &Case{Key: key}
+		if c.Nodes, err = p.parseMessage(true, numeric || inPlural); err != nil {
+			return
+		}
+		p.pos += 1 // closing brace of the case message
+		choice.Cases = append(choice.Cases, c)
+	}
+
+	if choice.Find("other") == nil {
+		err = p.errorf("%s is missing the required \"other\" case", kind)
+		return
+	}
+	node = choice
+	return
+}
@@ -0,0 +1,304 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lang
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/go-corelibs/x-text/language"
+
+	"github.com/go-enjin/be/pkg/forms"
+)
+
+var (
+	_ Mode            = (*NegotiateMode)(nil)
+	_ NegotiatingMode = (*NegotiateMode)(nil)
+)
+
+const (
+	NegotiatedByPath    = "path"
+	NegotiatedByQuery   = "query"
+	NegotiatedByCookie  = "cookie"
+	NegotiatedByHeader  = "header"
+	NegotiatedByDefault = "default"
+)
+
+// NegotiatingMode is a Mode which selects the best supported language for a
+// request instead of requiring the language to be present in the URL
+type NegotiatingMode interface {
+	Mode
+
+	// Negotiate selects the request language from the languages supported
+	Negotiate(defaultTag language.Tag, supported []language.Tag, r *http.Request) (n *Negotiation)
+	// MakeCookie returns the cookie remembering the given language choice
+	MakeCookie(tag language.Tag, r *http.Request) (cookie *http.Cookie)
+}
+
+// Negotiation is the result of a NegotiatingMode selecting a request language
+type Negotiation struct {
+	// Tag is the language selected
+	Tag language.Tag
+	// Path is the request path, without any language prefix
+	Path string
+	// Source describes how the language was selected, one of the
+	// NegotiatedBy constants
+	Source string
+	// Explicit is true when the visitor chose the language with the URL and
+	// the choice is to be remembered
+	Explicit bool
+	// Redirect is the localized URL to send first-time visitors to, empty
+	// when no redirection is necessary
+	Redirect string
+}
+
+type negotiationKey struct{}
+
+// SetNegotiation returns a copy of r with the given negotiation included, for
+// later handlers to act upon the visitor's language choice
+func SetNegotiation(r *http.Request, negotiated *Negotiation) (modified *http.Request) {
+	modified = r.Clone(context.WithValue(r.Context(), negotiationKey{}, negotiated))
+	return
+}
+
+// GetNegotiation returns the negotiation included with SetNegotiation, nil if
+// the request language was not negotiated
+func GetNegotiation(r *http.Request) (negotiated *Negotiation) {
+	negotiated, _ = r.Context().Value(negotiationKey{}).(*Negotiation)
+	return
+}
+
+type NegotiateMode struct {
+	query    string
+	cookie   string
+	maxAge   time.Duration
+	secure   bool
+	path     *PathMode
+	redirect bool
+}
+
+type NegotiateModeBuilder interface {
+	// SetQueryParameter specifies the URL query parameter used to explicitly
+	// choose a language, default is "lang"
+	SetQueryParameter(name string) NegotiateModeBuilder
+	// SetCookieName specifies the name of the cookie remembering an explicit
+	// language choice, default is "lang"
+	SetCookieName(name string) NegotiateModeBuilder
+	// SetCookieMaxAge specifies how long an explicit language choice is
+	// remembered, default is one year
+	SetCookieMaxAge(maxAge time.Duration) NegotiateModeBuilder
+	// SetSecureCookie specifies if the cookie is always secure, the cookie is
+	// otherwise secure only for TLS requests
+	SetSecureCookie(secure bool) NegotiateModeBuilder
+	// SetPathMode combines negotiation with the given path mode, language
+	// path prefixes are explicit choices and URLs are made with path prefixes,
+	// unprefixed paths are negotiated
+	SetPathMode(p PathModeBuilder) NegotiateModeBuilder
+	// SetRedirectFirstVisit redirects unprefixed requests to the localized
+	// path of the remembered or negotiated language, requires SetPathMode
+	SetRedirectFirstVisit(enabled bool) NegotiateModeBuilder
+
+	Make() Mode
+}
+
+func NewNegotiateMode() (n NegotiateModeBuilder) {
+	n = &NegotiateMode{
+		query:  "lang",
+		cookie: "lang",
+		maxAge: time.Hour * 24 * 365,
+	}
+	return
+}
+
+func (n *NegotiateMode) SetQueryParameter(name string) NegotiateModeBuilder {
+	n.query = name
+	return n
+}
+
+func (n *NegotiateMode) SetCookieName(name string) NegotiateModeBuilder {
+	n.cookie = name
+	return n
+}
+
+func (n *NegotiateMode) SetCookieMaxAge(maxAge time.Duration) NegotiateModeBuilder {
+	n.maxAge = maxAge
+	return n
+}
+
+func (n *NegotiateMode) SetSecureCookie(secure bool) NegotiateModeBuilder {
+	n.secure = secure
+	return n
+}
+
+func (n *NegotiateMode) SetPathMode(p PathModeBuilder) NegotiateModeBuilder {
+	if pm, ok := p.Make().(*PathMode); ok {
+		n.path = pm
+	}
+	return n
+}
+
+func (n *NegotiateMode) SetRedirectFirstVisit(enabled bool) NegotiateModeBuilder {
+	n.redirect = enabled
+	return n
+}
+
+func (n *NegotiateMode) Make() Mode {
+	return n
+}
+
+func (n *NegotiateMode) Name() (name string) {
+	name = "negotiate"
+	return
+}
+
+func (n *NegotiateMode) ToUrl(defaultTag, tag language.Tag, path string) (translated string) {
+	if n.path != nil {
+		translated = n.path.ToUrl(defaultTag, tag, path)
+		return
+	}
+	// the default language is included so that choosing it is explicit and
+	// replaces any previous choice remembered by the cookie
+	translated = path + fmt.Sprintf("?%v=%v", n.query, tag.String())
+	return
+}
+
+// FromRequest implements Mode, without the list of supported languages the
+// first language of the Accept-Language header is used
+func (n *NegotiateMode) FromRequest(defaultTag language.Tag, r *http.Request) (tag language.Tag, path string, ok bool) {
+	negotiated := n.Negotiate(defaultTag, nil, r)
+	tag, path, ok = negotiated.Tag, negotiated.Path, true
+	return
+}
+
+// Negotiate selects the request language, in order of precedence: the path
+// prefix (when combined with path mode), the query parameter, the cookie, the
+// best match for the Accept-Language header q-values and finally the default
+// language; a nil supported list accepts any language
+//
+// Only the path prefix and the query parameter are explicit choices, when
+// combined with path mode an unprefixed path uses the remembered cookie
+// language (or the negotiated language) and is redirected to the localized
+// path when redirection is enabled
+func (n *NegotiateMode) Negotiate(defaultTag language.Tag, supported []language.Tag, r *http.Request) (negotiated *Negotiation) {
+	negotiated = &Negotiation{
+		Tag:    defaultTag,
+		Path:   forms.CleanRequestPath(r.URL.Path),
+		Source: NegotiatedByDefault,
+	}
+
+	if n.path != nil {
+		if tag, trimmed, ok := n.path.ParsePathLang(negotiated.Path); ok {
+			if found, present := findSupported(tag, supported); present {
+				negotiated.Tag, negotiated.Path = found, trimmed
+				negotiated.Source, negotiated.Explicit = NegotiatedByPath, true
+				return
+			}
+		}
+	}
+
+	if values, present := r.URL.Query()[n.query]; present && len(values) >= 1 {
+		if tag, err := language.Parse(values[0]); err == nil {
+			if found, ok := findSupported(tag, supported); ok {
+				negotiated.Tag = found
+				negotiated.Source, negotiated.Explicit = NegotiatedByQuery, true
+				return
+			}
+		}
+	}
+
+	if cookie, err := r.Cookie(n.cookie); err == nil && cookie.Value != "" {
+		if tag, ee := language.Parse(cookie.Value); ee == nil {
+			if found, ok := findSupported(tag, supported); ok {
+				negotiated.Tag, negotiated.Source = found, NegotiatedByCookie
+				negotiated.Redirect = n.makeRedirect(defaultTag, found, negotiated.Path, r)
+				return
+			}
+		}
+	}
+
+	if header := r.Header.Get("Accept-Language"); header != "" {
+		if tag, ok := matchAcceptLanguage(header, supported); ok {
+			negotiated.Tag, negotiated.Source = tag, NegotiatedByHeader
+			negotiated.Redirect = n.makeRedirect(defaultTag, tag, negotiated.Path, r)
+		}
+	}
+
+	return
+}
+
+// makeRedirect returns the localized URL for the given unprefixed path, empty
+// when not combined with path mode, redirection is not enabled or the tag is
+// the default language
+func (n *NegotiateMode) makeRedirect(defaultTag, tag language.Tag, path string, r *http.Request) (redirect string) {
+	if n.path != nil && n.redirect && r.Method == http.MethodGet && !language.Compare(tag, defaultTag) {
+		redirect = n.path.ToUrl(defaultTag, tag, path)
+		if r.URL.RawQuery != "" {
+			redirect += "?" + r.URL.RawQuery
+		}
+	}
+	return
+}
+
+func (n *NegotiateMode) MakeCookie(tag language.Tag, r *http.Request) (cookie *http.Cookie) {
+	cookie = &http.Cookie{
+		Name:     n.cookie,
+		Value:    tag.String(),
+		Path:     "/",
+		MaxAge:   int(n.maxAge.Seconds()),
+		Secure:   n.secure || r.TLS != nil,
+		HttpOnly: true,
+		SameSite: http.SameSiteLaxMode,
+	}
+	return
+}
+
+// findSupported returns the supported language equivalent to the given tag, a
+// nil supported list accepts any language
+func findSupported(tag language.Tag, supported []language.Tag) (found language.Tag, ok bool) {
+	if supported == nil {
+		found, ok = tag, tag != language.Und
+		return
+	}
+	for _, known := range supported {
+		if language.Compare(known, tag) {
+			found, ok = known, true
+			return
+		}
+	}
+	return
+}
+
+// matchAcceptLanguage returns the best supported language for the given
+// Accept-Language header value, honouring q-values
+func matchAcceptLanguage(header string, supported []language.Tag) (tag language.Tag, ok bool) {
+	var err error
+	var desired []language.Tag
+	if desired, _, err = language.ParseAcceptLanguage(header); err != nil || len(desired) == 0 {
+		return
+	} else if supported == nil {
+		tag, ok = desired[0], true
+		return
+	} else if len(supported) == 0 {
+		return
+	}
+	matcher := language.NewMatcher(supported)
+	_, index, confidence := matcher.Match(desired...)
+	if ok = confidence != language.No; ok {
+		tag = supported[index]
+	}
+	return
+}
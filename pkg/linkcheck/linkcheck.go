@@ -0,0 +1,354 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package linkcheck renders all pages of an enjin in-process and checks that
+// every internal href, src and srcset reference resolves to a page, a public
+// or theme static file or any other route.
+package linkcheck
+
+import (
+	"crypto/tls"
+	"fmt"
+	"mime"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sort"
+	"time"
+
+	"github.com/go-corelibs/x-text/language"
+
+	clPath "github.com/go-corelibs/path"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/log"
+	"github.com/go-enjin/be/pkg/maps"
+)
+
+// MaxRedirects is the maximum number of redirects followed for a single link
+const MaxRedirects = 10
+
+// pageUrlsProvider is implemented by page indexing features (pages-pql)
+type pageUrlsProvider interface {
+	UnsafeAllUrls() (store feature.KeyValueStore)
+}
+
+// Checker renders pages through an http.Handler (typically the enjin router)
+// and resolves all internal links found
+type Checker struct {
+	enjin   feature.Internals
+	handler http.Handler
+	siteUrl *url.URL
+
+	hosts    map[string]struct{}
+	linked   map[string]struct{}
+	resolved map[string]*resolution
+}
+
+type target struct {
+	tag  language.Tag
+	key  string
+	kind string
+	u    *url.URL
+}
+
+type resolution struct {
+	ok     bool
+	page   feature.Page
+	key    string
+	status int
+	chain  []string
+	reason string
+}
+
+// New constructs a new Checker instance, siteUrl is used to construct all
+// requests and to determine which links are internal
+func New(enjin feature.Internals, handler http.Handler, siteUrl *url.URL) (c *Checker) {
+	c = &Checker{
+		enjin:   enjin,
+		handler: handler,
+		siteUrl: siteUrl,
+	}
+	return
+}
+
+// PageUrls returns all page URLs known to the enjin page providers
+func PageUrls(enjin feature.Internals) (urls []string) {
+	unique := make(map[string]struct{})
+	for _, f := range feature.FilterTyped[pageUrlsProvider](enjin.Features().List()) {
+		if store, ok := f.UnsafeAllUrls().(feature.ExtendedKeyValueStore); ok {
+			for pageUrl := range store.StreamKeys("", nil) {
+				unique[pageUrl] = struct{}{}
+			}
+		} else {
+			log.WarnF("%v all urls store does not support listing keys", f.(feature.Feature).Tag())
+		}
+	}
+	for pageUrl := range enjin.Pages() {
+		unique[pageUrl] = struct{}{}
+	}
+	urls = maps.SortedKeys(unique)
+	return
+}
+
+// Run renders and checks all pages, Run is not safe for concurrent use
+func (c *Checker) Run() (report *Report) {
+	report = &Report{Started: time.Now()}
+	c.hosts = map[string]struct{}{c.siteUrl.Host: {}}
+	c.linked = make(map[string]struct{})
+	c.resolved = make(map[string]*resolution)
+
+	targets := c.pageTargets()
+	for _, t := range targets {
+		c.hosts[t.u.Host] = struct{}{}
+	}
+
+	for _, t := range targets {
+		report.Pages += 1
+		w := c.serve(t.u)
+		if w.Code != http.StatusOK {
+			if t.kind != "status" {
+				report.add(&Issue{
+					Kind:     Broken,
+					Page:     t.key,
+					Language: t.tag.String(),
+					Status:   w.Code,
+					Reason:   "page responded with: " + http.StatusText(w.Code),
+				})
+			}
+			continue
+		}
+		if mimeType, _, _ := mime.ParseMediaType(w.Header().Get("Content-Type")); mimeType != "text/html" {
+			continue
+		}
+		for _, link := range ParseLinks(w.Body) {
+			report.Links += 1
+			c.checkLink(report, t, link)
+		}
+	}
+
+	for _, t := range targets {
+		if t.u.Path == "/" || t.kind == "status" {
+			continue
+		}
+		if _, linked := c.linked[t.key]; !linked {
+			report.add(&Issue{
+				Kind:     Orphan,
+				Page:     t.key,
+				Language: t.tag.String(),
+				Reason:   "not linked from any other page",
+			})
+		}
+	}
+
+	sort.SliceStable(report.Issues, func(i, j int) bool {
+		if report.Issues[i].Kind == report.Issues[j].Kind {
+			return report.Issues[i].Page < report.Issues[j].Page
+		}
+		return report.Issues[i].Kind < report.Issues[j].Kind
+	})
+	report.Duration = time.Since(report.Started).Round(time.Millisecond).String()
+	return
+}
+
+// pageTargets returns all pages, in each of their available languages
+func (c *Checker) pageTargets() (targets []*target) {
+	langMode := c.enjin.SiteLanguageMode()
+	defaultTag := c.enjin.SiteDefaultLanguage()
+	var locales []language.Tag
+	for _, tag := range c.enjin.SiteLocales() {
+		if c.enjin.SiteSupportsLanguage(tag) {
+			locales = append(locales, tag)
+		}
+	}
+	if len(locales) == 0 {
+		locales = append(locales, defaultTag)
+	}
+
+	r := c.newRequest(c.siteUrl)
+	for _, pageUrl := range PageUrls(c.enjin) {
+		for _, tag := range locales {
+			p := c.enjin.FindPage(r, tag, pageUrl)
+			if p == nil {
+				continue
+			} else if !language.Compare(p.LanguageTag(), tag) {
+				if p.LanguageTag() != language.Und || !language.Compare(tag, defaultTag) {
+					// fallback pages are checked with their own language
+					continue
+				}
+			}
+			translated := langMode.ToUrl(defaultTag, tag, pageUrl)
+			if u, err := c.siteUrl.Parse(translated); err == nil {
+				targets = append(targets, &target{tag: tag, key: translated, kind: p.Type(), u: u})
+			}
+		}
+	}
+	return
+}
+
+func (c *Checker) checkLink(report *Report, from *target, link Link) {
+	u, err := from.u.Parse(link.Value)
+	if err != nil {
+		report.add(&Issue{
+			Kind:     Broken,
+			Page:     from.key,
+			Language: from.tag.String(),
+			Link:     link.Value,
+			Attr:     link.Attr,
+			Reason:   fmt.Sprintf("invalid url: %v", err),
+		})
+		return
+	} else if u.Scheme != "http" && u.Scheme != "https" {
+		return
+	} else if !c.internal(u.Host) {
+		return
+	}
+	if u.Path == "" {
+		u.Path = "/"
+	}
+
+	res, found := c.resolved[u.Host+u.Path]
+	if !found {
+		res = c.resolve(u)
+		c.resolved[u.Host+u.Path] = res
+	}
+
+	if res.key != "" && res.key != from.key {
+		c.linked[res.key] = struct{}{}
+	}
+
+	issue := &Issue{
+		Page:     from.key,
+		Language: from.tag.String(),
+		Link:     link.Value,
+		Attr:     link.Attr,
+		Status:   res.status,
+		Chain:    res.chain,
+		Reason:   res.reason,
+	}
+
+	switch {
+	case !res.ok:
+		issue.Kind = Broken
+		report.add(issue)
+	case len(res.chain) > 0:
+		issue.Kind = Redirect
+		report.add(issue)
+	case res.page != nil && res.page.LanguageTag() != language.Und:
+		if language.Compare(from.tag, res.page.LanguageTag()) {
+			return
+		}
+		r := c.newRequest(u)
+		if p := c.enjin.FindPage(r, from.tag, res.page.Url()); p == nil || !language.Compare(p.LanguageTag(), from.tag) {
+			issue.Kind = MissingTranslation
+			issue.Reason = fmt.Sprintf("%v is not available in %v", res.page.Url(), from.tag)
+			report.add(issue)
+		}
+	}
+}
+
+// resolve looks for the page, public file or theme static file at the given
+// URL, falling back to requesting the URL and following any redirects
+func (c *Checker) resolve(u *url.URL) (res *resolution) {
+	res = &resolution{}
+	langMode := c.enjin.SiteLanguageMode()
+	defaultTag := c.enjin.SiteDefaultLanguage()
+
+	r := c.newRequest(u)
+	if tag, path, ok := langMode.FromRequest(defaultTag, r); ok {
+		if p := c.enjin.FindPage(r, tag, path); p != nil {
+			res.ok = true
+			res.page = p
+			res.key = langMode.ToUrl(defaultTag, tag, p.Url())
+			return
+		}
+	}
+
+	if c.enjin.PublicFileSystems().Lookup().FileExists(u.Path) {
+		res.ok = true
+		return
+	}
+
+	if t, err := c.enjin.GetTheme(); err == nil {
+		if _, _, ee := t.ReadStaticFile(clPath.TrimSlashes(u.Path)); ee == nil {
+			res.ok = true
+			return
+		}
+	}
+
+	next := u
+	for count := 0; count <= MaxRedirects; count++ {
+		w := c.serve(next)
+		res.status = w.Code
+
+		if w.Code < 300 || w.Code >= 400 {
+			if res.ok = w.Code == http.StatusOK; !res.ok {
+				res.reason = http.StatusText(w.Code)
+			} else if len(res.chain) > 0 {
+				r = c.newRequest(next)
+				if tag, path, ok := langMode.FromRequest(defaultTag, r); ok {
+					if p := c.enjin.FindPage(r, tag, path); p != nil {
+						res.key = langMode.ToUrl(defaultTag, tag, p.Url())
+					}
+				}
+			}
+			return
+		}
+
+		location := w.Header().Get("Location")
+		if location == "" {
+			res.reason = "redirect without a location"
+			return
+		}
+		var err error
+		if next, err = next.Parse(location); err != nil {
+			res.reason = fmt.Sprintf("invalid redirect location: %v", err)
+			return
+		}
+		res.chain = append(res.chain, location)
+		if !c.internal(next.Host) {
+			// external redirects are not followed
+			res.ok = true
+			return
+		}
+	}
+
+	res.ok = false
+	res.reason = "too many redirects"
+	return
+}
+
+func (c *Checker) internal(host string) (ok bool) {
+	if host == "" {
+		return true
+	}
+	_, ok = c.hosts[host]
+	return
+}
+
+func (c *Checker) newRequest(u *url.URL) (r *http.Request) {
+	r, _ = http.NewRequest(http.MethodGet, u.String(), nil)
+	r.RemoteAddr = "127.0.0.1:0"
+	r.RequestURI = u.RequestURI()
+	if u.Scheme == "https" {
+		// request.ParseDomainUrl depends on r.TLS
+		r.TLS = &tls.ConnectionState{}
+	}
+	return
+}
+
+func (c *Checker) serve(u *url.URL) (w *httptest.ResponseRecorder) {
+	w = httptest.NewRecorder()
+	c.handler.ServeHTTP(w, c.newRequest(u))
+	return
+}
@@ -0,0 +1,69 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkcheck
+
+import (
+	"io"
+	"strings"
+
+	"golang.org/x/net/html"
+)
+
+// Link is a single URL reference found within a rendered page
+type Link struct {
+	// Tag is the HTML element name
+	Tag string
+	// Attr is the attribute the URL was found in (href, src or srcset)
+	Attr string
+	// Value is the URL as written in the page
+	Value string
+}
+
+// ParseLinks returns all href, src and srcset URL references found within the
+// given HTML document
+func ParseLinks(r io.Reader) (links []Link) {
+	z := html.NewTokenizer(r)
+	for {
+		switch z.Next() {
+		case html.ErrorToken:
+			return
+		case html.StartTagToken, html.SelfClosingTagToken:
+			token := z.Token()
+			for _, attr := range token.Attr {
+				switch strings.ToLower(attr.Key) {
+				case "href", "src":
+					if value := strings.TrimSpace(attr.Val); value != "" {
+						links = append(links, Link{Tag: token.Data, Attr: attr.Key, Value: value})
+					}
+				case "srcset":
+					for _, value := range parseSrcSet(attr.Val) {
+						links = append(links, Link{Tag: token.Data, Attr: attr.Key, Value: value})
+					}
+				}
+			}
+		}
+	}
+}
+
+// parseSrcSet returns the URLs of a srcset attribute value, dropping the width
+// and pixel density descriptors
+func parseSrcSet(value string) (urls []string) {
+	for _, candidate := range strings.Split(value, ",") {
+		if fields := strings.Fields(candidate); len(fields) > 0 {
+			urls = append(urls, fields[0])
+		}
+	}
+	return
+}
@@ -0,0 +1,153 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package linkcheck
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"time"
+)
+
+// IssueKind identifies the type of problem found
+type IssueKind string
+
+const (
+	// Broken links do not resolve to any page, file or route
+	Broken IssueKind = "broken"
+	// MissingTranslation links resolve to a page that is not available in
+	// the language of the linking page
+	MissingTranslation IssueKind = "missing-translation"
+	// Redirect links resolve only after following one or more redirects
+	Redirect IssueKind = "redirect"
+	// Orphan pages are not linked to from any other page
+	Orphan IssueKind = "orphan"
+)
+
+// IssueKinds is the list of all IssueKind values
+var IssueKinds = []IssueKind{Broken, MissingTranslation, Redirect, Orphan}
+
+// ParseIssueKind returns the IssueKind matching the given name
+func ParseIssueKind(name string) (kind IssueKind, ok bool) {
+	for _, known := range IssueKinds {
+		if ok = strings.EqualFold(string(known), name); ok {
+			kind = known
+			return
+		}
+	}
+	return
+}
+
+// Issue describes a single problem found with a link or page
+type Issue struct {
+	Kind IssueKind `json:"kind"`
+	// Page is the URL path of the page the link was found on (or the orphaned
+	// page itself)
+	Page string `json:"page"`
+	// Language is the language of the page
+	Language string `json:"language,omitempty"`
+	// Link is the URL as written in the page
+	Link string `json:"link,omitempty"`
+	// Attr is the attribute the link was found in
+	Attr string `json:"attr,omitempty"`
+	// Status is the final HTTP status code of the link, if requested
+	Status int `json:"status,omitempty"`
+	// Chain is the list of redirect locations followed
+	Chain []string `json:"chain,omitempty"`
+	// Reason is a human-readable description of the issue
+	Reason string `json:"reason,omitempty"`
+}
+
+func (i *Issue) String() (text string) {
+	text = fmt.Sprintf("[%v] %v", i.Kind, i.Page)
+	if i.Language != "" {
+		text += " (" + i.Language + ")"
+	}
+	if i.Link != "" {
+		text += " " + i.Attr + "=" + i.Link
+	}
+	if len(i.Chain) > 0 {
+		text += " -> " + strings.Join(i.Chain, " -> ")
+	}
+	if i.Status > 0 {
+		text += fmt.Sprintf(" [%d]", i.Status)
+	}
+	if i.Reason != "" {
+		text += ": " + i.Reason
+	}
+	return
+}
+
+// Report is the result of checking all pages of an enjin
+type Report struct {
+	Started  time.Time `json:"started"`
+	Duration string    `json:"duration"`
+	Pages    int       `json:"pages"`
+	Links    int       `json:"links"`
+	Issues   []*Issue  `json:"issues"`
+}
+
+// Count returns the number of issues of the given kind
+func (r *Report) Count(kind IssueKind) (count int) {
+	for _, issue := range r.Issues {
+		if issue.Kind == kind {
+			count += 1
+		}
+	}
+	return
+}
+
+// Filter returns the issues of the given kind
+func (r *Report) Filter(kind IssueKind) (issues []*Issue) {
+	for _, issue := range r.Issues {
+		if issue.Kind == kind {
+			issues = append(issues, issue)
+		}
+	}
+	return
+}
+
+// Failed returns true if there are any issues of the given kinds, or of any
+// kind if none are given
+func (r *Report) Failed(kinds ...IssueKind) (failed bool) {
+	if len(kinds) == 0 {
+		return len(r.Issues) > 0
+	}
+	for _, kind := range kinds {
+		if r.Count(kind) > 0 {
+			return true
+		}
+	}
+	return
+}
+
+// WriteText writes a human-readable summary of the report, one issue per line
+func (r *Report) WriteText(w io.Writer) (err error) {
+	for _, issue := range r.Issues {
+		if _, err = fmt.Fprintln(w, issue.String()); err != nil {
+			return
+		}
+	}
+	_, err = fmt.Fprintf(w,
+		"checked %d links on %d pages in %v: %d broken, %d missing translations, %d redirects, %d orphans\n",
+		r.Links, r.Pages, r.Duration,
+		r.Count(Broken), r.Count(MissingTranslation), r.Count(Redirect), r.Count(Orphan),
+	)
+	return
+}
+
+func (r *Report) add(issue *Issue) {
+	r.Issues = append(r.Issues, issue)
+}
@@ -0,0 +1,303 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package lint checks the content files of all mounted page filesystems for
+// front-matter syntax errors, unknown theme layouts, missing or invalid page
+// context fields, unsupported page formats and duplicate page URLs.
+package lint
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/go-corelibs/slices"
+	"github.com/go-corelibs/x-text/message"
+
+	clPath "github.com/go-corelibs/path"
+	"github.com/go-enjin/be/pkg/context"
+	"github.com/go-enjin/be/pkg/editor"
+	"github.com/go-enjin/be/pkg/feature"
+	"github.com/go-enjin/be/pkg/maps"
+	"github.com/go-enjin/be/types/page"
+	"github.com/go-enjin/be/types/page/matter"
+)
+
+// Linter walks all PageFileSystemFeature mount points of an enjin
+type Linter struct {
+	enjin   feature.Internals
+	theme   feature.Theme
+	request *http.Request
+
+	fields     context.Fields
+	archetypes map[string]context.Fields
+	urls       map[string]string
+
+	report *Report
+}
+
+// New constructs a new Linter instance, the enjin is expected to have
+// already started all features
+func New(enjin feature.Internals) (l *Linter) {
+	l = &Linter{
+		enjin:   enjin,
+		request: httptest.NewRequest(http.MethodGet, "/", nil),
+	}
+	return
+}
+
+// Run lints all files of all mounted content filesystems
+func (l *Linter) Run() (report *Report, err error) {
+	if l.theme, err = l.enjin.GetTheme(); err != nil {
+		err = fmt.Errorf("error getting enjin theme: %v", err)
+		return
+	}
+
+	l.fields = l.enjin.MakePageContextFields(l.request)
+	l.archetypes = make(map[string]context.Fields)
+	l.urls = make(map[string]string)
+	l.report = &Report{Started: time.Now()}
+
+	for _, pfs := range feature.FilterTyped[feature.PageFileSystemFeature](l.enjin.Features().List()) {
+		mounted := pfs.GetMountedPoints()
+		for _, point := range maps.SortedKeys(mounted) {
+			for _, mp := range mounted[point] {
+				l.lintMountPoint(pfs.Tag().String(), mp)
+			}
+		}
+	}
+
+	l.report.sort()
+	l.report.Duration = time.Now().Sub(l.report.Started).String()
+	report = l.report
+	return
+}
+
+func (l *Linter) lintMountPoint(origin string, mp *feature.CMountPoint) {
+	files, err := mp.ROFS.ListAllFiles(".")
+	if err != nil {
+		l.report.add(&Problem{
+			Severity: Error,
+			Rule:     ReadFile,
+			File:     mp.Path,
+			Message:  fmt.Sprintf("error listing files: %v", err),
+		})
+		return
+	}
+	for _, file := range files {
+		if _, _, ok := editor.ParseEditorWorkFile(file); ok {
+			continue
+		}
+		l.lintFile(origin, mp, file)
+	}
+}
+
+func (l *Linter) lintFile(origin string, mp *feature.CMountPoint, file string) {
+	l.report.Files += 1
+	name := filepath.Join(mp.Path, file)
+
+	problem := func(severity Severity, rule Rule, line int, format string, argv ...interface{}) *Problem {
+		p := &Problem{
+			Severity: severity,
+			Rule:     rule,
+			File:     name,
+			Line:     line,
+			Message:  fmt.Sprintf(format, argv...),
+		}
+		l.report.add(p)
+		return p
+	}
+
+	data, err := mp.ROFS.ReadFile(file)
+	if err != nil {
+		problem(Error, ReadFile, 0, "error reading file: %v", err)
+		return
+	}
+
+	frontMatter, _, matterType := matter.ParseContent(string(data))
+	offset := matterLineOffset(matterType)
+	lineOf := func(key string) (line int) {
+		if line = keyLine(frontMatter, matterType, key); line > 0 {
+			line += offset
+		}
+		return
+	}
+
+	if format, _ := l.theme.MatchFormat(file); format == nil {
+		problem(Error, PageFormat, 0, "file extension %q does not match any page format: %v",
+			filepath.Ext(file), strings.Join(l.theme.ListFormats(), ", "))
+	}
+
+	_, _, created, updated, _ := mp.ROFS.FileStats(file)
+	pm, err := matter.ParsePageMatter(origin, file, created, updated, data)
+	if err != nil {
+		line := errorLine(frontMatter, matterType)
+		if line > 0 {
+			line += offset
+		}
+		problem(Error, FrontMatter, line, "%v", err)
+		return
+	}
+
+	pg, err := page.NewFromPageMatter(pm, l.theme, l.enjin.Context(l.request))
+	if err != nil {
+		problem(Error, PageFormat, 0, "error preparing page: %v", err)
+		return
+	}
+
+	pageUrl := pg.Url()
+	if !strings.HasPrefix(pageUrl, "!") {
+		pageUrl = path.Clean(mp.Mount + pageUrl)
+	}
+
+	if layout := pg.Layout(); !l.hasLayout(layout) {
+		problem(Error, UnknownLayout, lineOf("Layout"), "layout %q not found, available layouts: %v",
+			layout, strings.Join(l.listLayouts(), ", ")).Url = pageUrl
+	}
+
+	archetype := pg.Archetype()
+	archetypeFields := l.archetypeFields(archetype)
+	fields := context.Fields{}
+	for k, v := range l.fields {
+		fields[k] = v
+	}
+	for k, v := range archetypeFields {
+		if _, present := fields[k]; !present {
+			// no clobbering, same as the page editor
+			fields[k] = v
+		}
+	}
+
+	for _, key := range fields.SortedKeys() {
+		field := fields[key]
+		if strings.EqualFold(field.Key, "layout") {
+			// validated against the theme layouts above
+			continue
+		}
+
+		k, v := pm.Matter.GetKV(field.Key)
+		if k == "" {
+			if field.Required {
+				if field == archetypeFields[key] {
+					problem(Error, MissingField, 1, "%q archetype field %q is required", archetype, field.Key).Url = pageUrl
+				} else {
+					problem(Warning, MissingField, 1, "page field %q is required", field.Key).Url = pageUrl
+				}
+			}
+			continue
+		}
+
+		value, ok := scalarValue(v)
+		if !ok {
+			continue
+		}
+		if field.Parse != nil {
+			if _, ee := field.Parse(field, value); ee != nil {
+				problem(Error, InvalidField, lineOf(k), "invalid %q value: %v", k, ee).Url = pageUrl
+				continue
+			}
+		}
+		if text, isString := value.(string); isString && strings.HasSuffix(field.Format, "-option") && len(field.ValueOptions) > 0 {
+			if !slices.Within(text, field.ValueOptions) {
+				problem(Error, InvalidField, lineOf(k), "invalid %q value: %q is not one of: %v",
+					k, text, strings.Join(field.ValueOptions, ", ")).Url = pageUrl
+			}
+		}
+	}
+
+	urlKey := pg.LanguageTag().String() + " " + pageUrl
+	if other, duplicate := l.urls[urlKey]; duplicate {
+		problem(Error, DuplicateUrl, lineOf("Url"), "url %q (%v) is also provided by: %v",
+			pageUrl, pg.LanguageTag(), other).Url = pageUrl
+	} else {
+		l.urls[urlKey] = name
+	}
+}
+
+// hasLayout looks for the named layout within the theme and its parents
+func (l *Linter) hasLayout(name string) (found bool) {
+	for t := l.theme; t != nil && !found; t = t.GetParent() {
+		_, _, found = t.FindLayout(name)
+	}
+	return
+}
+
+// listLayouts returns the unique layout names of the theme and its parents
+func (l *Linter) listLayouts() (names []string) {
+	unique := make(map[string]struct{})
+	for t := l.theme; t != nil; t = t.GetParent() {
+		for _, name := range t.Layouts().ListLayouts() {
+			unique[name] = struct{}{}
+		}
+	}
+	names = maps.SortedKeys(unique)
+	return
+}
+
+// archetypeFields returns the theme fields for the named archetype, combining
+// the general (extension-less) and specific archetype fields
+func (l *Linter) archetypeFields(name string) (fields context.Fields) {
+	if name == "" {
+		return context.Fields{}
+	}
+	if cached, ok := l.archetypes[name]; ok {
+		return cached
+	}
+
+	tc := l.theme.GetConfig()
+	found := context.Fields{}
+	basename := clPath.Base(name)
+	if general, ok := tc.Supports.Archetypes[basename]; ok {
+		for k, v := range general {
+			found[k] = v
+		}
+	}
+	if basename != name {
+		if specific, ok := tc.Supports.Archetypes[name]; ok {
+			for k, v := range specific {
+				found[k] = v
+			}
+		}
+	}
+	found.Init(message.GetPrinter(l.request), l.enjin.PageContextParsers())
+
+	l.archetypes[name] = found
+	fields = found
+	return
+}
+
+// scalarValue normalizes front-matter values to the types supported by the
+// standard page context field parsers, ok is false for all other types
+func scalarValue(input interface{}) (value interface{}, ok bool) {
+	ok = true
+	switch t := input.(type) {
+	case string, bool, int, float64:
+		value = t
+	case int64:
+		value = int(t)
+	case int32:
+		value = int(t)
+	case uint64:
+		value = int(t)
+	case float32:
+		value = float64(t)
+	default:
+		ok = false
+	}
+	return
+}
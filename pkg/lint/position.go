@@ -0,0 +1,115 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"encoding/json"
+	"errors"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/BurntSushi/toml"
+
+	"github.com/go-enjin/be/types/page/matter"
+)
+
+var rxErrorLine = regexp.MustCompile(`\bline (\d+)\b`)
+
+// matterLineOffset returns the number of lines preceding the first line of
+// the front-matter text returned by matter.ParseContent, within the file
+func matterLineOffset(matterType matter.FrontMatterType) (offset int) {
+	switch matterType {
+	case matter.TomlMatter, matter.YamlMatter:
+		// skip the opening "+++" or "---" line
+		offset = 1
+	default:
+		// json front-matter replaces the opening "{{{" with "{"
+		offset = 0
+	}
+	return
+}
+
+// errorLine unmarshals the front-matter text again to find the line number
+// of the syntax error, returns zero if the line cannot be determined
+func errorLine(text string, matterType matter.FrontMatterType) (line int) {
+	var err error
+	if _, err = matter.UnmarshalFrontMatter([]byte(text), matterType); err == nil {
+		return
+	}
+
+	var tomlErr toml.ParseError
+	var jsonSyntaxErr *json.SyntaxError
+	var jsonTypeErr *json.UnmarshalTypeError
+
+	switch {
+	case errors.As(err, &tomlErr):
+		line = tomlErr.Position.Line
+	case errors.As(err, &jsonSyntaxErr):
+		line = offsetLine(text, jsonSyntaxErr.Offset)
+	case errors.As(err, &jsonTypeErr):
+		line = offsetLine(text, jsonTypeErr.Offset)
+	default:
+		// yaml errors are of the form: "yaml: line N: ..."
+		if m := rxErrorLine.FindStringSubmatch(err.Error()); len(m) == 2 {
+			line, _ = strconv.Atoi(m[1])
+		}
+	}
+	return
+}
+
+// offsetLine returns the line number of the given byte offset
+func offsetLine(text string, offset int64) (line int) {
+	if offset > int64(len(text)) {
+		offset = int64(len(text))
+	}
+	line = strings.Count(text[:offset], "\n") + 1
+	return
+}
+
+// keyLine returns the line number of the top-level key within the
+// front-matter text, returns zero if the key is not found
+func keyLine(text string, matterType matter.FrontMatterType, key string) (line int) {
+	quoted := strconv.Quote(key)
+	for idx, raw := range strings.Split(text, "\n") {
+		var found bool
+		switch matterType {
+		case matter.TomlMatter:
+			if trimmed := strings.TrimSpace(raw); strings.HasPrefix(trimmed, "[") {
+				// keys following a table header are not top-level
+				return
+			} else {
+				found = isKeyAssignment(trimmed, key, "=") || isKeyAssignment(trimmed, quoted, "=")
+			}
+		case matter.YamlMatter:
+			// top-level yaml keys are not indented
+			found = isKeyAssignment(raw, key, ":") || isKeyAssignment(raw, quoted, ":")
+		case matter.JsonMatter:
+			found = isKeyAssignment(strings.TrimSpace(raw), quoted, ":")
+		}
+		if found {
+			line = idx + 1
+			return
+		}
+	}
+	return
+}
+
+func isKeyAssignment(text, key, operator string) (found bool) {
+	if rest, ok := strings.CutPrefix(text, key); ok {
+		found = strings.HasPrefix(strings.TrimSpace(rest), operator)
+	}
+	return
+}
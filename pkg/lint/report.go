@@ -0,0 +1,137 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package lint
+
+import (
+	"fmt"
+	"io"
+	"sort"
+	"time"
+)
+
+// Severity is the importance of a Problem
+type Severity string
+
+const (
+	// Error problems prevent a page from being served as intended
+	Error Severity = "error"
+	// Warning problems are likely mistakes which do not prevent a page from
+	// being served
+	Warning Severity = "warning"
+)
+
+// Rule identifies the check which found a Problem
+type Rule string
+
+const (
+	// ReadFile problems are filesystem errors encountered reading a file
+	ReadFile Rule = "read-file"
+	// FrontMatter problems are front-matter syntax errors
+	FrontMatter Rule = "front-matter"
+	// PageFormat problems are files not matching any registered PageFormat
+	// extension, or failing to be prepared by the PageFormat matched
+	PageFormat Rule = "page-format"
+	// UnknownLayout problems are pages using a layout not provided by the
+	// theme (or its parent)
+	UnknownLayout Rule = "unknown-layout"
+	// MissingField problems are required page context fields that are not
+	// present in the front-matter
+	MissingField Rule = "missing-field"
+	// InvalidField problems are front-matter values which fail to parse with
+	// the page context field's format
+	InvalidField Rule = "invalid-field"
+	// DuplicateUrl problems are pages sharing the same URL and language as
+	// another page
+	DuplicateUrl Rule = "duplicate-url"
+)
+
+// Problem describes a single issue found within a content file
+type Problem struct {
+	Severity Severity `json:"severity"`
+	Rule     Rule     `json:"rule"`
+	// File is the path to the content file, including the mount point's
+	// filesystem path
+	File string `json:"file"`
+	// Line is the line number within File, zero when not applicable
+	Line int `json:"line,omitempty"`
+	// Url is the URL path of the page, if known
+	Url string `json:"url,omitempty"`
+	// Message is a human-readable description of the problem
+	Message string `json:"message"`
+}
+
+func (p *Problem) String() (text string) {
+	text = p.File
+	if p.Line > 0 {
+		text += fmt.Sprintf(":%d", p.Line)
+	}
+	text += fmt.Sprintf(": %v: %v [%v]", p.Severity, p.Message, p.Rule)
+	return
+}
+
+// Report is the result of linting all content filesystems of an enjin
+type Report struct {
+	Started  time.Time  `json:"started"`
+	Duration string     `json:"duration"`
+	Files    int        `json:"files"`
+	Problems []*Problem `json:"problems"`
+}
+
+// Count returns the number of problems of the given severity
+func (r *Report) Count(severity Severity) (count int) {
+	for _, problem := range r.Problems {
+		if problem.Severity == severity {
+			count += 1
+		}
+	}
+	return
+}
+
+// Failed returns true if there are any errors, or any problems at all when
+// strict is true
+func (r *Report) Failed(strict bool) (failed bool) {
+	if strict {
+		return len(r.Problems) > 0
+	}
+	return r.Count(Error) > 0
+}
+
+// WriteText writes one problem per line, in the "file:line: message" form
+// understood by most editors, followed by a summary line
+func (r *Report) WriteText(w io.Writer) (err error) {
+	for _, problem := range r.Problems {
+		if _, err = fmt.Fprintln(w, problem.String()); err != nil {
+			return
+		}
+	}
+	_, err = fmt.Fprintf(w,
+		"linted %d files in %v: %d errors, %d warnings\n",
+		r.Files, r.Duration, r.Count(Error), r.Count(Warning),
+	)
+	return
+}
+
+func (r *Report) add(problem *Problem) {
+	r.Problems = append(r.Problems, problem)
+}
+
+func (r *Report) sort() {
+	sort.SliceStable(r.Problems, func(i, j int) (less bool) {
+		if r.Problems[i].File == r.Problems[j].File {
+			return r.Problems[i].Line < r.Problems[j].Line
+		}
+		return r.Problems[i].File < r.Problems[j].File
+	})
+}
@@ -16,6 +16,7 @@ package log
 
 import (
 	"fmt"
+	"io"
 	"log"
 	"log/syslog"
 
@@ -38,6 +39,7 @@ type Configuration struct {
 	PapertrailHost   string
 	PapertrailPort   int
 	PapertrailTag    string
+	Output           io.Writer
 }
 
 var (
@@ -57,6 +59,10 @@ var (
 func (c Configuration) Apply() {
 	logger = logrus.New()
 
+	if c.Output != nil {
+		logger.SetOutput(c.Output)
+	}
+
 	switch c.LoggingFormat {
 	case FormatJson:
 		logger.SetFormatter(&logrus.JSONFormatter{
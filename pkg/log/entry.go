@@ -0,0 +1,97 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
+
+// Entry is a set of structured fields to include with log entries
+//
+// Example:
+//
+//	log.With("feature", f.Tag(), "bucket", name).WarnRF(r, "bucket is full")
+type Entry struct {
+	fields Fields
+}
+
+// With returns a new Entry with the given key/value pairs
+func With(pairs ...interface{}) (entry *Entry) {
+	entry = &Entry{fields: MakeFields(pairs...)}
+	return
+}
+
+// With returns a copy of the Entry with the given key/value pairs added
+func (e *Entry) With(pairs ...interface{}) (entry *Entry) {
+	entry = &Entry{fields: make(Fields)}
+	for k, v := range e.fields {
+		entry.fields[k] = v
+	}
+	for k, v := range MakeFields(pairs...) {
+		entry.fields[k] = v
+	}
+	return
+}
+
+// Fields returns a copy of the Entry fields
+func (e *Entry) Fields() (fields Fields) {
+	fields = make(Fields)
+	for k, v := range e.fields {
+		fields[k] = v
+	}
+	return
+}
+
+func (e *Entry) ErrorF(format string, argv ...interface{}) {
+	output(logrus.ErrorLevel, 1, nil, e.fields, format, argv...)
+}
+
+func (e *Entry) WarnF(format string, argv ...interface{}) {
+	output(logrus.WarnLevel, 1, nil, e.fields, format, argv...)
+}
+
+func (e *Entry) InfoF(format string, argv ...interface{}) {
+	output(logrus.InfoLevel, 1, nil, e.fields, format, argv...)
+}
+
+func (e *Entry) DebugF(format string, argv ...interface{}) {
+	output(logrus.DebugLevel, 1, nil, e.fields, format, argv...)
+}
+
+func (e *Entry) TraceF(format string, argv ...interface{}) {
+	output(logrus.TraceLevel, 1, nil, e.fields, format, argv...)
+}
+
+func (e *Entry) ErrorRF(r *http.Request, format string, argv ...interface{}) {
+	output(logrus.ErrorLevel, 1, r, e.fields, format, argv...)
+}
+
+func (e *Entry) WarnRF(r *http.Request, format string, argv ...interface{}) {
+	output(logrus.WarnLevel, 1, r, e.fields, format, argv...)
+}
+
+func (e *Entry) InfoRF(r *http.Request, format string, argv ...interface{}) {
+	output(logrus.InfoLevel, 1, r, e.fields, format, argv...)
+}
+
+func (e *Entry) DebugRF(r *http.Request, format string, argv ...interface{}) {
+	output(logrus.DebugLevel, 1, r, e.fields, format, argv...)
+}
+
+func (e *Entry) TraceRF(r *http.Request, format string, argv ...interface{}) {
+	output(logrus.TraceLevel, 1, r, e.fields, format, argv...)
+}
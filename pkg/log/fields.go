@@ -0,0 +1,233 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package log
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+	"sync"
+
+	"github.com/go-chi/chi/v5"
+	"github.com/sirupsen/logrus"
+
+	"github.com/go-corelibs/x-text/language"
+	"github.com/go-corelibs/x-text/message"
+
+	"github.com/go-enjin/be/pkg/net"
+	"github.com/go-enjin/be/pkg/request"
+)
+
+// Fields are the structured key/value pairs included with log entries
+type Fields map[string]interface{}
+
+// standard structured logging field names, shared with the access logger
+const (
+	FieldCaller    = "caller"
+	FieldFunc      = "func"
+	FieldFeature   = "feature"
+	FieldRequestID = "request_id"
+	FieldRemoteIP  = "remote_ip"
+	FieldUser      = "user"
+	FieldLanguage  = "lang"
+	FieldRoute     = "route"
+)
+
+type requestFieldsKey struct{}
+
+type requestFieldsHolderKey struct{}
+
+// requestFieldsHolder is the mutable set of fields shared by a request and
+// all the requests derived from it
+type requestFieldsHolder struct {
+	fields Fields
+	sync.RWMutex
+}
+
+var (
+	gFeaturePackages     = make(map[string]string)
+	gFeaturePackagesLock sync.RWMutex
+)
+
+// MakeFields returns Fields from the given key/value pairs, non-string keys
+// are formatted with %v
+func MakeFields(pairs ...interface{}) (fields Fields) {
+	fields = make(Fields)
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		if key, ok := pairs[idx].(string); ok {
+			fields[key] = pairs[idx+1]
+		} else {
+			fields[fmt.Sprintf("%v", pairs[idx])] = pairs[idx+1]
+		}
+	}
+	return
+}
+
+// AppendRequestFields returns a copy of r with the given key/value pairs
+// included in all structured log entries made with r
+func AppendRequestFields(r *http.Request, pairs ...interface{}) (modified *http.Request) {
+	fields := make(Fields)
+	if existing, ok := r.Context().Value(requestFieldsKey{}).(Fields); ok {
+		for k, v := range existing {
+			fields[k] = v
+		}
+	}
+	appended := MakeFields(pairs...)
+	for k, v := range appended {
+		fields[k] = v
+	}
+	RecordRequestFields(r, pairs...)
+	modified = r.Clone(context.WithValue(r.Context(), requestFieldsKey{}, fields))
+	return
+}
+
+// PrepareRequestFields returns a copy of r with a mutable set of fields which
+// AppendRequestFields also records, so that entries made with r include the
+// fields appended further along the middleware chain (like the access log
+// entries made after the request is served)
+func PrepareRequestFields(r *http.Request) (modified *http.Request) {
+	holder := &requestFieldsHolder{fields: make(Fields)}
+	modified = r.Clone(context.WithValue(r.Context(), requestFieldsHolderKey{}, holder))
+	return
+}
+
+// RecordRequestFields includes the given key/value pairs with the fields
+// prepared by PrepareRequestFields, without modifying r, for the fields only
+// known further along the middleware chain, like the request language, to be
+// included with the access log entries
+func RecordRequestFields(r *http.Request, pairs ...interface{}) {
+	if holder, ok := r.Context().Value(requestFieldsHolderKey{}).(*requestFieldsHolder); ok {
+		holder.Lock()
+		defer holder.Unlock()
+		for k, v := range MakeFields(pairs...) {
+			holder.fields[k] = v
+		}
+	}
+}
+
+// RequestFields returns the standard fields derived from the given request:
+// request ID, remote IP address, language, route and any fields appended
+// with AppendRequestFields, including those appended to derived requests when
+// PrepareRequestFields was used
+func RequestFields(r *http.Request) (fields Fields) {
+	fields = make(Fields)
+	if r == nil {
+		return
+	}
+	if holder, ok := r.Context().Value(requestFieldsHolderKey{}).(*requestFieldsHolder); ok {
+		holder.RLock()
+		for k, v := range holder.fields {
+			fields[k] = v
+		}
+		holder.RUnlock()
+	}
+	if rid := request.GetRequestID(r); rid != "" {
+		fields[FieldRequestID] = rid
+	}
+	if ip, err := net.GetIpFromRequest(r); err == nil && ip != "" {
+		fields[FieldRemoteIP] = ip
+	}
+	if tag := message.GetTag(r); !language.Compare(tag, language.Und) {
+		fields[FieldLanguage] = tag.String()
+	}
+	if rctx := chi.RouteContext(r.Context()); rctx != nil && rctx.RoutePattern() != "" {
+		fields[FieldRoute] = rctx.RoutePattern()
+	} else if r.URL != nil {
+		fields[FieldRoute] = r.URL.Path
+	}
+	if appended, ok := r.Context().Value(requestFieldsKey{}).(Fields); ok {
+		for k, v := range appended {
+			fields[k] = v
+		}
+	}
+	return
+}
+
+// RegisterFeaturePackage associates the given Go package path with the
+// feature tag reported in the "feature" field of log entries made from
+// within that package; when more than one feature instance is registered
+// for the same package, the common baseTag is reported instead
+func RegisterFeaturePackage(pkgPath, tag, baseTag string) {
+	gFeaturePackagesLock.Lock()
+	defer gFeaturePackagesLock.Unlock()
+	if existing, present := gFeaturePackages[pkgPath]; present && existing != tag {
+		tag = baseTag
+	}
+	gFeaturePackages[pkgPath] = tag
+}
+
+func lookupFeaturePackage(fullName string) (tag string) {
+	// github.com/go-enjin/be/features/thing.(*CFeature).Method.func1
+	pkgPath := fullName
+	if idx := strings.LastIndex(pkgPath, "/"); idx > -1 {
+		if dot := strings.Index(pkgPath[idx:], "."); dot > -1 {
+			pkgPath = pkgPath[:idx+dot]
+		}
+	} else if dot := strings.Index(pkgPath, "."); dot > -1 {
+		pkgPath = pkgPath[:dot]
+	}
+	gFeaturePackagesLock.RLock()
+	defer gFeaturePackagesLock.RUnlock()
+	tag = gFeaturePackages[pkgPath]
+	return
+}
+
+// makeFields returns the complete set of structured fields for a log entry
+// made by the caller at the given depth
+func makeFields(depth int, r *http.Request, fields Fields) (data logrus.Fields) {
+	depth += 1
+	file, line, name, fullName := callerInfo(depth)
+	data = logrus.Fields{
+		FieldCaller: fmt.Sprintf("%s:%d", file, line),
+		FieldFunc:   name,
+	}
+	if tag := lookupFeaturePackage(fullName); tag != "" {
+		data[FieldFeature] = tag
+	}
+	for k, v := range RequestFields(r) {
+		data[k] = v
+	}
+	for k, v := range fields {
+		data[k] = v
+	}
+	return
+}
+
+// Structured returns true when log entries are output as JSON objects with
+// all context as separate fields
+func Structured() (structured bool) {
+	structured = Config.LoggingFormat == FormatJson
+	return
+}
+
+// output is the common implementation of all logging wrappers
+func output(level logrus.Level, depth int, r *http.Request, fields Fields, format string, argv ...interface{}) {
+	depth += 1
+	if !logger.IsLevelEnabled(level) {
+		return
+	}
+	var entry *logrus.Entry
+	if Structured() {
+		entry = logger.WithFields(makeFields(depth, r, fields))
+	} else {
+		entry = logger.WithFields(logrus.Fields(fields))
+		format = prefixLogEntry(depth, format, r)
+	}
+	entry.Logf(level, format, argv...)
+	if level == logrus.FatalLevel {
+		logger.Exit(1)
+	}
+}
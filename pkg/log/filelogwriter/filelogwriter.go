@@ -19,16 +19,35 @@ import (
 	"io"
 	"os"
 	"path/filepath"
+	"sync"
+	"time"
 )
 
 var _ io.Writer = (*FileLogWriter)(nil)
 
 // FileLogWriter is an io.Writer that does not keep any file handles open any
 // longer than necessary.
+//
+// FileLogWriter optionally rotates the log file when it grows beyond a maximum
+// size or when a rotation interval elapses. Rotated files are renamed with a
+// timestamp suffix (see BackupTimeFormat), can be gzip compressed and are
+// pruned to a maximum count and/or age. All methods are safe for concurrent
+// use.
 type FileLogWriter struct {
 	file string
 	flag int
 	mode os.FileMode
+
+	maxSize    int64
+	interval   time.Duration
+	compress   bool
+	maxBackups int
+	maxAge     time.Duration
+
+	period time.Time
+
+	sync.Mutex
+	pruning sync.Mutex
 }
 
 // NewFileLogWriter constructs a new FileLogWriter instance with the settings
@@ -56,6 +75,8 @@ func NewFileLogWriter(file string) (flw *FileLogWriter, err error) {
 // SetFlag is a chainable method for setting the file flags used to open a new
 // file handle each time Write is called
 func (flw *FileLogWriter) SetFlag(flag int) *FileLogWriter {
+	flw.Lock()
+	defer flw.Unlock()
 	flw.flag = flag
 	return flw
 }
@@ -63,13 +84,21 @@ func (flw *FileLogWriter) SetFlag(flag int) *FileLogWriter {
 // SetMode is a chainable method for setting the file mode used to open a new
 // file handle each time Write is called
 func (flw *FileLogWriter) SetMode(mode os.FileMode) *FileLogWriter {
+	flw.Lock()
+	defer flw.Unlock()
 	flw.mode = mode
 	return flw
 }
 
 // Write opens the log file, writes the data given and returns the bytes written
-// and the error state after closing the open file handle.
+// and the error state after closing the open file handle. If the log file is
+// due for rotation, it is rotated before the data is written.
 func (flw *FileLogWriter) Write(p []byte) (n int, err error) {
+	flw.Lock()
+	defer flw.Unlock()
+	if err = flw.checkRotation(int64(len(p))); err != nil {
+		return
+	}
 	var fh *os.File
 	if fh, err = os.OpenFile(flw.file, flw.flag, flw.mode); err != nil {
 		return
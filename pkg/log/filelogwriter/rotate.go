@@ -0,0 +1,256 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package filelogwriter
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// BackupTimeFormat is the timestamp format inserted between the log file name
+// and its extension when the log file is rotated
+const BackupTimeFormat = "20060102-150405.000"
+
+// SetMaxSize is a chainable method for setting the maximum size of the log file,
+// in bytes, before it is rotated. A size of zero disables size based rotation
+func (flw *FileLogWriter) SetMaxSize(size int64) *FileLogWriter {
+	flw.Lock()
+	defer flw.Unlock()
+	flw.maxSize = size
+	return flw
+}
+
+// SetRotateInterval is a chainable method for setting how often the log file is
+// rotated, aligned to UTC boundaries (ie: 24h rotates at midnight UTC). An
+// interval of zero disables time based rotation
+func (flw *FileLogWriter) SetRotateInterval(interval time.Duration) *FileLogWriter {
+	flw.Lock()
+	defer flw.Unlock()
+	flw.interval = interval
+	return flw
+}
+
+// SetCompress is a chainable method for enabling gzip compression of rotated
+// log files
+func (flw *FileLogWriter) SetCompress(compress bool) *FileLogWriter {
+	flw.Lock()
+	defer flw.Unlock()
+	flw.compress = compress
+	return flw
+}
+
+// SetMaxBackups is a chainable method for setting the maximum number of rotated
+// log files to retain. A count of zero retains all rotated files
+func (flw *FileLogWriter) SetMaxBackups(count int) *FileLogWriter {
+	flw.Lock()
+	defer flw.Unlock()
+	flw.maxBackups = count
+	return flw
+}
+
+// SetMaxAge is a chainable method for setting the maximum age of rotated log
+// files to retain. An age of zero retains rotated files regardless of age
+func (flw *FileLogWriter) SetMaxAge(age time.Duration) *FileLogWriter {
+	flw.Lock()
+	defer flw.Unlock()
+	flw.maxAge = age
+	return flw
+}
+
+// Rotate forces the log file to be rotated, regardless of size or interval
+func (flw *FileLogWriter) Rotate() (err error) {
+	flw.Lock()
+	defer flw.Unlock()
+	if _, err = os.Stat(flw.file); os.IsNotExist(err) {
+		err = nil
+		return
+	} else if err != nil {
+		return
+	}
+	err = flw.rotate(time.Now())
+	return
+}
+
+// Reopen resets the rotation state of the log file. FileLogWriter opens the log
+// file for each Write and so external tools (like logrotate) may move or
+// truncate the log file at any time, Reopen is used to notify the writer that
+// the current rotation period must be derived from the log file again
+func (flw *FileLogWriter) Reopen() {
+	flw.Lock()
+	defer flw.Unlock()
+	flw.period = time.Time{}
+}
+
+func (flw *FileLogWriter) checkRotation(incoming int64) (err error) {
+	if flw.maxSize <= 0 && flw.interval <= 0 {
+		return
+	}
+
+	now := time.Now()
+	var info os.FileInfo
+	if info, err = os.Stat(flw.file); os.IsNotExist(err) {
+		err = nil
+		flw.period = now
+		return
+	} else if err != nil {
+		return
+	}
+
+	if flw.period.IsZero() {
+		flw.period = info.ModTime()
+	}
+
+	if info.Size() == 0 {
+		// never rotate empty files
+		return
+	}
+
+	if flw.maxSize > 0 && info.Size()+incoming > flw.maxSize {
+		err = flw.rotate(now)
+	} else if flw.interval > 0 && !now.Truncate(flw.interval).Equal(flw.period.Truncate(flw.interval)) {
+		err = flw.rotate(now)
+	}
+	return
+}
+
+func (flw *FileLogWriter) rotate(now time.Time) (err error) {
+	backup := flw.backupName(now)
+	if err = os.Rename(flw.file, backup); err != nil {
+		err = fmt.Errorf("error rotating log file: %v", err)
+		return
+	}
+	flw.period = now
+	go flw.postRotate(backup, flw.compress, flw.maxBackups, flw.maxAge)
+	return
+}
+
+func (flw *FileLogWriter) splitName() (dir, prefix, ext string) {
+	dir, name := filepath.Split(flw.file)
+	ext = filepath.Ext(name)
+	prefix = strings.TrimSuffix(name, ext)
+	return
+}
+
+func (flw *FileLogWriter) backupName(now time.Time) (backup string) {
+	dir, prefix, ext := flw.splitName()
+	backup = filepath.Join(dir, prefix+"-"+now.Format(BackupTimeFormat)+ext)
+	return
+}
+
+// postRotate compresses and prunes rotated log files in the background, errors
+// are reported on os.Stderr as the log file itself may be the one in error
+func (flw *FileLogWriter) postRotate(backup string, compress bool, maxBackups int, maxAge time.Duration) {
+	flw.pruning.Lock()
+	defer flw.pruning.Unlock()
+
+	if compress {
+		if err := compressFile(backup); err != nil {
+			_, _ = fmt.Fprintf(os.Stderr, "error compressing rotated log file: %v - %v\n", backup, err)
+		}
+	}
+
+	if maxBackups <= 0 && maxAge <= 0 {
+		return
+	}
+
+	now := time.Now()
+	for idx, b := range flw.listBackups() {
+		if (maxBackups > 0 && idx >= maxBackups) || (maxAge > 0 && now.Sub(b.when) > maxAge) {
+			if err := os.Remove(b.path); err != nil && !os.IsNotExist(err) {
+				_, _ = fmt.Fprintf(os.Stderr, "error removing rotated log file: %v - %v\n", b.path, err)
+			}
+		}
+	}
+}
+
+type backupFile struct {
+	path string
+	when time.Time
+}
+
+// listBackups returns the rotated log files, newest first
+func (flw *FileLogWriter) listBackups() (backups []backupFile) {
+	dir, prefix, ext := flw.splitName()
+	if dir == "" {
+		dir = "."
+	}
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	for _, entry := range entries {
+		name := entry.Name()
+		if entry.IsDir() || !strings.HasPrefix(name, prefix+"-") {
+			continue
+		}
+		stamp := strings.TrimPrefix(strings.TrimSuffix(name, ".gz"), prefix+"-")
+		if stamp = strings.TrimSuffix(stamp, ext); len(stamp) != len(BackupTimeFormat) {
+			continue
+		}
+		// backup names are formatted in local time
+		if when, ee := time.ParseInLocation(BackupTimeFormat, stamp, time.Local); ee == nil {
+			backups = append(backups, backupFile{path: filepath.Join(dir, name), when: when})
+		}
+	}
+
+	sort.SliceStable(backups, func(i, j int) bool {
+		return backups[i].when.After(backups[j].when)
+	})
+	return
+}
+
+// compressFile gzips the given file to file+".gz" and removes the original
+func compressFile(file string) (err error) {
+	var src *os.File
+	if src, err = os.Open(file); err != nil {
+		return
+	}
+	defer func() {
+		_ = src.Close()
+	}()
+
+	var info os.FileInfo
+	if info, err = src.Stat(); err != nil {
+		return
+	}
+
+	var dst *os.File
+	if dst, err = os.OpenFile(file+".gz", os.O_CREATE|os.O_WRONLY|os.O_TRUNC, info.Mode()); err != nil {
+		return
+	}
+
+	gz := gzip.NewWriter(dst)
+	if _, err = io.Copy(gz, src); err == nil {
+		err = gz.Close()
+	}
+	if ee := dst.Close(); err == nil {
+		err = ee
+	}
+	if err != nil {
+		_ = os.Remove(file + ".gz")
+		return
+	}
+
+	err = os.Remove(file)
+	return
+}
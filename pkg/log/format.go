@@ -21,3 +21,9 @@ const (
 	FormatJson
 	FormatText
 )
+
+var Formats = map[string]Format{
+	"pretty": FormatPretty,
+	"json":   FormatJson,
+	"text":   FormatText,
+}
@@ -29,13 +29,18 @@ var (
 	rxInvalidFuncName = regexp.MustCompile(`^\s*(\d+|func\d+)\s*$`)
 )
 
-func getLogPrefix(depth int, r *http.Request) string {
+// callerInfo returns the source file and line of the caller at the given
+// depth, the first valid function name found from that depth and the full
+// package-qualified function name of the caller
+func callerInfo(depth int) (file string, line int, name, fullName string) {
 	depth += 1
-	var file, name string
-	var line int
 	var ok bool
-	if _, file, line, ok = runtime.Caller(depth); ok {
+	var pc uintptr
+	if pc, file, line, ok = runtime.Caller(depth); ok {
 		file = rxGoModuleVersion.ReplaceAllString(file, "/")
+		if fn := runtime.FuncForPC(pc); fn != nil {
+			fullName = fn.Name()
+		}
 		for i := depth; i < 20; i++ {
 			if pc, _, _, ok := runtime.Caller(i); ok {
 				fn := runtime.FuncForPC(pc).Name()
@@ -50,6 +55,12 @@ func getLogPrefix(depth int, r *http.Request) string {
 			break
 		}
 	}
+	return
+}
+
+func getLogPrefix(depth int, r *http.Request) string {
+	depth += 1
+	file, line, name, _ := callerInfo(depth)
 	if Config.LoggingFormat == FormatText {
 		return "[" + name + "]"
 	}
@@ -14,7 +14,11 @@
 
 package log
 
-import "net/http"
+import (
+	"net/http"
+
+	"github.com/sirupsen/logrus"
+)
 
 func ErrorR(r *http.Request, err error) {
 	ErrorRDF(r, 1, "%v", err)
@@ -26,7 +30,7 @@ func ErrorRF(r *http.Request, format string, argv ...interface{}) {
 
 func ErrorRDF(r *http.Request, depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Errorf(prefixLogEntry(depth, format, r), argv...)
+	output(logrus.ErrorLevel, depth, r, nil, format, argv...)
 }
 
 func WarnRF(r *http.Request, format string, argv ...interface{}) {
@@ -35,7 +39,7 @@ func WarnRF(r *http.Request, format string, argv ...interface{}) {
 
 func WarnRDF(r *http.Request, depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Warnf(prefixLogEntry(depth, format, r), argv...)
+	output(logrus.WarnLevel, depth, r, nil, format, argv...)
 }
 
 func InfoRF(r *http.Request, format string, argv ...interface{}) {
@@ -44,7 +48,7 @@ func InfoRF(r *http.Request, format string, argv ...interface{}) {
 
 func InfoRDF(r *http.Request, depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Infof(prefixLogEntry(depth, format, r), argv...)
+	output(logrus.InfoLevel, depth, r, nil, format, argv...)
 }
 
 func DebugRF(r *http.Request, format string, argv ...interface{}) {
@@ -53,7 +57,7 @@ func DebugRF(r *http.Request, format string, argv ...interface{}) {
 
 func DebugRDF(r *http.Request, depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Debugf(prefixLogEntry(depth, format, r), argv...)
+	output(logrus.DebugLevel, depth, r, nil, format, argv...)
 }
 
 func TraceRF(r *http.Request, format string, argv ...interface{}) {
@@ -62,16 +66,16 @@ func TraceRF(r *http.Request, format string, argv ...interface{}) {
 
 func TraceRDF(r *http.Request, depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Tracef(prefixLogEntry(depth, format, r), argv...)
+	output(logrus.TraceLevel, depth, r, nil, format, argv...)
 }
 
 func PanicRF(r *http.Request, format string, argv ...interface{}) {
-	PanicDF(1, format, argv...)
+	PanicRDF(r, 1, format, argv...)
 }
 
 func PanicRDF(r *http.Request, depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Panicf(prefixLogEntry(depth, format, r), argv...)
+	output(logrus.PanicLevel, depth, r, nil, format, argv...)
 }
 
 func FatalRF(r *http.Request, format string, argv ...interface{}) {
@@ -80,5 +84,5 @@ func FatalRF(r *http.Request, format string, argv ...interface{}) {
 
 func FatalRDF(r *http.Request, depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Fatalf(prefixLogEntry(depth, format, r), argv...)
+	output(logrus.FatalLevel, depth, r, nil, format, argv...)
 }
@@ -14,6 +14,10 @@
 
 package log
 
+import (
+	"github.com/sirupsen/logrus"
+)
+
 func Error(err error) {
 	ErrorDF(1, "%v", err)
 }
@@ -24,7 +28,7 @@ func ErrorF(format string, argv ...interface{}) {
 
 func ErrorDF(depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Errorf(prefixLogEntry(depth, format, nil), argv...)
+	output(logrus.ErrorLevel, depth, nil, nil, format, argv...)
 }
 
 func WarnF(format string, argv ...interface{}) {
@@ -33,7 +37,7 @@ func WarnF(format string, argv ...interface{}) {
 
 func WarnDF(depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Warnf(prefixLogEntry(depth, format, nil), argv...)
+	output(logrus.WarnLevel, depth, nil, nil, format, argv...)
 }
 
 func InfoF(format string, argv ...interface{}) {
@@ -42,7 +46,7 @@ func InfoF(format string, argv ...interface{}) {
 
 func InfoDF(depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Infof(prefixLogEntry(depth, format, nil), argv...)
+	output(logrus.InfoLevel, depth, nil, nil, format, argv...)
 }
 
 func DebugF(format string, argv ...interface{}) {
@@ -51,7 +55,7 @@ func DebugF(format string, argv ...interface{}) {
 
 func DebugDF(depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Debugf(prefixLogEntry(depth, format, nil), argv...)
+	output(logrus.DebugLevel, depth, nil, nil, format, argv...)
 }
 
 func TraceF(format string, argv ...interface{}) {
@@ -60,7 +64,7 @@ func TraceF(format string, argv ...interface{}) {
 
 func TraceDF(depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Tracef(prefixLogEntry(depth, format, nil), argv...)
+	output(logrus.TraceLevel, depth, nil, nil, format, argv...)
 }
 
 func PanicF(format string, argv ...interface{}) {
@@ -69,7 +73,7 @@ func PanicF(format string, argv ...interface{}) {
 
 func PanicDF(depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Panicf(prefixLogEntry(depth, format, nil), argv...)
+	output(logrus.PanicLevel, depth, nil, nil, format, argv...)
 }
 
 func FatalF(format string, argv ...interface{}) {
@@ -78,5 +82,5 @@ func FatalF(format string, argv ...interface{}) {
 
 func FatalDF(depth int, format string, argv ...interface{}) {
 	depth += 1
-	logger.Fatalf(prefixLogEntry(depth, format, nil), argv...)
+	output(logrus.FatalLevel, depth, nil, nil, format, argv...)
 }
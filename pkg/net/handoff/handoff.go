@@ -0,0 +1,264 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package handoff provides the listening socket inheritance used for
+// zero-downtime restarts, where a new process is started with the listening
+// sockets of the current process and signals when it is ready to take over
+package handoff
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	// EnvInheritFds is the number of listening sockets inherited, starting
+	// with file descriptor 3
+	EnvInheritFds = "ENJIN_INHERIT_FDS"
+	// EnvReadyFd is the file descriptor the new process writes to once ready
+	EnvReadyFd = "ENJIN_READY_FD"
+)
+
+const gInheritFdsStart = 3
+
+var (
+	gInherited     []net.Listener
+	gInheritedErr  error
+	gInheritedOnce sync.Once
+	gInheritedLock sync.Mutex
+)
+
+// Inherited returns the listeners inherited from a parent process, if any
+func Inherited() (listeners []net.Listener, err error) {
+	gInheritedOnce.Do(func() {
+		defer func() {
+			_ = os.Unsetenv(EnvInheritFds)
+		}()
+		value := os.Getenv(EnvInheritFds)
+		if value == "" {
+			return
+		}
+		var count int
+		if count, gInheritedErr = strconv.Atoi(value); gInheritedErr != nil || count < 0 {
+			gInheritedErr = fmt.Errorf("invalid %v value: %q", EnvInheritFds, value)
+			return
+		}
+		for idx := 0; idx < count; idx++ {
+			fd := gInheritFdsStart + idx
+			file := os.NewFile(uintptr(fd), "inherited-"+strconv.Itoa(fd))
+			l, ee := net.FileListener(file)
+			_ = file.Close()
+			if ee != nil {
+				gInheritedErr = fmt.Errorf("error inheriting file descriptor %d: %w", fd, ee)
+				return
+			}
+			gInherited = append(gInherited, l)
+		}
+	})
+	gInheritedLock.Lock()
+	defer gInheritedLock.Unlock()
+	listeners, err = append([]net.Listener{}, gInherited...), gInheritedErr
+	return
+}
+
+// Inherit returns the inherited listener matching the network and address
+// given, or nil if none were inherited
+func Inherit(network, address string) (l net.Listener, err error) {
+	if _, err = Inherited(); err != nil {
+		return
+	}
+	gInheritedLock.Lock()
+	defer gInheritedLock.Unlock()
+	for idx, il := range gInherited {
+		if matchAddr(il.Addr(), network, address) {
+			l = il
+			gInherited = append(gInherited[:idx], gInherited[idx+1:]...)
+			return
+		}
+	}
+	return
+}
+
+// Listen returns the inherited listener matching the network and address
+// given, or a new listener if none were inherited
+func Listen(network, address string) (l net.Listener, inherited bool, err error) {
+	if l, err = Inherit(network, address); err != nil {
+		return
+	} else if inherited = l != nil; !inherited {
+		l, err = net.Listen(network, address)
+	}
+	return
+}
+
+func matchAddr(addr net.Addr, network, address string) (matched bool) {
+	switch network {
+	case "unix":
+		matched = addr.Network() == "unix" && addr.String() == address
+	default:
+		if addr.Network() != "tcp" {
+			return
+		}
+		wantHost, wantPort, err := net.SplitHostPort(address)
+		if err != nil {
+			return
+		}
+		haveHost, havePort, err := net.SplitHostPort(addr.String())
+		if err != nil || wantPort != havePort {
+			return
+		}
+		if wantHost == haveHost {
+			return true
+		}
+		// wildcard addresses are reported as "::" or "0.0.0.0"
+		isAny := func(host string) bool {
+			return host == "" || host == "0.0.0.0" || host == "::"
+		}
+		matched = isAny(wantHost) && isAny(haveHost)
+	}
+	return
+}
+
+// Files returns duplicated file descriptors for the given listeners, suitable
+// for use with Restart
+func Files(listeners ...net.Listener) (files []*os.File, err error) {
+	type filer interface {
+		File() (f *os.File, err error)
+	}
+	for _, l := range listeners {
+		if fl, ok := l.(filer); ok {
+			var file *os.File
+			if file, err = fl.File(); err != nil {
+				return
+			}
+			files = append(files, file)
+		} else {
+			err = fmt.Errorf("%T listeners cannot be handed off", l)
+			return
+		}
+	}
+	return
+}
+
+// Restart starts a new instance of the current executable with the same
+// arguments and environment, inheriting the given listener files, and waits
+// up to the timeout for the new process to call NotifyReady
+func Restart(files []*os.File, timeout time.Duration) (pid int, err error) {
+	var executable string
+	if executable, err = os.Executable(); err != nil {
+		return
+	}
+
+	var reader, writer *os.File
+	if reader, writer, err = os.Pipe(); err != nil {
+		return
+	}
+	defer func() {
+		_ = reader.Close()
+	}()
+
+	extra := append(append([]*os.File{}, files...), writer)
+	env := append(
+		os.Environ(),
+		EnvInheritFds+"="+strconv.Itoa(len(files)),
+		EnvReadyFd+"="+strconv.Itoa(gInheritFdsStart+len(files)),
+	)
+
+	attr := &os.ProcAttr{
+		Env:   env,
+		Files: append([]*os.File{os.Stdin, os.Stdout, os.Stderr}, extra...),
+	}
+
+	var proc *os.Process
+	proc, err = os.StartProcess(executable, os.Args, attr)
+	// the child has its own copy of the writer
+	_ = writer.Close()
+	if err != nil {
+		return
+	}
+	pid = proc.Pid
+
+	ready := make(chan error, 1)
+	go func() {
+		buf := make([]byte, 5)
+		if _, ee := io.ReadFull(reader, buf); ee != nil {
+			ready <- fmt.Errorf("process %d exited before becoming ready: %w", pid, ee)
+		} else if string(buf) != "ready" {
+			ready <- fmt.Errorf("process %d sent an unexpected ready message: %q", pid, string(buf))
+		} else {
+			ready <- nil
+		}
+	}()
+
+	select {
+	case err = <-ready:
+	case <-time.After(timeout):
+		err = fmt.Errorf("process %d did not become ready within %v", pid, timeout)
+	}
+	if err != nil {
+		_ = proc.Kill()
+		_, _ = proc.Wait()
+		return
+	}
+	// the new process is not waited for, it outlives this one
+	_ = proc.Release()
+	return
+}
+
+// NotifyReady informs the parent process, if any, that this process has
+// started successfully and is ready to take over the inherited listeners
+func NotifyReady() (err error) {
+	value := os.Getenv(EnvReadyFd)
+	if value == "" {
+		return
+	}
+	var fd int
+	if fd, err = strconv.Atoi(value); err != nil {
+		err = fmt.Errorf("invalid %v value: %q", EnvReadyFd, value)
+		return
+	}
+	file := os.NewFile(uintptr(fd), "ready")
+	defer func() {
+		_ = file.Close()
+		_ = os.Unsetenv(EnvReadyFd)
+	}()
+	_, err = file.Write([]byte("ready"))
+	return
+}
+
+// Drain gracefully shuts down the given server, waiting up to the timeout
+// given for active requests to complete before closing all connections
+func Drain(srv *http.Server, timeout time.Duration) (err error) {
+	if srv == nil {
+		return
+	}
+	ctx := context.Background()
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	if err = srv.Shutdown(ctx); errors.Is(err, context.DeadlineExceeded) {
+		err = fmt.Errorf("drain timeout of %v exceeded, closing active connections", timeout)
+		_ = srv.Close()
+	}
+	return
+}
@@ -0,0 +1,36 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors
+
+import (
+	"strings"
+)
+
+type ConfigError []string
+
+func (c ConfigError) Error() (msg string) {
+	msg = strings.Join(c, "\n")
+	return
+}
+
+func (c ConfigError) addError(msg string) (modified ConfigError) {
+	modified = append(c, msg)
+	return
+}
+
+func (c ConfigError) isEmpty() (empty bool) {
+	empty = len(c) == 0
+	return
+}
@@ -0,0 +1,290 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/go-corelibs/slices"
+)
+
+// Config describes a Cross-Origin Resource Sharing policy
+type Config struct {
+	AllowOrigins     Origins
+	AllowMethods     []string
+	AllowHeaders     []string
+	ExposeHeaders    []string
+	AllowCredentials bool
+	MaxAge           int
+
+	// credentialed are the only origins AllowCredentials applies to, when
+	// restricted by Merge
+	credentialed Origins
+	restricted   bool
+}
+
+// IsEmpty returns true if no origins are allowed
+func (c Config) IsEmpty() (empty bool) {
+	empty = len(c.AllowOrigins) == 0
+	return
+}
+
+// Merge returns a new Config with the other Config lists merged with this
+// one and the other non-zero MaxAge takes precedence; AllowCredentials only
+// applies to the origins of the configs which allowed credentials, so origins
+// merged from an uncredentialed config are never credentialed and the merged
+// AllowCredentials is always false when all origins are allowed
+func (c Config) Merge(other Config) (merged Config) {
+	merged = Config{
+		AllowOrigins:     c.AllowOrigins,
+		AllowMethods:     slices.Merge(c.AllowMethods, other.AllowMethods),
+		AllowHeaders:     slices.Merge(c.AllowHeaders, other.AllowHeaders),
+		ExposeHeaders:    slices.Merge(c.ExposeHeaders, other.ExposeHeaders),
+		AllowCredentials: c.AllowCredentials || other.AllowCredentials,
+		MaxAge:           c.MaxAge,
+		credentialed:     append(append(Origins{}, c.credentialedOrigins()...), other.credentialedOrigins()...),
+		restricted:       true,
+	}
+	for _, origin := range other.AllowOrigins {
+		var present bool
+		for _, known := range merged.AllowOrigins {
+			if present = known.String() == origin.String(); present {
+				break
+			}
+		}
+		if !present {
+			merged.AllowOrigins = append(merged.AllowOrigins, origin)
+		}
+	}
+	if other.MaxAge != 0 {
+		merged.MaxAge = other.MaxAge
+	}
+	if merged.AllowOrigins.HasAll() {
+		merged.AllowCredentials = false
+	}
+	return
+}
+
+// credentialedOrigins returns the origins AllowCredentials applies to
+func (c Config) credentialedOrigins() (origins Origins) {
+	if !c.AllowCredentials {
+		return
+	} else if c.restricted {
+		origins = c.credentialed
+		return
+	}
+	origins = c.AllowOrigins
+	return
+}
+
+// Credentialed returns true if AllowCredentials is set for the given origin
+// and the "*" wildcard origin is not present
+func (c Config) Credentialed(origin string) (allowed bool) {
+	if !c.AllowCredentials || c.AllowOrigins.HasAll() {
+		return
+	} else if c.restricted {
+		allowed = c.credentialed.Match(origin)
+		return
+	}
+	allowed = true
+	return
+}
+
+// AllowedOrigin returns the Access-Control-Allow-Origin value for the given
+// origin, which is empty when the origin is not allowed
+func (c Config) AllowedOrigin(origin string) (value string) {
+	if origin == "" || !c.AllowOrigins.Match(origin) {
+		return
+	} else if c.AllowOrigins.HasAll() {
+		// the wildcard is never reflected and is never credentialed
+		value = "*"
+		return
+	}
+	value = origin
+	return
+}
+
+// AllowedMethod returns true if the given method is allowed, simple methods
+// are always allowed
+func (c Config) AllowedMethod(method string) (allowed bool) {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodPost:
+		return true
+	}
+	for _, m := range c.AllowMethods {
+		if allowed = m == "*" || strings.EqualFold(m, method); allowed {
+			return
+		}
+	}
+	return
+}
+
+// AllowedHeaders returns true if all the given headers are allowed
+func (c Config) AllowedHeaders(headers []string) (allowed bool) {
+	for _, header := range headers {
+		var found bool
+		for _, h := range c.AllowHeaders {
+			if found = h == "*" || strings.EqualFold(h, header); found {
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
+
+// ApplyHeaders sets the CORS response headers for an actual (non-preflight)
+// request, returning false if the request origin is not allowed
+func (c Config) ApplyHeaders(w http.ResponseWriter, r *http.Request) (allowed bool) {
+	w.Header().Add("Vary", "Origin")
+	var origin string
+	if origin = c.AllowedOrigin(r.Header.Get("Origin")); origin == "" {
+		return
+	}
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	if c.Credentialed(origin) {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	if len(c.ExposeHeaders) > 0 {
+		w.Header().Set("Access-Control-Expose-Headers", strings.Join(c.ExposeHeaders, ", "))
+	}
+	allowed = true
+	return
+}
+
+// ApplyPreflight sets the CORS response headers for a preflight request,
+// returning false if the origin, method or any of the headers requested are
+// not allowed
+func (c Config) ApplyPreflight(w http.ResponseWriter, r *http.Request) (allowed bool) {
+	w.Header().Add("Vary", "Origin")
+	w.Header().Add("Vary", "Access-Control-Request-Method")
+	w.Header().Add("Vary", "Access-Control-Request-Headers")
+
+	var origin string
+	if origin = c.AllowedOrigin(r.Header.Get("Origin")); origin == "" {
+		return
+	}
+
+	method := r.Header.Get("Access-Control-Request-Method")
+	if !c.AllowedMethod(method) {
+		return
+	}
+
+	var requested []string
+	for _, value := range r.Header.Values("Access-Control-Request-Headers") {
+		for _, header := range strings.Split(value, ",") {
+			if header = strings.TrimSpace(header); header != "" {
+				requested = append(requested, header)
+			}
+		}
+	}
+	if !c.AllowedHeaders(requested) {
+		return
+	}
+
+	w.Header().Set("Access-Control-Allow-Origin", origin)
+	w.Header().Set("Access-Control-Allow-Methods", method)
+	if len(requested) > 0 {
+		w.Header().Set("Access-Control-Allow-Headers", strings.Join(requested, ", "))
+	}
+	if c.Credentialed(origin) {
+		w.Header().Set("Access-Control-Allow-Credentials", "true")
+	}
+	if c.MaxAge > 0 {
+		w.Header().Set("Access-Control-Max-Age", strconv.Itoa(c.MaxAge))
+	}
+	allowed = true
+	return
+}
+
+// ParseConfig parses the given context into a new Config, the context keys
+// are: allow-origins, allow-methods, allow-headers, expose-headers,
+// allow-credentials and max-age; allow-credentials cannot be used with the
+// "*" origin
+func ParseConfig(ctx map[string]interface{}) (config Config, err error) {
+	var cfgErr ConfigError
+
+	parseList := func(key string) (list []string) {
+		switch things := ctx[key].(type) {
+		case string:
+			for _, thing := range strings.Split(things, ",") {
+				if thing = strings.TrimSpace(thing); thing != "" {
+					list = append(list, thing)
+				}
+			}
+		case []interface{}:
+			for idx, thing := range things {
+				if value, ok := thing.(string); ok && strings.TrimSpace(value) != "" {
+					list = append(list, strings.TrimSpace(value))
+				} else {
+					cfgErr = cfgErr.addError(fmt.Sprintf("failed to parse cors.%s[%d]=\"%v\"", key, idx, thing))
+				}
+			}
+		case nil:
+		default:
+			cfgErr = cfgErr.addError(fmt.Sprintf("failed to parse cors.%s=\"%v\"", key, things))
+		}
+		return
+	}
+
+	for idx, value := range parseList("allow-origins") {
+		if origin, ee := ParseOrigin(value); ee != nil {
+			cfgErr = cfgErr.addError(fmt.Sprintf("failed to parse cors.allow-origins[%d]=\"%v\": %v", idx, value, ee))
+		} else {
+			config.AllowOrigins = append(config.AllowOrigins, origin)
+		}
+	}
+
+	for _, method := range parseList("allow-methods") {
+		config.AllowMethods = append(config.AllowMethods, strings.ToUpper(method))
+	}
+	config.AllowHeaders = parseList("allow-headers")
+	config.ExposeHeaders = parseList("expose-headers")
+
+	switch v := ctx["allow-credentials"].(type) {
+	case bool:
+		config.AllowCredentials = v
+	case nil:
+	default:
+		cfgErr = cfgErr.addError(fmt.Sprintf("failed to parse cors.allow-credentials=\"%v\"", v))
+	}
+
+	if config.AllowCredentials && config.AllowOrigins.HasAll() {
+		config.AllowCredentials = false
+		cfgErr = cfgErr.addError(`cors.allow-credentials cannot be used with the "*" allow-origins`)
+	}
+
+	switch v := ctx["max-age"].(type) {
+	case int:
+		config.MaxAge = v
+	case int64:
+		config.MaxAge = int(v)
+	case float64:
+		config.MaxAge = int(v)
+	case nil:
+	default:
+		cfgErr = cfgErr.addError(fmt.Sprintf("failed to parse cors.max-age=\"%v\"", v))
+	}
+
+	if !cfgErr.isEmpty() {
+		err = cfgErr
+	}
+	return
+}
@@ -0,0 +1,123 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package cors
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Origin matches the value of Origin request headers
+type Origin interface {
+	// Match returns true if the given origin is allowed
+	Match(origin string) (matched bool)
+	// String returns the configured form of this Origin
+	String() (value string)
+}
+
+type Origins []Origin
+
+// Match returns true if any of the Origins match the given origin
+func (o Origins) Match(origin string) (matched bool) {
+	for _, item := range o {
+		if matched = item.Match(origin); matched {
+			return
+		}
+	}
+	return
+}
+
+// HasAll returns true if any of the Origins is the "*" wildcard
+func (o Origins) HasAll() (present bool) {
+	for _, item := range o {
+		if present = item.String() == "*"; present {
+			return
+		}
+	}
+	return
+}
+
+// ParseOrigin returns an Origin for the given value, which is one of:
+//
+//   - "*" to match all origins
+//   - "/pattern/" to match entire origins with a regular expression
+//   - a value with one or more "*" wildcards, ie: "https://*.example.com"
+//   - an exact origin, ie: "https://example.com"
+func ParseOrigin(value string) (origin Origin, err error) {
+	value = strings.TrimSpace(value)
+	switch {
+	case value == "":
+		err = fmt.Errorf("empty origin")
+	case value == "*":
+		origin = allOrigin{}
+	case len(value) > 2 && value[0] == '/' && value[len(value)-1] == '/':
+		var rx *regexp.Regexp
+		if rx, err = regexp.Compile(`^(?:` + value[1:len(value)-1] + `)$`); err == nil {
+			origin = &regexpOrigin{value: value, rx: rx}
+		}
+	case strings.Contains(value, "*"):
+		parts := strings.Split(value, "*")
+		for idx, part := range parts {
+			parts[idx] = regexp.QuoteMeta(strings.ToLower(part))
+		}
+		origin = &regexpOrigin{
+			value: value,
+			rx:    regexp.MustCompile(`^` + strings.Join(parts, `[^./]+`) + `$`),
+		}
+	default:
+		origin = exactOrigin(strings.ToLower(strings.TrimSuffix(value, "/")))
+	}
+	return
+}
+
+type allOrigin struct{}
+
+func (o allOrigin) Match(origin string) (matched bool) {
+	matched = origin != ""
+	return
+}
+
+func (o allOrigin) String() (value string) {
+	value = "*"
+	return
+}
+
+type exactOrigin string
+
+func (o exactOrigin) Match(origin string) (matched bool) {
+	matched = strings.ToLower(origin) == string(o)
+	return
+}
+
+func (o exactOrigin) String() (value string) {
+	value = string(o)
+	return
+}
+
+type regexpOrigin struct {
+	value string
+	rx    *regexp.Regexp
+}
+
+func (o *regexpOrigin) Match(origin string) (matched bool) {
+	matched = o.rx.MatchString(strings.ToLower(origin))
+	return
+}
+
+func (o *regexpOrigin) String() (value string) {
+	value = o.value
+	return
+}
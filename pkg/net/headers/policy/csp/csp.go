@@ -51,13 +51,22 @@ const (
 
 var (
 	DefaultReportPathPrefix = "/_/csp-violation"
+	// DefaultReportNonceLifetime is how long report nonces remain valid
+	DefaultReportNonceLifetime = time.Minute
+	// MaxReportBodySize limits the number of bytes read from report requests
+	MaxReportBodySize int64 = 64 * 1024
 )
 
 type ModifyPolicyFn = func(policy Policy, r *http.Request) (modified Policy)
 
+// ReportReceiverFn is called for each violation report received, valid is
+// true when the report nonce has not expired
+type ReportReceiverFn = func(valid bool, contentType string, body []byte, r *http.Request)
+
 type PolicyHandler struct {
 	reportNonces map[string]time.Time
 	requestNonce map[string]string
+	receivers    []ReportReceiverFn
 
 	sync.RWMutex
 }
@@ -97,12 +106,44 @@ func (h *PolicyHandler) PruneReportNonces() {
 	h.Lock()
 	defer h.Unlock()
 	for nonce, stamp := range h.reportNonces {
-		if time.Now().Sub(stamp) >= time.Minute {
+		if time.Now().Sub(stamp) >= DefaultReportNonceLifetime {
 			delete(h.reportNonces, nonce)
 		}
 	}
 }
 
+// AddReportReceiver includes the given fn in the list of receivers called for
+// each violation report, when no receivers are present, reports are logged
+func (h *PolicyHandler) AddReportReceiver(fn ReportReceiverFn) {
+	h.Lock()
+	defer h.Unlock()
+	h.receivers = append(h.receivers, fn)
+}
+
+func (h *PolicyHandler) receiveReport(r *http.Request) {
+	body, _ := io.ReadAll(io.LimitReader(r.Body, MaxReportBodySize))
+	var nonce string
+	if pathLen := len(r.URL.Path); pathLen >= 10 {
+		nonce = r.URL.Path[pathLen-10:]
+	}
+	valid := h.ValidateReportNonce(nonce)
+
+	h.RLock()
+	receivers := append([]ReportReceiverFn{}, h.receivers...)
+	h.RUnlock()
+
+	if len(receivers) > 0 {
+		contentType := r.Header.Get("Content-Type")
+		for _, fn := range receivers {
+			fn(valid, contentType, body, r)
+		}
+	} else if valid {
+		log.WarnF("content-security-policy violation report received:\n%v", string(body))
+	} else {
+		log.WarnF("content-security-policy violation report received [expired]:\n%v", string(body))
+	}
+}
+
 func (h *PolicyHandler) SetRequestPolicy(r *http.Request, policy Policy) (modified *http.Request) {
 	modified = r.Clone(context.WithValue(r.Context(), PolicyTag, policy))
 	return
@@ -153,14 +194,7 @@ func (h *PolicyHandler) PrepareRequestMiddleware(next http.Handler) http.Handler
 		h.PruneReportNonces()
 		if strings.HasPrefix(r.URL.Path, "/_/csp-violation-") {
 			if r.Method == http.MethodPost {
-				body, _ := io.ReadAll(r.Body)
-				pathLen := len(r.URL.Path)
-				nonce := r.URL.Path[pathLen-10:]
-				if h.ValidateReportNonce(nonce) {
-					log.WarnF("content-security-policy violation report received:\n%v", string(body))
-				} else {
-					log.WarnF("content-security-policy violation report received [expired]:\n%v", string(body))
-				}
+				h.receiveReport(r)
 			}
 			serve.Serve204(w, r)
 			return
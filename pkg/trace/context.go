@@ -0,0 +1,147 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"context"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+type spanContextKey struct{}
+
+// ContextWithSpan returns a copy of ctx with the given span as the current
+// span
+func ContextWithSpan(ctx context.Context, s *Span) (modified context.Context) {
+	modified = context.WithValue(ctx, spanContextKey{}, s)
+	return
+}
+
+// SpanFromContext returns the current span of the given context, if any
+func SpanFromContext(ctx context.Context) (s *Span) {
+	if ctx != nil {
+		s, _ = ctx.Value(spanContextKey{}).(*Span)
+	}
+	return
+}
+
+// ContextFromArgv returns the context of the first *http.Request or
+// context.Context found within argv, for use with signal emissions and other
+// variadic hooks, defaulting to context.Background
+func ContextFromArgv(argv ...interface{}) (ctx context.Context) {
+	for _, arg := range argv {
+		switch t := arg.(type) {
+		case *http.Request:
+			if t != nil {
+				return t.Context()
+			}
+		case context.Context:
+			if t != nil {
+				return t
+			}
+		}
+	}
+	ctx = context.Background()
+	return
+}
+
+// Start begins a new internal span as a child of the current span within
+// ctx. When ctx has no current span, a new root span is only started if the
+// provider was configured to include detached spans. Start returns a nil
+// span when tracing is not enabled
+func Start(ctx context.Context, name string, attributes ...interface{}) (modified context.Context, s *Span) {
+	modified = ctx
+	p := current()
+	if p == nil {
+		return
+	}
+	parent := SpanFromContext(ctx)
+	if parent == nil && !p.config.Detached {
+		return
+	}
+	s = p.newSpan(name, SpanKindInternal, parent, nil, attributes...)
+	modified = ContextWithSpan(ctx, s)
+	return
+}
+
+// StartRequest begins a new server span for the given request, continuing
+// any upstream trace given by the W3C traceparent header
+func StartRequest(r *http.Request, name string, attributes ...interface{}) (modified *http.Request, s *Span) {
+	modified = r
+	p := current()
+	if p == nil {
+		return
+	}
+	s = p.newSpan(name, SpanKindServer, nil, extractRemoteParent(r), attributes...)
+	modified = r.WithContext(ContextWithSpan(r.Context(), s))
+	return
+}
+
+// StartRequestChild begins a new internal span as a child of the current
+// span of the given request
+func StartRequestChild(r *http.Request, name string, attributes ...interface{}) (modified *http.Request, s *Span) {
+	modified = r
+	if parent := SpanFromContext(r.Context()); parent != nil {
+		var ctx context.Context
+		if ctx, s = Start(r.Context(), name, attributes...); s != nil {
+			modified = r.WithContext(ctx)
+		}
+	}
+	return
+}
+
+func (p *provider) newSpan(name string, kind SpanKind, parent *Span, remote *remoteParent, attributes ...interface{}) (s *Span) {
+	s = &Span{
+		SpanID:     newSpanID(),
+		Name:       name,
+		Kind:       kind,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]interface{}),
+		provider:   p,
+	}
+	switch {
+	case parent != nil:
+		s.parent = parent
+		s.TraceID = parent.TraceID
+		s.ParentID = parent.SpanID
+		s.sampled = parent.sampled
+		s.state = parent.state
+	case remote != nil:
+		s.TraceID = remote.traceID
+		s.ParentID = remote.spanID
+		s.sampled = remote.sampled || p.sample()
+		s.state = remote.state
+	default:
+		s.TraceID = newTraceID()
+		s.sampled = p.sample()
+	}
+	if s.sampled {
+		setAttributes(s.Attributes, attributes...)
+	}
+	return
+}
+
+func (p *provider) sample() (sampled bool) {
+	switch {
+	case p.config.SampleRatio >= 1.0:
+		sampled = true
+	case p.config.SampleRatio <= 0.0:
+		sampled = false
+	default:
+		sampled = rand.Float64() < p.config.SampleRatio
+	}
+	return
+}
@@ -0,0 +1,66 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+var _ Exporter = (*FileExporter)(nil)
+
+// FileExporter appends each batch of spans to a file as a single line of
+// OTLP/JSON, the same format accepted by OTLP/HTTP collectors and the
+// OpenTelemetry collector "otlpjsonfile" receiver
+type FileExporter struct {
+	path string
+	file *os.File
+
+	sync.Mutex
+}
+
+// NewFileExporter opens (or creates) the given file for appending
+func NewFileExporter(path string) (exporter *FileExporter, err error) {
+	var file *os.File
+	if file, err = os.OpenFile(path, os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0640); err != nil {
+		return
+	}
+	exporter = &FileExporter{
+		path: path,
+		file: file,
+	}
+	return
+}
+
+func (e *FileExporter) ExportSpans(resource Resource, spans []*Span) (err error) {
+	var data []byte
+	if data, err = json.Marshal(makeOtlpRequest(resource, spans)); err != nil {
+		return
+	}
+	e.Lock()
+	defer e.Unlock()
+	_, err = e.file.Write(append(data, '\n'))
+	return
+}
+
+func (e *FileExporter) Shutdown() (err error) {
+	e.Lock()
+	defer e.Unlock()
+	if err = e.file.Sync(); err == nil {
+		err = e.file.Close()
+	}
+	return
+}
@@ -0,0 +1,91 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+)
+
+const (
+	// DefaultOtlpEndpoint is the standard local OTLP/HTTP collector traces
+	// endpoint
+	DefaultOtlpEndpoint = "http://localhost:4318/v1/traces"
+	DefaultOtlpTimeout  = 10 * time.Second
+)
+
+var _ Exporter = (*OtlpExporter)(nil)
+
+// OtlpExporter posts OTLP/JSON encoded spans to an OTLP/HTTP collector
+type OtlpExporter struct {
+	endpoint string
+	headers  map[string]string
+	client   *http.Client
+}
+
+// NewOtlpExporter constructs an OtlpExporter for the given collector
+// endpoint, an empty endpoint uses the DefaultOtlpEndpoint
+func NewOtlpExporter(endpoint string, headers map[string]string) (exporter *OtlpExporter) {
+	if endpoint == "" {
+		endpoint = DefaultOtlpEndpoint
+	}
+	exporter = &OtlpExporter{
+		endpoint: endpoint,
+		headers:  headers,
+		client:   &http.Client{Timeout: DefaultOtlpTimeout},
+	}
+	return
+}
+
+func (e *OtlpExporter) ExportSpans(resource Resource, spans []*Span) (err error) {
+	var data []byte
+	if data, err = json.Marshal(makeOtlpRequest(resource, spans)); err != nil {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), DefaultOtlpTimeout)
+	defer cancel()
+
+	var req *http.Request
+	if req, err = http.NewRequestWithContext(ctx, http.MethodPost, e.endpoint, bytes.NewReader(data)); err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	for k, v := range e.headers {
+		req.Header.Set(k, v)
+	}
+
+	var resp *http.Response
+	if resp, err = e.client.Do(req); err != nil {
+		return
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		err = fmt.Errorf("otlp collector responded with: %v", resp.Status)
+	}
+	return
+}
+
+func (e *OtlpExporter) Shutdown() (err error) {
+	e.client.CloseIdleConnections()
+	return
+}
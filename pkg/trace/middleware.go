@@ -0,0 +1,43 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"net/http"
+)
+
+// Middleware wraps the given middleware function with a span which ends when
+// the middleware either calls the next handler or returns, so that the span
+// duration is the time spent within the middleware itself and consecutive
+// middleware spans are siblings rather than deeply nested
+func Middleware(name string, mw func(next http.Handler) http.Handler) func(next http.Handler) http.Handler {
+	return func(next http.Handler) http.Handler {
+		inner := mw(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if s := SpanFromContext(r.Context()); s != nil && s.Name == name {
+				s.End()
+				r = r.WithContext(ContextWithSpan(r.Context(), s.parent))
+			}
+			next.ServeHTTP(w, r)
+		}))
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if rr, s := StartRequestChild(r, name); s != nil {
+				defer s.End()
+				inner.ServeHTTP(w, rr)
+				return
+			}
+			inner.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,156 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/go-enjin/be/pkg/maps"
+)
+
+// InstrumentationScope is the OTLP scope name for all go-enjin spans
+const InstrumentationScope = "github.com/go-enjin/be"
+
+// the following types are the OTLP/JSON encoding of an
+// ExportTraceServiceRequest, see:
+// https://opentelemetry.io/docs/specs/otlp/#json-protobuf-encoding
+
+type otlpRequest struct {
+	ResourceSpans []otlpResourceSpans `json:"resourceSpans"`
+}
+
+type otlpResourceSpans struct {
+	Resource   otlpResource     `json:"resource"`
+	ScopeSpans []otlpScopeSpans `json:"scopeSpans"`
+}
+
+type otlpResource struct {
+	Attributes []otlpKeyValue `json:"attributes,omitempty"`
+}
+
+type otlpScopeSpans struct {
+	Scope otlpScope  `json:"scope"`
+	Spans []otlpSpan `json:"spans"`
+}
+
+type otlpScope struct {
+	Name string `json:"name"`
+}
+
+type otlpSpan struct {
+	TraceID           string         `json:"traceId"`
+	SpanID            string         `json:"spanId"`
+	TraceState        string         `json:"traceState,omitempty"`
+	ParentSpanID      string         `json:"parentSpanId,omitempty"`
+	Name              string         `json:"name"`
+	Kind              SpanKind       `json:"kind"`
+	StartTimeUnixNano string         `json:"startTimeUnixNano"`
+	EndTimeUnixNano   string         `json:"endTimeUnixNano"`
+	Attributes        []otlpKeyValue `json:"attributes,omitempty"`
+	Status            otlpStatus     `json:"status"`
+}
+
+type otlpStatus struct {
+	Code    StatusCode `json:"code,omitempty"`
+	Message string     `json:"message,omitempty"`
+}
+
+type otlpKeyValue struct {
+	Key   string       `json:"key"`
+	Value otlpAnyValue `json:"value"`
+}
+
+type otlpAnyValue struct {
+	StringValue *string  `json:"stringValue,omitempty"`
+	BoolValue   *bool    `json:"boolValue,omitempty"`
+	IntValue    *string  `json:"intValue,omitempty"`
+	DoubleValue *float64 `json:"doubleValue,omitempty"`
+}
+
+// makeOtlpRequest encodes the given spans as an OTLP/JSON export request
+func makeOtlpRequest(resource Resource, spans []*Span) (request otlpRequest) {
+	resourceAttributes := map[string]interface{}{}
+	for k, v := range resource.Attributes {
+		resourceAttributes[k] = v
+	}
+	if resource.ServiceName != "" {
+		resourceAttributes["service.name"] = resource.ServiceName
+	}
+	if resource.ServiceVersion != "" {
+		resourceAttributes["service.version"] = resource.ServiceVersion
+	}
+
+	encoded := make([]otlpSpan, 0, len(spans))
+	for _, s := range spans {
+		s.Lock()
+		span := otlpSpan{
+			TraceID:           s.TraceID.String(),
+			SpanID:            s.SpanID.String(),
+			TraceState:        s.state,
+			Name:              s.Name,
+			Kind:              s.Kind,
+			StartTimeUnixNano: strconv.FormatInt(s.StartTime.UnixNano(), 10),
+			EndTimeUnixNano:   strconv.FormatInt(s.EndTime.UnixNano(), 10),
+			Attributes:        makeOtlpAttributes(s.Attributes),
+			Status:            otlpStatus{Code: s.StatusCode, Message: s.StatusMessage},
+		}
+		if s.ParentID.Valid() {
+			span.ParentSpanID = s.ParentID.String()
+		}
+		s.Unlock()
+		encoded = append(encoded, span)
+	}
+
+	request.ResourceSpans = []otlpResourceSpans{{
+		Resource: otlpResource{Attributes: makeOtlpAttributes(resourceAttributes)},
+		ScopeSpans: []otlpScopeSpans{{
+			Scope: otlpScope{Name: InstrumentationScope},
+			Spans: encoded,
+		}},
+	}}
+	return
+}
+
+func makeOtlpAttributes(attributes map[string]interface{}) (list []otlpKeyValue) {
+	for _, key := range maps.SortedKeys(attributes) {
+		list = append(list, otlpKeyValue{Key: key, Value: makeOtlpAnyValue(attributes[key])})
+	}
+	return
+}
+
+func makeOtlpAnyValue(v interface{}) (value otlpAnyValue) {
+	switch t := v.(type) {
+	case bool:
+		value.BoolValue = &t
+	case int, int8, int16, int32, int64, uint, uint8, uint16, uint32, uint64:
+		s := fmt.Sprintf("%d", t)
+		value.IntValue = &s
+	case float32:
+		f := float64(t)
+		value.DoubleValue = &f
+	case float64:
+		value.DoubleValue = &t
+	case string:
+		value.StringValue = &t
+	case fmt.Stringer:
+		s := t.String()
+		value.StringValue = &s
+	default:
+		s := fmt.Sprintf("%v", t)
+		value.StringValue = &s
+	}
+	return
+}
@@ -0,0 +1,196 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/go-enjin/be/pkg/log"
+)
+
+const (
+	DefaultBatchSize     = 512
+	DefaultQueueSize     = 4096
+	DefaultFlushInterval = 5 * time.Second
+)
+
+// Exporter is implemented by span output destinations
+type Exporter interface {
+	// ExportSpans delivers the given batch of ended spans
+	ExportSpans(resource Resource, spans []*Span) (err error)
+	// Shutdown releases any exporter resources
+	Shutdown() (err error)
+}
+
+// Resource describes the process producing the spans
+type Resource struct {
+	ServiceName    string
+	ServiceVersion string
+	Attributes     map[string]interface{}
+}
+
+// Config is the tracing provider configuration
+type Config struct {
+	Resource Resource
+	// SampleRatio is the fraction of new traces to record, between 0.0 and
+	// 1.0; upstream sampled traces are always recorded
+	SampleRatio float64
+	// Detached enables starting new root spans outside of request contexts
+	Detached bool
+	// BatchSize is the maximum number of spans exported at once
+	BatchSize int
+	// QueueSize is the number of ended spans buffered before dropping
+	QueueSize int
+	// FlushInterval is the longest duration ended spans wait for export
+	FlushInterval time.Duration
+}
+
+type provider struct {
+	config   Config
+	exporter Exporter
+
+	queue   chan *Span
+	flush   chan chan struct{}
+	done    chan struct{}
+	dropped atomic.Uint64
+}
+
+var (
+	gProvider atomic.Pointer[provider]
+	gMutex    sync.Mutex
+)
+
+func current() (p *provider) {
+	p = gProvider.Load()
+	return
+}
+
+// Enabled returns true when a tracing exporter has been configured
+func Enabled() (enabled bool) {
+	enabled = current() != nil
+	return
+}
+
+// Configure enables tracing with the given exporter, replacing and shutting
+// down any previously configured exporter
+func Configure(exporter Exporter, config Config) {
+	if config.BatchSize <= 0 {
+		config.BatchSize = DefaultBatchSize
+	}
+	if config.QueueSize <= 0 {
+		config.QueueSize = DefaultQueueSize
+	}
+	if config.FlushInterval <= 0 {
+		config.FlushInterval = DefaultFlushInterval
+	}
+	p := &provider{
+		config:   config,
+		exporter: exporter,
+		queue:    make(chan *Span, config.QueueSize),
+		flush:    make(chan chan struct{}),
+		done:     make(chan struct{}),
+	}
+	gMutex.Lock()
+	defer gMutex.Unlock()
+	previous := gProvider.Swap(p)
+	go p.run()
+	if previous != nil {
+		previous.shutdown()
+	}
+}
+
+// Flush blocks until all ended spans have been exported
+func Flush() {
+	if p := current(); p != nil {
+		ack := make(chan struct{})
+		select {
+		case p.flush <- ack:
+			<-ack
+		case <-p.done:
+		}
+	}
+}
+
+// Shutdown disables tracing, exporting any remaining spans first
+func Shutdown() {
+	gMutex.Lock()
+	defer gMutex.Unlock()
+	if p := gProvider.Swap(nil); p != nil {
+		p.shutdown()
+	}
+}
+
+func (p *provider) enqueue(s *Span) {
+	select {
+	case p.queue <- s:
+	default:
+		if dropped := p.dropped.Add(1); dropped == 1 || dropped%1000 == 0 {
+			log.WarnF("trace span queue full, %d spans dropped", dropped)
+		}
+	}
+}
+
+func (p *provider) shutdown() {
+	ack := make(chan struct{})
+	p.flush <- ack
+	<-ack
+	close(p.done)
+	if err := p.exporter.Shutdown(); err != nil {
+		log.ErrorF("error shutting down trace exporter: %v", err)
+	}
+}
+
+func (p *provider) run() {
+	ticker := time.NewTicker(p.config.FlushInterval)
+	defer ticker.Stop()
+
+	batch := make([]*Span, 0, p.config.BatchSize)
+	export := func() {
+		if len(batch) > 0 {
+			if err := p.exporter.ExportSpans(p.config.Resource, batch); err != nil {
+				log.ErrorF("error exporting %d trace spans: %v", len(batch), err)
+			}
+			batch = make([]*Span, 0, p.config.BatchSize)
+		}
+	}
+
+	for {
+		select {
+		case <-p.done:
+			return
+		case s := <-p.queue:
+			if batch = append(batch, s); len(batch) >= p.config.BatchSize {
+				export()
+			}
+		case <-ticker.C:
+			export()
+		case ack := <-p.flush:
+			for drained := false; !drained; {
+				select {
+				case s := <-p.queue:
+					if batch = append(batch, s); len(batch) >= p.config.BatchSize {
+						export()
+					}
+				default:
+					drained = true
+				}
+			}
+			export()
+			close(ack)
+		}
+	}
+}
@@ -0,0 +1,176 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// TraceID is a W3C trace-id
+type TraceID [16]byte
+
+// SpanID is a W3C parent-id
+type SpanID [8]byte
+
+// SpanKind values match the OpenTelemetry protocol enumeration
+type SpanKind uint8
+
+const (
+	SpanKindUnspecified SpanKind = iota
+	SpanKindInternal
+	SpanKindServer
+	SpanKindClient
+)
+
+// StatusCode values match the OpenTelemetry protocol enumeration
+type StatusCode uint8
+
+const (
+	StatusUnset StatusCode = iota
+	StatusOk
+	StatusError
+)
+
+func (t TraceID) Valid() (valid bool) {
+	valid = t != TraceID{}
+	return
+}
+
+func (t TraceID) String() (hexed string) {
+	hexed = hex.EncodeToString(t[:])
+	return
+}
+
+func (s SpanID) Valid() (valid bool) {
+	valid = s != SpanID{}
+	return
+}
+
+func (s SpanID) String() (hexed string) {
+	hexed = hex.EncodeToString(s[:])
+	return
+}
+
+func newTraceID() (id TraceID) {
+	for !id.Valid() {
+		_, _ = rand.Read(id[:])
+	}
+	return
+}
+
+func newSpanID() (id SpanID) {
+	for !id.Valid() {
+		_, _ = rand.Read(id[:])
+	}
+	return
+}
+
+// Span is a single timed operation within a trace, all methods are safe to
+// call on a nil Span so that callers need not check if tracing is enabled
+type Span struct {
+	TraceID       TraceID
+	SpanID        SpanID
+	ParentID      SpanID
+	Name          string
+	Kind          SpanKind
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]interface{}
+	StatusCode    StatusCode
+	StatusMessage string
+
+	sampled  bool
+	state    string
+	parent   *Span
+	provider *provider
+
+	sync.Mutex
+}
+
+// Sampled returns true if the span will be exported when ended
+func (s *Span) Sampled() (sampled bool) {
+	if s != nil {
+		sampled = s.sampled
+	}
+	return
+}
+
+// SetAttributes records the given key/value pairs on the span, keys must be
+// strings and values are typically strings, bools, ints or floats
+func (s *Span) SetAttributes(pairs ...interface{}) {
+	if s == nil || !s.sampled {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	setAttributes(s.Attributes, pairs...)
+}
+
+// SetError marks the span as failed with the given error, nil errors are
+// ignored
+func (s *Span) SetError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	s.StatusCode = StatusError
+	s.StatusMessage = err.Error()
+}
+
+// SetOk marks the span as having completed successfully
+func (s *Span) SetOk() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	defer s.Unlock()
+	if s.StatusCode == StatusUnset {
+		s.StatusCode = StatusOk
+	}
+}
+
+// End records the end time of the span and queues it for export, calling
+// End more than once has no effect
+func (s *Span) End() {
+	if s == nil {
+		return
+	}
+	s.Lock()
+	if !s.EndTime.IsZero() {
+		s.Unlock()
+		return
+	}
+	s.EndTime = time.Now()
+	s.Unlock()
+	if s.sampled && s.provider != nil {
+		s.provider.enqueue(s)
+	}
+}
+
+func setAttributes(attributes map[string]interface{}, pairs ...interface{}) {
+	for idx := 0; idx+1 < len(pairs); idx += 2 {
+		switch key := pairs[idx].(type) {
+		case string:
+			attributes[key] = pairs[idx+1]
+		default:
+			attributes[fmt.Sprintf("%v", key)] = pairs[idx+1]
+		}
+	}
+}
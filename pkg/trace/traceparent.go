@@ -0,0 +1,111 @@
+// Copyright (c) 2024  The Go-Enjin Authors
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package trace
+
+import (
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+const (
+	// TraceParentHeader is the W3C Trace Context request header
+	TraceParentHeader = "traceparent"
+	// TraceStateHeader is the W3C Trace Context vendor state header
+	TraceStateHeader = "tracestate"
+)
+
+const flagSampled byte = 0x01
+
+// remoteParent is the span context received from an upstream service
+type remoteParent struct {
+	traceID TraceID
+	spanID  SpanID
+	sampled bool
+	state   string
+}
+
+// ParseTraceParent parses a version 00 W3C traceparent header value
+func ParseTraceParent(value string) (traceID TraceID, spanID SpanID, sampled bool, err error) {
+	parts := strings.Split(strings.TrimSpace(value), "-")
+	if len(parts) < 4 {
+		err = fmt.Errorf("invalid traceparent: %q", value)
+		return
+	} else if len(parts[0]) != 2 || parts[0] == "ff" {
+		err = fmt.Errorf("unsupported traceparent version: %q", parts[0])
+		return
+	} else if parts[0] == "00" && len(parts) != 4 {
+		err = fmt.Errorf("invalid traceparent: %q", value)
+		return
+	}
+
+	var flags []byte
+	if len(parts[1]) != 32 || len(parts[2]) != 16 || len(parts[3]) != 2 {
+		err = fmt.Errorf("invalid traceparent field lengths: %q", value)
+		return
+	} else if _, err = hex.Decode(traceID[:], []byte(parts[1])); err != nil {
+		return
+	} else if _, err = hex.Decode(spanID[:], []byte(parts[2])); err != nil {
+		return
+	} else if flags, err = hex.DecodeString(parts[3]); err != nil {
+		return
+	} else if !traceID.Valid() || !spanID.Valid() {
+		err = fmt.Errorf("invalid traceparent identifiers: %q", value)
+		return
+	}
+
+	sampled = flags[0]&flagSampled == flagSampled
+	return
+}
+
+// FormatTraceParent returns the version 00 W3C traceparent header value for
+// the given span
+func FormatTraceParent(s *Span) (value string) {
+	if s == nil {
+		return
+	}
+	var flags byte
+	if s.sampled {
+		flags = flagSampled
+	}
+	value = fmt.Sprintf("00-%s-%s-%02x", s.TraceID, s.SpanID, flags)
+	return
+}
+
+// Inject sets the traceparent (and any received tracestate) headers for the
+// span found within the request context, for use with outgoing requests
+func Inject(r *http.Request) {
+	if s := SpanFromContext(r.Context()); s != nil {
+		r.Header.Set(TraceParentHeader, FormatTraceParent(s))
+		if s.state != "" {
+			r.Header.Set(TraceStateHeader, s.state)
+		}
+	}
+}
+
+func extractRemoteParent(r *http.Request) (parent *remoteParent) {
+	if value := r.Header.Get(TraceParentHeader); value != "" {
+		if traceID, spanID, sampled, err := ParseTraceParent(value); err == nil {
+			parent = &remoteParent{
+				traceID: traceID,
+				spanID:  spanID,
+				sampled: sampled,
+				state:   r.Header.Get(TraceStateHeader),
+			}
+		}
+	}
+	return
+}
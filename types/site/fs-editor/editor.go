@@ -143,10 +143,18 @@ func (f *CEditorFeature[MakeTypedFeature]) GetEditorKey() (name string) {
 	return f.EditorKey
 }
 
+func (f *CEditorFeature[MakeTypedFeature]) GetEditorType() (editorType string) {
+	return f.EditorType
+}
+
 func (f *CEditorFeature[MakeTypedFeature]) GetEditorPath() (path string) {
 	return f.Editor.SiteFeaturePath() + "/" + f.EditorKey
 }
 
+func (f *CEditorFeature[MakeTypedFeature]) GetFileOperations() (ops map[string]*feature.EditorOperation) {
+	return f.FileOperations
+}
+
 func (f *CEditorFeature[MakeTypedFeature]) GetEditorMenu() (m menu.Menu) {
 	return nil
 }